@@ -0,0 +1,700 @@
+package fakeserver
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handlers return false when the method is not supported so the router can
+// answer with a 404, matching how Rails treats unknown routes.
+
+func (s *Server) identity(r *request) {
+	if r.Method != http.MethodGet {
+		r.error(http.StatusNotFound, "Not found")
+		return
+	}
+	st := s.store
+	r.json(http.StatusOK, map[string]interface{}{
+		"accounts": []interface{}{
+			map[string]interface{}{
+				"id":         st.accountID,
+				"name":       "Fake Account",
+				"slug":       "/" + s.Account,
+				"created_at": timestamp(st.users[0].CreatedAt),
+				"user":       r.userJSON(st.findUser(st.me)),
+			},
+		},
+	})
+}
+
+func (s *Server) boards(r *request) bool {
+	st := s.store
+	switch r.Method {
+	case http.MethodGet:
+		items := make([]interface{}, 0, len(st.boards))
+		for _, b := range st.boards {
+			items = append(items, r.boardJSON(st, b))
+		}
+		s.paginate(r, items)
+	case http.MethodPost:
+		name, _ := r.stringParam("board", "name")
+		if name == "" {
+			r.error(http.StatusUnprocessableEntity, "Name can't be blank")
+			return true
+		}
+		b := &board{ID: st.newID(), Name: name, AllAccess: true, CreatorID: st.me, CreatedAt: time.Now()}
+		if v, ok := r.boolParam("board", "all_access"); ok {
+			b.AllAccess = v
+		}
+		if v, ok := r.stringParam("board", "auto_postpone_period"); ok {
+			b.AutoPostponePeriod, _ = strconv.Atoi(v)
+		}
+		st.boards = append(st.boards, b)
+		r.created(r.accountPath("/boards/" + b.ID + ".json"))
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) board(r *request, id string) bool {
+	st := s.store
+	b := st.findBoard(id)
+	if b == nil {
+		r.error(http.StatusNotFound, "Board not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		r.json(http.StatusOK, r.boardJSON(st, b))
+	case http.MethodPatch, http.MethodPut:
+		if v, ok := r.stringParam("board", "name"); ok && v != "" {
+			b.Name = v
+		}
+		if v, ok := r.boolParam("board", "all_access"); ok {
+			b.AllAccess = v
+		}
+		if v, ok := r.stringParam("board", "auto_postpone_period"); ok {
+			b.AutoPostponePeriod, _ = strconv.Atoi(v)
+		}
+		r.noContent()
+	case http.MethodDelete:
+		st.deleteBoard(b)
+		r.noContent()
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) columns(r *request, boardID string) bool {
+	st := s.store
+	b := st.findBoard(boardID)
+	if b == nil {
+		r.error(http.StatusNotFound, "Board not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		items := []interface{}{}
+		for _, c := range st.columns {
+			if c.BoardID == b.ID {
+				items = append(items, r.columnJSON(c))
+			}
+		}
+		r.json(http.StatusOK, items)
+	case http.MethodPost:
+		name, _ := r.stringParam("column", "name")
+		if name == "" {
+			r.error(http.StatusUnprocessableEntity, "Name can't be blank")
+			return true
+		}
+		c := &column{ID: st.newID(), BoardID: b.ID, Name: name, CreatedAt: time.Now()}
+		c.Color, _ = r.stringParam("column", "color")
+		st.columns = append(st.columns, c)
+		r.created(r.accountPath("/boards/" + b.ID + "/columns/" + c.ID + ".json"))
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) column(r *request, boardID, id string) bool {
+	st := s.store
+	c := st.findColumn(boardID, id)
+	if c == nil {
+		r.error(http.StatusNotFound, "Column not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		r.json(http.StatusOK, r.columnJSON(c))
+	case http.MethodPatch, http.MethodPut:
+		if v, ok := r.stringParam("column", "name"); ok && v != "" {
+			c.Name = v
+		}
+		if v, ok := r.stringParam("column", "color"); ok {
+			c.Color = v
+		}
+		r.noContent()
+	case http.MethodDelete:
+		st.columns = remove(st.columns, c)
+		for _, card := range st.cards {
+			if card.ColumnID == c.ID {
+				card.ColumnID = ""
+			}
+		}
+		r.noContent()
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) cards(r *request) bool {
+	st := s.store
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		matches := []*card{}
+		for _, c := range st.cards {
+			if cardMatches(c, q) {
+				matches = append(matches, c)
+			}
+		}
+		// Most recently active first, like the API's default ordering.
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].LastActiveAt.After(matches[j].LastActiveAt)
+		})
+		items := make([]interface{}, 0, len(matches))
+		for _, c := range matches {
+			items = append(items, r.cardJSON(st, c, false))
+		}
+		s.paginate(r, items)
+	case http.MethodPost:
+		boardID, _ := r.stringParam("board_id")
+		b := st.findBoard(boardID)
+		if b == nil {
+			r.error(http.StatusNotFound, "Board not found")
+			return true
+		}
+		title, _ := r.stringParam("card", "title")
+		now := time.Now()
+		st.cardSeq++
+		c := &card{
+			ID:           st.newID(),
+			Number:       st.cardSeq,
+			BoardID:      b.ID,
+			Title:        title,
+			CreatorID:    st.me,
+			CreatedAt:    now,
+			LastActiveAt: now,
+		}
+		if v, ok := r.stringParam("card", "description"); ok {
+			c.DescriptionHTML = actionText(v)
+		}
+		if v, ok := r.stringParam("card", "image"); ok {
+			c.Image = v
+		}
+		if v, ok := r.stringParam("card", "created_at"); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				c.CreatedAt = t
+			}
+		}
+		if v, ok := r.stringParam("card", "tag_ids"); ok {
+			for _, id := range strings.Split(v, ",") {
+				if t := st.findTag(strings.TrimSpace(id)); t != nil {
+					c.TagIDs = append(c.TagIDs, t.ID)
+				}
+			}
+		}
+		st.cards = append(st.cards, c)
+		r.created(r.accountPath(fmt.Sprintf("/cards/%d.json", c.Number)))
+	default:
+		return false
+	}
+	return true
+}
+
+// cardMatches applies the card list filters the CLI sends.
+func cardMatches(c *card, q map[string][]string) bool {
+	if ids := q["board_ids[]"]; len(ids) > 0 && !contains(ids, c.BoardID) {
+		return false
+	}
+	if ids := q["tag_ids[]"]; len(ids) > 0 && !overlaps(ids, c.TagIDs) {
+		return false
+	}
+	if ids := q["assignee_ids[]"]; len(ids) > 0 && !overlaps(ids, c.AssigneeIDs) {
+		return false
+	}
+	for _, term := range q["terms[]"] {
+		if !strings.Contains(strings.ToLower(c.Title+" "+plainText(c.DescriptionHTML)), strings.ToLower(term)) {
+			return false
+		}
+	}
+
+	indexedBy := ""
+	if v := q["indexed_by"]; len(v) > 0 {
+		indexedBy = v[0]
+	}
+	switch indexedBy {
+	case "all":
+		return true
+	case "closed":
+		return c.Closed
+	case "not_now":
+		return c.Postponed && !c.Closed
+	case "golden":
+		return c.Golden && !c.Closed
+	}
+	return !c.Closed && !c.Postponed
+}
+
+func (s *Server) card(r *request, number string) bool {
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		r.json(http.StatusOK, r.cardJSON(st, c, true))
+	case http.MethodPatch, http.MethodPut:
+		if v, ok := r.stringParam("card", "title"); ok && v != "" {
+			c.Title = v
+		}
+		if v, ok := r.stringParam("card", "description"); ok {
+			c.DescriptionHTML = actionText(v)
+		}
+		if v, ok := r.stringParam("card", "image"); ok {
+			c.Image = v
+		}
+		if v, ok := r.stringParam("card", "created_at"); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				c.CreatedAt = t
+			}
+		}
+		c.LastActiveAt = time.Now()
+		r.json(http.StatusOK, r.cardJSON(st, c, true))
+	case http.MethodDelete:
+		st.deleteCard(c)
+		r.noContent()
+	default:
+		return false
+	}
+	return true
+}
+
+// cardAction handles the card sub-resources and the comment and step
+// collections, which share the /cards/:number/:name shape.
+func (s *Server) cardAction(r *request, number, action string) bool {
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+
+	switch action + " " + r.Method {
+	case "comments GET":
+		items := []interface{}{}
+		for _, cm := range st.comments {
+			if cm.CardNumber == c.Number {
+				items = append(items, r.commentJSON(st, cm))
+			}
+		}
+		s.paginate(r, items)
+		return true
+	case "comments POST":
+		body, _ := r.stringParam("comment", "body")
+		if body == "" {
+			r.error(http.StatusUnprocessableEntity, "Body can't be blank")
+			return true
+		}
+		now := time.Now()
+		cm := &comment{ID: st.newID(), CardNumber: c.Number, BodyHTML: actionText(body), CreatorID: st.me, CreatedAt: now, UpdatedAt: now}
+		if v, ok := r.stringParam("comment", "created_at"); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				cm.CreatedAt = t
+			}
+		}
+		st.comments = append(st.comments, cm)
+		c.LastActiveAt = now
+		r.created(r.accountPath(fmt.Sprintf("/cards/%d/comments/%s.json", c.Number, cm.ID)))
+		return true
+	case "steps POST":
+		content, _ := r.stringParam("step", "content")
+		if content == "" {
+			r.error(http.StatusUnprocessableEntity, "Content can't be blank")
+			return true
+		}
+		sp := &step{ID: st.newID(), CardNumber: c.Number, Content: content}
+		sp.Completed, _ = r.boolParam("step", "completed")
+		st.steps = append(st.steps, sp)
+		r.created(r.accountPath(fmt.Sprintf("/cards/%d/steps/%s.json", c.Number, sp.ID)))
+		return true
+	case "triage POST":
+		columnID, _ := r.stringParam("column_id")
+		col := st.findColumn(c.BoardID, columnID)
+		if col == nil {
+			r.error(http.StatusNotFound, "Column not found")
+			return true
+		}
+		c.ColumnID = col.ID
+		c.Postponed = false
+	case "triage DELETE":
+		c.ColumnID = ""
+		c.Postponed = false
+	case "closure POST":
+		c.Closed = true
+	case "closure DELETE":
+		c.Closed = false
+	case "not_now POST":
+		c.Postponed = true
+		c.ColumnID = ""
+	case "taggings POST":
+		title, _ := r.stringParam("tag_title")
+		title = strings.TrimPrefix(strings.TrimSpace(title), "#")
+		if title == "" {
+			r.error(http.StatusUnprocessableEntity, "Tag title can't be blank")
+			return true
+		}
+		t := st.findTagByTitle(title)
+		if t == nil {
+			t = &tag{ID: st.newID(), Title: title, CreatedAt: time.Now()}
+			st.tags = append(st.tags, t)
+		}
+		c.TagIDs = toggle(c.TagIDs, t.ID)
+	case "assignments POST":
+		userID, _ := r.stringParam("assignee_id")
+		if st.findUser(userID) == nil {
+			r.error(http.StatusNotFound, "User not found")
+			return true
+		}
+		c.AssigneeIDs = toggle(c.AssigneeIDs, userID)
+	case "watch POST":
+		c.Watching = true
+	case "watch DELETE":
+		c.Watching = false
+	default:
+		return false
+	}
+
+	c.LastActiveAt = time.Now()
+	r.noContent()
+	return true
+}
+
+func (s *Server) comment(r *request, number, id string) bool {
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+	cm := st.findComment(c.Number, id)
+	if cm == nil {
+		r.error(http.StatusNotFound, "Comment not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		r.json(http.StatusOK, r.commentJSON(st, cm))
+	case http.MethodPatch, http.MethodPut:
+		if v, ok := r.stringParam("comment", "body"); ok && v != "" {
+			cm.BodyHTML = actionText(v)
+		}
+		cm.UpdatedAt = time.Now()
+		r.json(http.StatusOK, r.commentJSON(st, cm))
+	case http.MethodDelete:
+		st.deleteComment(cm)
+		r.noContent()
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) reactions(r *request, number, commentID string) bool {
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+	cm := st.findComment(c.Number, commentID)
+	if cm == nil {
+		r.error(http.StatusNotFound, "Comment not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		items := []interface{}{}
+		for _, re := range st.reactions {
+			if re.CommentID == cm.ID {
+				items = append(items, r.reactionJSON(st, re))
+			}
+		}
+		r.json(http.StatusOK, items)
+	case http.MethodPost:
+		content, _ := r.stringParam("content")
+		if content == "" {
+			r.error(http.StatusUnprocessableEntity, "Content can't be blank")
+			return true
+		}
+		st.reactions = append(st.reactions, &reaction{ID: st.newID(), CommentID: cm.ID, Content: content, ReacterID: st.me})
+		r.w.WriteHeader(http.StatusCreated)
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) reaction(r *request, number, commentID, id string) bool {
+	if r.Method != http.MethodDelete {
+		return false
+	}
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+	cm := st.findComment(c.Number, commentID)
+	if cm == nil {
+		r.error(http.StatusNotFound, "Comment not found")
+		return true
+	}
+	for _, re := range st.reactions {
+		if re.ID == id && re.CommentID == cm.ID {
+			st.reactions = remove(st.reactions, re)
+			r.noContent()
+			return true
+		}
+	}
+	r.error(http.StatusNotFound, "Reaction not found")
+	return true
+}
+
+func (s *Server) step(r *request, number, id string) bool {
+	st := s.store
+	c := st.findCard(number)
+	if c == nil {
+		r.error(http.StatusNotFound, "Card not found")
+		return true
+	}
+	sp := st.findStep(c.Number, id)
+	if sp == nil {
+		r.error(http.StatusNotFound, "Step not found")
+		return true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		r.json(http.StatusOK, stepJSON(sp))
+	case http.MethodPatch, http.MethodPut:
+		if v, ok := r.stringParam("step", "content"); ok && v != "" {
+			sp.Content = v
+		}
+		if v, ok := r.boolParam("step", "completed"); ok {
+			sp.Completed = v
+		}
+		r.json(http.StatusOK, stepJSON(sp))
+	case http.MethodDelete:
+		st.steps = remove(st.steps, sp)
+		r.noContent()
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *Server) tags(r *request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	items := make([]interface{}, 0, len(s.store.tags))
+	for _, t := range s.store.tags {
+		items = append(items, r.tagJSON(t))
+	}
+	s.paginate(r, items)
+	return true
+}
+
+func (s *Server) users(r *request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	items := make([]interface{}, 0, len(s.store.users))
+	for _, u := range s.store.users {
+		items = append(items, r.userJSON(u))
+	}
+	s.paginate(r, items)
+	return true
+}
+
+func (s *Server) user(r *request, id string) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	u := s.store.findUser(id)
+	if u == nil {
+		r.error(http.StatusNotFound, "User not found")
+		return true
+	}
+	r.json(http.StatusOK, r.userJSON(u))
+	return true
+}
+
+func (s *Server) notifications(r *request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	st := s.store
+	items := make([]interface{}, 0, len(st.notices))
+	// Newest first.
+	for i := len(st.notices) - 1; i >= 0; i-- {
+		items = append(items, r.notificationJSON(st, st.notices[i]))
+	}
+	s.paginate(r, items)
+	return true
+}
+
+func (s *Server) bulkReading(r *request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	now := time.Now()
+	for _, n := range s.store.notices {
+		if !n.Read {
+			n.Read = true
+			n.ReadAt = now
+		}
+	}
+	r.noContent()
+	return true
+}
+
+func (s *Server) notificationAction(r *request, id, action string) bool {
+	if r.Method != http.MethodPost || (action != "read" && action != "unread") {
+		return false
+	}
+	n := s.store.findNotification(id)
+	if n == nil {
+		r.error(http.StatusNotFound, "Notification not found")
+		return true
+	}
+	n.Read = action == "read"
+	n.ReadAt = time.Now()
+	r.noContent()
+	return true
+}
+
+// directUpload creates a blob and returns the signed URL the client should
+// PUT the file to, like Active Storage's direct uploads controller.
+func (s *Server) directUpload(r *request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	st := s.store
+	b := &blob{Key: st.newID()}
+	b.Filename, _ = r.stringParam("blob", "filename")
+	b.ContentType, _ = r.stringParam("blob", "content_type")
+	b.Checksum, _ = r.stringParam("blob", "checksum")
+	size, _ := r.stringParam("blob", "byte_size")
+	b.ByteSize, _ = strconv.ParseInt(size, 10, 64)
+	if b.Filename == "" || b.Checksum == "" {
+		r.error(http.StatusUnprocessableEntity, "Filename and checksum are required")
+		return true
+	}
+	st.blobs[b.Key] = b
+
+	r.json(http.StatusOK, map[string]interface{}{
+		"id":              b.Key,
+		"key":             b.Key,
+		"filename":        b.Filename,
+		"content_type":    b.ContentType,
+		"byte_size":       b.ByteSize,
+		"checksum":        b.Checksum,
+		"signed_id":       "signed-" + b.Key,
+		"attachable_sgid": "sgid-" + b.Key,
+		"direct_upload": map[string]interface{}{
+			"url": r.url("/rails/active_storage/disk/" + b.Key),
+			"headers": map[string]string{
+				"Content-Type": b.ContentType,
+				"Content-MD5":  b.Checksum,
+			},
+		},
+	})
+	return true
+}
+
+// putBlob stores uploaded bytes after checking them against the checksum
+// given when the upload was created.
+func (s *Server) putBlob(r *request, key string) {
+	if r.Method != http.MethodPut {
+		r.error(http.StatusNotFound, "Not found")
+		return
+	}
+	b, ok := s.store.blobs[key]
+	if !ok {
+		r.error(http.StatusNotFound, "Blob not found")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		r.error(http.StatusBadRequest, err.Error())
+		return
+	}
+	sum := md5.Sum(data)
+	if base64.StdEncoding.EncodeToString(sum[:]) != b.Checksum {
+		r.error(http.StatusUnprocessableEntity, "Checksum mismatch")
+		return
+	}
+	b.Data = data
+	b.Uploaded = true
+	r.noContent()
+}
+
+// getBlob serves an uploaded file. The path is the blob key or signed ID,
+// optionally followed by the filename.
+func (s *Server) getBlob(r *request, path string) {
+	if r.Method != http.MethodGet {
+		r.error(http.StatusNotFound, "Not found")
+		return
+	}
+	key, _, _ := strings.Cut(path, "/")
+	b, ok := s.store.blobs[strings.TrimPrefix(key, "signed-")]
+	if !ok || !b.Uploaded {
+		r.error(http.StatusNotFound, "Blob not found")
+		return
+	}
+	r.w.Header().Set("Content-Type", b.ContentType)
+	r.w.WriteHeader(http.StatusOK)
+	r.w.Write(b.Data)
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
+
+func overlaps(a, b []string) bool {
+	for _, it := range a {
+		if contains(b, it) {
+			return true
+		}
+	}
+	return false
+}
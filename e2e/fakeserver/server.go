@@ -0,0 +1,322 @@
+// Package fakeserver implements an in-memory fake of the Fizzy API so the e2e
+// suite can run without a live account.
+//
+// It covers the endpoints the CLI uses: boards, columns, cards and their
+// triage/closure/not_now/taggings/assignments/watch sub-resources, comments,
+// reactions, steps, tags, users, notifications, identity and Active Storage
+// direct uploads. List endpoints are paginated with Link headers and create
+// endpoints return Location headers, like the real API.
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default credentials accepted by a new server.
+const (
+	DefaultToken   = "fake-token"
+	DefaultAccount = "897362094"
+)
+
+// DefaultPageSize is the number of items returned per page by list endpoints.
+const DefaultPageSize = 25
+
+// Server is a running fake Fizzy API.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:54321.
+	URL string
+
+	// Token is the only bearer token the server accepts.
+	Token string
+
+	// Account is the account slug that prefixes every account-scoped path.
+	Account string
+
+	// PageSize is the number of items per page for list endpoints.
+	PageSize int
+
+	httpServer *httptest.Server
+
+	mu    sync.Mutex
+	store *store
+}
+
+// New starts a fake server on a random local port with seed data.
+func New() *Server {
+	s := NewUnstarted()
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// NewUnstarted returns a fake server that is not listening. Use it as an
+// http.Handler, e.g. with httptest.NewServer.
+func NewUnstarted() *Server {
+	return &Server{
+		Token:    DefaultToken,
+		Account:  DefaultAccount,
+		PageSize: DefaultPageSize,
+		store:    newStore(),
+	}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	}
+}
+
+// CurrentUserID returns the ID of the user the token authenticates as.
+func (s *Server) CurrentUserID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.me
+}
+
+// AddNotification adds an unread notification about a card and returns its ID.
+func (s *Server) AddNotification(cardNumber int, title, body string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.addNotification(cardNumber, title, body)
+}
+
+// request is the per-request context passed to handlers.
+type request struct {
+	*http.Request
+	w       http.ResponseWriter
+	base    string
+	account string
+	segs    []string
+	payload map[string]interface{}
+}
+
+// ServeHTTP routes a request to the matching handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req := &request{Request: r, w: w, base: "http://" + r.Host, account: s.Account}
+
+	path := strings.TrimSuffix(r.URL.Path, ".json")
+
+	// Direct upload targets are signed URLs and do not need the token.
+	if strings.HasPrefix(path, "/rails/active_storage/disk/") {
+		s.putBlob(req, strings.TrimPrefix(path, "/rails/active_storage/disk/"))
+		return
+	}
+	// So are blob URLs, which serve uploaded files.
+	if strings.HasPrefix(r.URL.Path, "/rails/active_storage/blobs/") {
+		s.getBlob(req, strings.TrimPrefix(r.URL.Path, "/rails/active_storage/blobs/"))
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		req.error(http.StatusUnauthorized, "Invalid access token")
+		return
+	}
+
+	if r.Body != nil && r.ContentLength != 0 {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+			req.payload = payload
+		}
+	}
+
+	if path == "/my/identity" {
+		s.identity(req)
+		return
+	}
+
+	prefix := "/" + s.Account + "/"
+	if !strings.HasPrefix(path, prefix) {
+		req.error(http.StatusNotFound, "Account not found")
+		return
+	}
+	req.segs = strings.Split(strings.TrimPrefix(path, prefix), "/")
+
+	if !s.route(req) {
+		req.error(http.StatusNotFound, "Not found")
+	}
+}
+
+// route dispatches on the path segments after the account slug and reports
+// whether a handler matched.
+func (s *Server) route(r *request) bool {
+	segs := r.segs
+	switch {
+	case match(segs, "boards"):
+		return s.boards(r)
+	case match(segs, "boards", "*"):
+		return s.board(r, segs[1])
+	case match(segs, "boards", "*", "columns"):
+		return s.columns(r, segs[1])
+	case match(segs, "boards", "*", "columns", "*"):
+		return s.column(r, segs[1], segs[3])
+	case match(segs, "cards"):
+		return s.cards(r)
+	case match(segs, "cards", "*"):
+		return s.card(r, segs[1])
+	case match(segs, "cards", "*", "*"):
+		return s.cardAction(r, segs[1], segs[2])
+	case match(segs, "cards", "*", "comments", "*"):
+		return s.comment(r, segs[1], segs[3])
+	case match(segs, "cards", "*", "comments", "*", "reactions"):
+		return s.reactions(r, segs[1], segs[3])
+	case match(segs, "cards", "*", "comments", "*", "reactions", "*"):
+		return s.reaction(r, segs[1], segs[3], segs[5])
+	case match(segs, "cards", "*", "steps", "*"):
+		return s.step(r, segs[1], segs[3])
+	case match(segs, "tags"):
+		return s.tags(r)
+	case match(segs, "users"):
+		return s.users(r)
+	case match(segs, "users", "*"):
+		return s.user(r, segs[1])
+	case match(segs, "notifications"):
+		return s.notifications(r)
+	case match(segs, "notifications", "bulk_reading"):
+		return s.bulkReading(r)
+	case match(segs, "notifications", "*", "*"):
+		return s.notificationAction(r, segs[1], segs[2])
+	case match(segs, "rails", "active_storage", "direct_uploads"):
+		return s.directUpload(r)
+	}
+	return false
+}
+
+// match reports whether segs matches pattern, where "*" matches any segment.
+func match(segs []string, pattern ...string) bool {
+	if len(segs) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// json writes a JSON response.
+func (r *request) json(status int, v interface{}) {
+	r.w.Header().Set("Content-Type", "application/json")
+	r.w.WriteHeader(status)
+	json.NewEncoder(r.w).Encode(v)
+}
+
+// error writes a JSON error response in the shape the API uses.
+func (r *request) error(status int, message string) {
+	r.json(status, map[string]string{"error": message})
+}
+
+// created writes a 201 response with a Location header for path.
+func (r *request) created(path string) {
+	r.w.Header().Set("Location", r.url(path))
+	r.w.WriteHeader(http.StatusCreated)
+}
+
+// noContent writes a 204 response.
+func (r *request) noContent() {
+	r.w.WriteHeader(http.StatusNoContent)
+}
+
+// url returns an absolute URL for a path.
+func (r *request) url(path string) string {
+	return r.base + path
+}
+
+// accountPath prefixes a path with the account slug.
+func (r *request) accountPath(path string) string {
+	return "/" + r.account + path
+}
+
+// param returns a string field from a nested payload object, e.g.
+// param("card", "title"). It reports whether the field was present.
+func (r *request) param(keys ...string) (interface{}, bool) {
+	var cur interface{} = r.payload
+	for _, k := range keys {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[k]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func (r *request) stringParam(keys ...string) (string, bool) {
+	v, ok := r.param(keys...)
+	if !ok {
+		return "", false
+	}
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(val), true
+	}
+	return "", false
+}
+
+func (r *request) boolParam(keys ...string) (bool, bool) {
+	v, ok := r.param(keys...)
+	if !ok {
+		return false, false
+	}
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		return val == "true" || val == "1", true
+	}
+	return false, false
+}
+
+// paginate writes one page of items with a Link header pointing at the next
+// page when there is one.
+func (s *Server) paginate(r *request, items []interface{}) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	size := s.PageSize
+	if size < 1 {
+		size = DefaultPageSize
+	}
+
+	start := (page - 1) * size
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+
+	if end < len(items) {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+		r.w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, r.base+next.String()))
+	}
+
+	r.json(http.StatusOK, append([]interface{}{}, items[start:end]...))
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
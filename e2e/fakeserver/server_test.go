@@ -0,0 +1,126 @@
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path string, body interface{}) *http.Response {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, err := http.NewRequest(method, s.URL+path, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestRequiresToken(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/" + s.Account + "/boards.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestCreateReturnsLocation(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	resp := do(t, s, "POST", "/"+s.Account+"/boards.json", map[string]interface{}{
+		"board": map[string]interface{}{"name": "Roadmap"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	location := resp.Header.Get("Location")
+	if !strings.HasPrefix(location, s.URL+"/"+s.Account+"/boards/") {
+		t.Fatalf("unexpected Location %q", location)
+	}
+
+	resp = do(t, s, "GET", strings.TrimPrefix(location, s.URL), nil)
+	var b map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&b)
+	if b["name"] != "Roadmap" {
+		t.Errorf("expected board 'Roadmap', got %v", b["name"])
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	s := New()
+	defer s.Close()
+	s.PageSize = 2
+
+	for _, name := range []string{"One", "Two"} {
+		do(t, s, "POST", "/"+s.Account+"/boards.json", map[string]interface{}{
+			"board": map[string]interface{}{"name": name},
+		})
+	}
+
+	resp := do(t, s, "GET", "/"+s.Account+"/boards.json", nil)
+	link := resp.Header.Get("Link")
+	want := `<` + s.URL + "/" + s.Account + `/boards.json?page=2>; rel="next"`
+	if link != want {
+		t.Fatalf("expected Link %q, got %q", want, link)
+	}
+
+	resp = do(t, s, "GET", "/"+s.Account+"/boards.json?page=2", nil)
+	var page []interface{}
+	json.NewDecoder(resp.Body).Decode(&page)
+	if len(page) != 1 || resp.Header.Get("Link") != "" {
+		t.Errorf("expected last page with 1 board and no Link, got %d boards, Link %q", len(page), resp.Header.Get("Link"))
+	}
+}
+
+func TestCardListFilters(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	resp := do(t, s, "POST", "/"+s.Account+"/boards.json", map[string]interface{}{
+		"board": map[string]interface{}{"name": "Other"},
+	})
+	boardID := resp.Header.Get("Location")
+	boardID = strings.TrimSuffix(boardID[strings.LastIndex(boardID, "/")+1:], ".json")
+
+	resp = do(t, s, "POST", "/"+s.Account+"/cards.json", map[string]interface{}{
+		"board_id": boardID,
+		"card":     map[string]interface{}{"title": "Elsewhere"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	do(t, s, "POST", "/"+s.Account+"/cards/2/closure.json", nil)
+
+	resp = do(t, s, "GET", "/"+s.Account+"/cards.json?board_ids[]="+boardID, nil)
+	var open []map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&open)
+	if len(open) != 0 {
+		t.Errorf("expected closed card to be excluded, got %v", open)
+	}
+
+	resp = do(t, s, "GET", "/"+s.Account+"/cards.json?board_ids[]="+boardID+"&indexed_by=closed", nil)
+	var closed []map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&closed)
+	if len(closed) != 1 || closed[0]["title"] != "Elsewhere" {
+		t.Errorf("expected the closed card, got %v", closed)
+	}
+}
@@ -0,0 +1,555 @@
+package fakeserver
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type user struct {
+	ID        string
+	Name      string
+	Email     string
+	Role      string
+	CreatedAt time.Time
+}
+
+type board struct {
+	ID                 string
+	Name               string
+	AllAccess          bool
+	AutoPostponePeriod int
+	CreatorID          string
+	CreatedAt          time.Time
+}
+
+type column struct {
+	ID        string
+	BoardID   string
+	Name      string
+	Color     string
+	CreatedAt time.Time
+}
+
+type card struct {
+	ID              string
+	Number          int
+	BoardID         string
+	ColumnID        string
+	Title           string
+	DescriptionHTML string
+	Image           string
+	Closed          bool
+	Postponed       bool
+	Golden          bool
+	TagIDs          []string
+	AssigneeIDs     []string
+	Watching        bool
+	CreatorID       string
+	CreatedAt       time.Time
+	LastActiveAt    time.Time
+}
+
+type comment struct {
+	ID         string
+	CardNumber int
+	BodyHTML   string
+	CreatorID  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type reaction struct {
+	ID        string
+	CommentID string
+	Content   string
+	ReacterID string
+}
+
+type step struct {
+	ID         string
+	CardNumber int
+	Content    string
+	Completed  bool
+}
+
+type tag struct {
+	ID        string
+	Title     string
+	CreatedAt time.Time
+}
+
+type notification struct {
+	ID         string
+	CardNumber int
+	Title      string
+	Body       string
+	Read       bool
+	ReadAt     time.Time
+	CreatorID  string
+	CreatedAt  time.Time
+}
+
+type blob struct {
+	Key         string
+	Filename    string
+	ContentType string
+	ByteSize    int64
+	Checksum    string
+	Uploaded    bool
+	Data        []byte
+}
+
+// store holds the server state. Slices keep insertion order so list
+// responses are stable.
+type store struct {
+	seq       int
+	cardSeq   int
+	me        string
+	accountID string
+	users     []*user
+	boards    []*board
+	columns   []*column
+	cards     []*card
+	comments  []*comment
+	reactions []*reaction
+	steps     []*step
+	tags      []*tag
+	notices   []*notification
+	blobs     map[string]*blob
+}
+
+func newStore() *store {
+	st := &store{blobs: map[string]*blob{}}
+	now := time.Now()
+
+	me := &user{ID: st.newID(), Name: "Test User", Email: "test@example.com", Role: "owner", CreatedAt: now}
+	other := &user{ID: st.newID(), Name: "Jane Doe", Email: "jane@example.com", Role: "member", CreatedAt: now}
+	st.users = []*user{me, other}
+	st.me = me.ID
+	st.accountID = st.newID()
+
+	welcome := &board{ID: st.newID(), Name: "Getting Started", AllAccess: true, CreatorID: me.ID, CreatedAt: now}
+	st.boards = append(st.boards, welcome)
+	st.cardSeq++
+	c := &card{
+		ID:              st.newID(),
+		Number:          st.cardSeq,
+		BoardID:         welcome.ID,
+		Title:           "Welcome to Fizzy",
+		DescriptionHTML: actionText("This card was created by the fake server."),
+		CreatorID:       other.ID,
+		CreatedAt:       now,
+		LastActiveAt:    now,
+	}
+	st.cards = append(st.cards, c)
+	st.addNotification(c.Number, "Welcome to Fizzy", "Jane Doe added you to Getting Started")
+
+	return st
+}
+
+// newID returns a new opaque ID in the same lowercase base36 shape as the
+// real API's IDs.
+func (st *store) newID() string {
+	st.seq++
+	id := strconv.FormatInt(int64(st.seq), 36)
+	return "03" + strings.Repeat("0", 23-len(id)) + id
+}
+
+func (st *store) addNotification(cardNumber int, title, body string) string {
+	n := &notification{
+		ID:         st.newID(),
+		CardNumber: cardNumber,
+		Title:      title,
+		Body:       body,
+		CreatorID:  st.users[len(st.users)-1].ID,
+		CreatedAt:  time.Now(),
+	}
+	st.notices = append(st.notices, n)
+	return n.ID
+}
+
+func (st *store) findUser(id string) *user {
+	for _, u := range st.users {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (st *store) findBoard(id string) *board {
+	for _, b := range st.boards {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
+
+func (st *store) findColumn(boardID, id string) *column {
+	for _, c := range st.columns {
+		if c.ID == id && (boardID == "" || c.BoardID == boardID) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (st *store) findCard(number string) *card {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return nil
+	}
+	for _, c := range st.cards {
+		if c.Number == n {
+			return c
+		}
+	}
+	return nil
+}
+
+func (st *store) findComment(cardNumber int, id string) *comment {
+	for _, c := range st.comments {
+		if c.ID == id && c.CardNumber == cardNumber {
+			return c
+		}
+	}
+	return nil
+}
+
+func (st *store) findStep(cardNumber int, id string) *step {
+	for _, s := range st.steps {
+		if s.ID == id && s.CardNumber == cardNumber {
+			return s
+		}
+	}
+	return nil
+}
+
+func (st *store) findTag(id string) *tag {
+	for _, t := range st.tags {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (st *store) findTagByTitle(title string) *tag {
+	for _, t := range st.tags {
+		if strings.EqualFold(t.Title, title) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (st *store) findNotification(id string) *notification {
+	for _, n := range st.notices {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// deleteCard removes a card and everything attached to it.
+func (st *store) deleteCard(c *card) {
+	st.cards = remove(st.cards, c)
+	for _, cm := range append([]*comment{}, st.comments...) {
+		if cm.CardNumber == c.Number {
+			st.deleteComment(cm)
+		}
+	}
+	steps := st.steps[:0]
+	for _, s := range st.steps {
+		if s.CardNumber != c.Number {
+			steps = append(steps, s)
+		}
+	}
+	st.steps = steps
+	notices := st.notices[:0]
+	for _, n := range st.notices {
+		if n.CardNumber != c.Number {
+			notices = append(notices, n)
+		}
+	}
+	st.notices = notices
+}
+
+func (st *store) deleteComment(c *comment) {
+	st.comments = remove(st.comments, c)
+	reactions := st.reactions[:0]
+	for _, r := range st.reactions {
+		if r.CommentID != c.ID {
+			reactions = append(reactions, r)
+		}
+	}
+	st.reactions = reactions
+}
+
+func (st *store) deleteBoard(b *board) {
+	st.boards = remove(st.boards, b)
+	for _, c := range append([]*card{}, st.cards...) {
+		if c.BoardID == b.ID {
+			st.deleteCard(c)
+		}
+	}
+	columns := st.columns[:0]
+	for _, c := range st.columns {
+		if c.BoardID != b.ID {
+			columns = append(columns, c)
+		}
+	}
+	st.columns = columns
+}
+
+func remove[T comparable](items []T, item T) []T {
+	out := items[:0]
+	for _, it := range items {
+		if it != item {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func toggle(ids []string, id string) []string {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return append(ids, id)
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// actionText wraps rich text the way Action Text renders it.
+func actionText(body string) string {
+	return `<div class="action-text-content">` + body + `</div>`
+}
+
+var attachmentPattern = regexp.MustCompile(`<action-text-attachment sgid="sgid-([^"]+)"([^>]*)>`)
+
+// richText renders the attachments in rich text with the blob details and
+// URL Action Text adds to them.
+func (r *request) richText(st *store, richText string) string {
+	return attachmentPattern.ReplaceAllStringFunc(richText, func(tag string) string {
+		m := attachmentPattern.FindStringSubmatch(tag)
+		b, ok := st.blobs[m[1]]
+		if !ok {
+			return tag
+		}
+		return fmt.Sprintf(`<action-text-attachment sgid="sgid-%s" content-type="%s" url="%s" filename="%s" filesize="%d"%s>`,
+			b.Key, html.EscapeString(b.ContentType), r.url("/rails/active_storage/blobs/"+b.Key+"/"+url.PathEscape(b.Filename)),
+			html.EscapeString(b.Filename), b.ByteSize, m[2])
+	})
+}
+
+// plainText strips markup from rich text.
+func plainText(richText string) string {
+	text := tagPattern.ReplaceAllString(richText, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+func colorName(value string) string {
+	names := map[string]string{
+		"var(--color-card-default)": "Blue",
+		"var(--color-card-1)":       "Gray",
+		"var(--color-card-2)":       "Tan",
+		"var(--color-card-3)":       "Yellow",
+		"var(--color-card-4)":       "Lime",
+		"var(--color-card-5)":       "Aqua",
+		"var(--color-card-6)":       "Violet",
+		"var(--color-card-7)":       "Purple",
+		"var(--color-card-8)":       "Pink",
+	}
+	if name, ok := names[value]; ok {
+		return name
+	}
+	return value
+}
+
+// JSON renderers. These mirror the shape of the real API's responses.
+
+func (r *request) userJSON(u *user) interface{} {
+	if u == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":            u.ID,
+		"name":          u.Name,
+		"role":          u.Role,
+		"active":        true,
+		"email_address": u.Email,
+		"created_at":    timestamp(u.CreatedAt),
+		"url":           r.url(r.accountPath("/users/" + u.ID)),
+	}
+}
+
+func (r *request) boardJSON(st *store, b *board) interface{} {
+	return map[string]interface{}{
+		"id":                   b.ID,
+		"name":                 b.Name,
+		"all_access":           b.AllAccess,
+		"auto_postpone_period": b.AutoPostponePeriod,
+		"created_at":           timestamp(b.CreatedAt),
+		"url":                  r.url(r.accountPath("/boards/" + b.ID)),
+		"creator":              r.userJSON(st.findUser(b.CreatorID)),
+	}
+}
+
+func (r *request) columnJSON(c *column) interface{} {
+	color := c.Color
+	if color == "" {
+		color = "var(--color-card-default)"
+	}
+	return map[string]interface{}{
+		"id":   c.ID,
+		"name": c.Name,
+		"color": map[string]interface{}{
+			"name":  colorName(color),
+			"value": color,
+		},
+		"created_at": timestamp(c.CreatedAt),
+	}
+}
+
+func (r *request) cardJSON(st *store, c *card, withSteps bool) map[string]interface{} {
+	tags := make([]interface{}, 0, len(c.TagIDs))
+	for _, id := range c.TagIDs {
+		if t := st.findTag(id); t != nil {
+			tags = append(tags, t.Title)
+		}
+	}
+	assignees := make([]interface{}, 0, len(c.AssigneeIDs))
+	for _, id := range c.AssigneeIDs {
+		assignees = append(assignees, r.userJSON(st.findUser(id)))
+	}
+
+	status := "published"
+	if c.Closed {
+		status = "closed"
+	}
+
+	out := map[string]interface{}{
+		"id":                 c.ID,
+		"number":             c.Number,
+		"title":              c.Title,
+		"status":             status,
+		"description":        plainText(c.DescriptionHTML),
+		"description_html":   r.richText(st, c.DescriptionHTML),
+		"image_url":          nil,
+		"tags":               tags,
+		"closed":             c.Closed,
+		"postponed":          c.Postponed,
+		"golden":             c.Golden,
+		"watching":           c.Watching,
+		"created_at":         timestamp(c.CreatedAt),
+		"last_active_at":     timestamp(c.LastActiveAt),
+		"url":                r.url(r.accountPath(fmt.Sprintf("/cards/%d", c.Number))),
+		"comments_url":       r.url(r.accountPath(fmt.Sprintf("/cards/%d/comments.json", c.Number))),
+		"creator":            r.userJSON(st.findUser(c.CreatorID)),
+		"assignees":          assignees,
+		"has_more_assignees": false,
+	}
+	if c.Image != "" {
+		out["image_url"] = r.url("/rails/active_storage/blobs/" + c.Image)
+	}
+	if b := st.findBoard(c.BoardID); b != nil {
+		out["board"] = r.boardJSON(st, b)
+	}
+	if col := st.findColumn(c.BoardID, c.ColumnID); col != nil {
+		out["column"] = r.columnJSON(col)
+	}
+	if withSteps {
+		steps := []interface{}{}
+		for _, s := range st.steps {
+			if s.CardNumber == c.Number {
+				steps = append(steps, stepJSON(s))
+			}
+		}
+		out["steps"] = steps
+	}
+	return out
+}
+
+func (r *request) commentJSON(st *store, c *comment) interface{} {
+	return map[string]interface{}{
+		"id":         c.ID,
+		"created_at": timestamp(c.CreatedAt),
+		"updated_at": timestamp(c.UpdatedAt),
+		"body": map[string]interface{}{
+			"plain_text": plainText(c.BodyHTML),
+			"html":       r.richText(st, c.BodyHTML),
+		},
+		"creator": r.userJSON(st.findUser(c.CreatorID)),
+		"card": map[string]interface{}{
+			"number": c.CardNumber,
+			"url":    r.url(r.accountPath(fmt.Sprintf("/cards/%d", c.CardNumber))),
+		},
+		"reactions_url": r.url(r.accountPath(fmt.Sprintf("/cards/%d/comments/%s/reactions.json", c.CardNumber, c.ID))),
+		"url":           r.url(r.accountPath(fmt.Sprintf("/cards/%d/comments/%s", c.CardNumber, c.ID))),
+	}
+}
+
+func (r *request) reactionJSON(st *store, re *reaction) interface{} {
+	return map[string]interface{}{
+		"id":      re.ID,
+		"content": re.Content,
+		"reacter": r.userJSON(st.findUser(re.ReacterID)),
+	}
+}
+
+func stepJSON(s *step) interface{} {
+	return map[string]interface{}{
+		"id":        s.ID,
+		"content":   s.Content,
+		"completed": s.Completed,
+	}
+}
+
+func (r *request) tagJSON(t *tag) interface{} {
+	return map[string]interface{}{
+		"id":         t.ID,
+		"title":      t.Title,
+		"created_at": timestamp(t.CreatedAt),
+		"url":        r.url(r.accountPath("/cards?tag_ids[]=" + t.ID)),
+	}
+}
+
+func (r *request) notificationJSON(st *store, n *notification) interface{} {
+	out := map[string]interface{}{
+		"id":         n.ID,
+		"read":       n.Read,
+		"read_at":    nil,
+		"created_at": timestamp(n.CreatedAt),
+		"title":      n.Title,
+		"body":       n.Body,
+		"creator":    r.userJSON(st.findUser(n.CreatorID)),
+		"url":        r.url(r.accountPath("/notifications/" + n.ID)),
+	}
+	if n.Read {
+		out["read_at"] = timestamp(n.ReadAt)
+	}
+	if c := st.findCard(strconv.Itoa(n.CardNumber)); c != nil {
+		status := "published"
+		if c.Closed {
+			status = "closed"
+		}
+		out["card"] = map[string]interface{}{
+			"id":     c.ID,
+			"number": c.Number,
+			"title":  c.Title,
+			"status": status,
+			"url":    r.url(r.accountPath(fmt.Sprintf("/cards/%d", c.Number))),
+		}
+	}
+	return out
+}
@@ -92,6 +92,8 @@ const (
 	ExitNotFound    = 5
 	ExitValidation  = 6
 	ExitNetwork     = 7
+	ExitRateLimited = 8
+	ExitCancelled   = 130
 )
 
 // LoadConfig loads test configuration from environment variables.
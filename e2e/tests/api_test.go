@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/e2e/harness"
+)
+
+func TestAPIRaw(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	t.Run("GET lists boards", func(t *testing.T) {
+		result := h.Run("api", "GET", "/boards.json", "--paginate")
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if result.GetDataArray() == nil {
+			t.Error("expected data to be an array")
+		}
+	})
+
+	t.Run("POST creates a board", func(t *testing.T) {
+		name := fmt.Sprintf("API Board %d", time.Now().UnixNano())
+		result := h.Run("api", "POST", "/boards.json", "--field", "board[name]="+name)
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		boardID := result.GetIDFromLocation()
+		if boardID == "" {
+			t.Fatalf("expected location in response, got %s", result.Stdout)
+		}
+		h.Cleanup.AddBoard(boardID)
+
+		show := h.Run("api", "GET", "/boards/"+boardID+".json")
+		if show.GetDataString("name") != name {
+			t.Errorf("expected name %q, got %q", name, show.GetDataString("name"))
+		}
+	})
+
+	t.Run("GET identity without account prefix", func(t *testing.T) {
+		result := h.Run("api", "GET", "/my/identity.json")
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		if result.GetDataMap()["accounts"] == nil {
+			t.Error("expected accounts in identity response")
+		}
+	})
+
+	t.Run("not found maps to exit code", func(t *testing.T) {
+		result := h.Run("api", "GET", "/cards/999999999.json")
+
+		if result.ExitCode != harness.ExitNotFound {
+			t.Errorf("expected exit code %d, got %d", harness.ExitNotFound, result.ExitCode)
+		}
+	})
+}
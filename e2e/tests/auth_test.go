@@ -22,7 +22,7 @@ func TestAuthStatus(t *testing.T) {
 		}
 		defer os.RemoveAll(tmpDir)
 
-		configDir := filepath.Join(tmpDir, ".fizzy")
+		configDir := filepath.Join(tmpDir, ".config", "fizzy")
 		os.MkdirAll(configDir, 0755)
 		configPath := filepath.Join(configDir, "config.yaml")
 		os.WriteFile(configPath, []byte("token: "+cfg.Token+"\n"), 0600)
@@ -110,7 +110,7 @@ func TestAuthLogin(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	configDir := filepath.Join(tmpDir, ".fizzy")
+	configDir := filepath.Join(tmpDir, ".config", "fizzy")
 
 	t.Run("saves token to config file", func(t *testing.T) {
 		// Run login with HOME set to temp directory
@@ -143,7 +143,7 @@ func TestAuthLogout(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	configDir := filepath.Join(tmpDir, ".fizzy")
+	configDir := filepath.Join(tmpDir, ".config", "fizzy")
 	os.MkdirAll(configDir, 0755)
 
 	// Create a config file
@@ -2,6 +2,10 @@ package tests
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -299,3 +303,55 @@ func TestBoardDeleteNotFound(t *testing.T) {
 		}
 	})
 }
+
+func TestBoardExport(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	column := h.Run("column", "create", "--board", boardID, "--name", "Review")
+	if column.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create column: %s", column.Stdout)
+	}
+	number := createTestCard(t, h, boardID)
+	h.Run("card", "column", strconv.Itoa(number), "--column", column.GetIDFromLocation())
+	h.Run("step", "create", "--card", strconv.Itoa(number), "--content", "Check the docs", "--completed")
+	closed := createTestCard(t, h, boardID)
+	h.Run("card", "close", strconv.Itoa(closed))
+
+	dir := t.TempDir()
+	export := func(name string) string {
+		out := filepath.Join(dir, name)
+		result := h.Run("board", "export", boardID, "--format", "markdown", "--out", out)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	first := export("first.md")
+	lanes := []string{"## Not Now", "## Maybe?", "## Review", "## Done"}
+	last := -1
+	for _, lane := range lanes {
+		i := strings.Index(first, lane)
+		if i <= last {
+			t.Fatalf("expected lanes in board order %v, got\n%s", lanes, first)
+		}
+		last = i
+	}
+	review := first[strings.Index(first, "## Review"):strings.Index(first, "## Done")]
+	if !strings.Contains(review, fmt.Sprintf("### #%d ", number)) || !strings.Contains(review, "- [x] Check the docs") {
+		t.Errorf("expected card #%d with its step in Review, got\n%s", number, review)
+	}
+	if !strings.Contains(first[strings.Index(first, "## Done"):], fmt.Sprintf("### #%d ", closed)) {
+		t.Errorf("expected card #%d in Done", closed)
+	}
+
+	if second := export("second.md"); second != first {
+		t.Errorf("expected the same report twice, got\n%s\nthen\n%s", first, second)
+	}
+}
@@ -386,6 +386,342 @@ func TestCardCreateWithDescription(t *testing.T) {
 	})
 }
 
+func TestCardCreateWithAttachments(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	wd, _ := os.Getwd()
+	image := filepath.Join(wd, "..", "testdata", "fixtures", "test_image.png")
+	if _, err := os.Stat(image); os.IsNotExist(err) {
+		t.Skipf("test fixture not found at %s", image)
+	}
+
+	boardID := createTestBoard(t, h)
+
+	t.Run("uploads attachments and a header image file", func(t *testing.T) {
+		title := fmt.Sprintf("Card with Attachment %d", time.Now().UnixNano())
+		result := h.Run("card", "create", "--board", boardID, "--title", title,
+			"--description", "<p>Screenshot:</p>", "--attach", image, "--image", image)
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s\nstdout: %s",
+				harness.ExitSuccess, result.ExitCode, result.Stderr, result.Stdout)
+		}
+
+		cardNumber := result.GetNumberFromLocation()
+		if cardNumber == 0 {
+			cardNumber = result.GetDataInt("number")
+		}
+		if cardNumber == 0 {
+			t.Fatal("no card number returned")
+		}
+		h.Cleanup.AddCard(cardNumber)
+
+		showResult := h.Run("card", "show", strconv.Itoa(cardNumber))
+		descHTML := showResult.GetDataString("description_html")
+		if !strings.Contains(descHTML, "Screenshot:") || !strings.Contains(descHTML, "<action-text-attachment") {
+			t.Errorf("expected description with an attachment, got %q", descHTML)
+		}
+		if showResult.GetDataString("image_url") == "" {
+			t.Error("expected the card to have a header image")
+		}
+	})
+
+	t.Run("converts a markdown file with a local image", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "description.md")
+		content := "## Steps\n\n- **Open** the app\n\n![Screenshot](" + image + ")\n"
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		title := fmt.Sprintf("Markdown Card %d", time.Now().UnixNano())
+		result := h.Run("card", "create", "--board", boardID, "--title", title, "--description_file", file)
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s\nstdout: %s",
+				harness.ExitSuccess, result.ExitCode, result.Stderr, result.Stdout)
+		}
+
+		cardNumber := result.GetNumberFromLocation()
+		if cardNumber == 0 {
+			cardNumber = result.GetDataInt("number")
+		}
+		if cardNumber == 0 {
+			t.Fatal("no card number returned")
+		}
+		h.Cleanup.AddCard(cardNumber)
+
+		showResult := h.Run("card", "show", strconv.Itoa(cardNumber))
+		descHTML := showResult.GetDataString("description_html")
+		for _, want := range []string{"<h2>Steps</h2>", "<strong>Open</strong>", "<action-text-attachment"} {
+			if !strings.Contains(descHTML, want) {
+				t.Errorf("expected description to contain %q, got %q", want, descHTML)
+			}
+		}
+
+		plain := h.Run("card", "show", strconv.Itoa(cardNumber), "-o", "table", "--plain")
+		for _, want := range []string{"Steps", "- Open the app", "[attachment: "} {
+			if !strings.Contains(plain.Stdout, want) {
+				t.Errorf("expected plain output to contain %q, got:\n%s", want, plain.Stdout)
+			}
+		}
+
+		md := h.Run("card", "show", strconv.Itoa(cardNumber), "--markdown")
+		if got := md.GetDataString("description_markdown"); !strings.HasPrefix(got, "## Steps\n\n- **Open** the app") {
+			t.Errorf("expected description_markdown, got %q", got)
+		}
+	})
+}
+
+func TestCardEdit(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	title := fmt.Sprintf("Edit Test Card %d", time.Now().UnixNano())
+	result := h.Run("card", "create", "--board", boardID, "--title", title, "--description", "<p>Before</p>")
+	if result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create test card: %s\nstdout: %s", result.Stderr, result.Stdout)
+	}
+	cardNumber := result.GetNumberFromLocation()
+	if cardNumber == 0 {
+		cardNumber = result.GetDataInt("number")
+	}
+	if cardNumber == 0 {
+		t.Fatal("no card number returned")
+	}
+	h.Cleanup.AddCard(cardNumber)
+
+	// The "editor" replaces the file with a prepared one
+	dir := t.TempDir()
+	edited := filepath.Join(dir, "edited.md")
+	doc := "---\ntitle: " + title + " (edited)\ntags: [e2e-edit]\n---\n\n## After\n\n- *changed*\n"
+	if err := os.WriteFile(edited, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	editor := filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\ncat '" + edited + "' > \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	result = h.RunWithEnv(map[string]string{"VISUAL": "", "EDITOR": editor}, "card", "edit", strconv.Itoa(cardNumber))
+	if result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s\nstdout: %s",
+			harness.ExitSuccess, result.ExitCode, result.Stderr, result.Stdout)
+	}
+
+	if got := result.GetDataString("title"); got != title+" (edited)" {
+		t.Errorf("expected the new title, got %q", got)
+	}
+	descHTML := result.GetDataString("description_html")
+	if !strings.Contains(descHTML, "<h2>After</h2>") || !strings.Contains(descHTML, "<em>changed</em>") {
+		t.Errorf("expected the new description, got %q", descHTML)
+	}
+	tags, _ := result.GetDataMap()["tags"].([]interface{})
+	if len(tags) != 1 || tags[0] != "e2e-edit" {
+		t.Errorf("expected tags [e2e-edit], got %v", tags)
+	}
+}
+
+func TestCardListWhere(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	var cards []string
+	for i := 1; i <= 3; i++ {
+		cards = append(cards, strconv.Itoa(createTestCard(t, h, boardID)))
+	}
+	if result := h.Run("card", "assign", cards[0], "--user", "me"); result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to assign card: %s", result.Stderr)
+	}
+	if result := h.Run("step", "create", "--card", cards[1], "--content", "Open step"); result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create step: %s", result.Stderr)
+	}
+
+	numbers := func(result *harness.Result) []string {
+		var out []string
+		for _, item := range result.GetDataArray() {
+			card, _ := item.(map[string]interface{})
+			out = append(out, fmt.Sprintf("%v", card["number"]))
+		}
+		return out
+	}
+
+	t.Run("filters unassigned cards and sorts them", func(t *testing.T) {
+		result := h.Run("card", "list", "--board", boardID, "--all", "--where", "assignees = [] and created_at > -1d", "--sort", "number:desc")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if got := numbers(result); strings.Join(got, ",") != cards[2]+","+cards[1] {
+			t.Errorf("expected cards %s,%s, got %v", cards[2], cards[1], got)
+		}
+	})
+
+	t.Run("filters by unfinished steps", func(t *testing.T) {
+		result := h.Run("card", "list", "--board", boardID, "--all", "--where", "steps.completed = false")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if got := numbers(result); len(got) != 1 || got[0] != cards[1] {
+			t.Errorf("expected card %s, got %v", cards[1], got)
+		}
+	})
+
+	t.Run("limits the results", func(t *testing.T) {
+		result := h.Run("card", "list", "--board", boardID, "--all", "--sort", "number", "--limit", "1")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if got := numbers(result); len(got) != 1 || got[0] != cards[0] {
+			t.Errorf("expected card %s, got %v", cards[0], got)
+		}
+	})
+}
+
+func TestCardBulkActions(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	first := strconv.Itoa(createTestCard(t, h, boardID))
+	second := strconv.Itoa(createTestCard(t, h, boardID))
+
+	t.Run("lists the cards with --dry-run", func(t *testing.T) {
+		result := h.Run("card", "close", "--where", "number = "+first, "--board", boardID, "--dry-run")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		cards := result.GetDataArray()
+		if len(cards) != 1 || fmt.Sprintf("%v", cards[0].(map[string]interface{})["number"]) != first {
+			t.Errorf("expected card %s, got %v", first, cards)
+		}
+		if show := h.Run("card", "show", first); show.GetDataBool("closed") {
+			t.Error("expected --dry-run to leave the card open")
+		}
+	})
+
+	t.Run("reports each card", func(t *testing.T) {
+		result := h.Run("card", "close", first, second, "999999")
+		if result.ExitCode != harness.ExitError {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitError, result.ExitCode, result.Stdout)
+		}
+		results := result.GetDataArray()
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %v", results)
+		}
+		for i, want := range []bool{true, true, false} {
+			item := results[i].(map[string]interface{})
+			if item["success"] != want {
+				t.Errorf("result %d: expected success %v, got %v", i, want, item)
+			}
+		}
+		if code := results[2].(map[string]interface{})["error"].(map[string]interface{})["code"]; code != "NOT_FOUND" {
+			t.Errorf("expected NOT_FOUND, got %v", code)
+		}
+	})
+
+	t.Run("reopens the cards matching --where", func(t *testing.T) {
+		result := h.Run("card", "reopen", "--where", "closed", "--board", boardID, "--indexed-by", "closed")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if n := len(result.GetDataArray()); n != 2 {
+			t.Errorf("expected 2 cards reopened, got %d", n)
+		}
+		for _, number := range []string{first, second} {
+			if show := h.Run("card", "show", number); show.GetDataBool("closed") {
+				t.Errorf("expected card %s to be open", number)
+			}
+		}
+	})
+}
+
+func TestCardSearch(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	word := fmt.Sprintf("zebra%d", time.Now().UnixNano())
+	create := func(title, description string) string {
+		t.Helper()
+		args := []string{"card", "create", "--board", boardID, "--title", title}
+		if description != "" {
+			args = append(args, "--description", description)
+		}
+		result := h.Run(args...)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("failed to create test card: %s\nstdout: %s", result.Stderr, result.Stdout)
+		}
+		cardNumber := result.GetNumberFromLocation()
+		if cardNumber == 0 {
+			cardNumber = result.GetDataInt("number")
+		}
+		if cardNumber == 0 {
+			t.Fatal("no card number returned")
+		}
+		h.Cleanup.AddCard(cardNumber)
+		return strconv.Itoa(cardNumber)
+	}
+	inDescription := create("Search card one", "<p>The "+word+" escaped</p>")
+	inTitle := create("Find the "+word, "")
+	inComment := create("Search card three", "")
+	if result := h.Run("comment", "create", "--card", inComment, "--body", "<p>Saw a "+word+"</p>"); result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create comment: %s", result.Stderr)
+	}
+
+	numbers := func(result *harness.Result) []string {
+		var out []string
+		for _, item := range result.GetDataArray() {
+			card, _ := item.(map[string]interface{})
+			out = append(out, fmt.Sprintf("%v", card["number"]))
+		}
+		return out
+	}
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		result := h.Run("card", "search", word, "--board", boardID)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		got := numbers(result)
+		if len(got) != 2 || got[0] != inTitle || got[1] != inDescription {
+			t.Fatalf("expected cards [%s %s], got %v", inTitle, inDescription, got)
+		}
+		card, _ := result.GetDataArray()[0].(map[string]interface{})
+		search, _ := card["search"].(map[string]interface{})
+		if search["field"] != "title" || search["snippet"] != "Find the **"+word+"**" {
+			t.Errorf("expected a highlighted title match, got %v", search)
+		}
+	})
+
+	t.Run("searches comments with --local", func(t *testing.T) {
+		result := h.Run("card", "search", word, "--board", boardID, "--local")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		got := numbers(result)
+		if len(got) != 3 || got[2] != inComment {
+			t.Fatalf("expected the comment match last of 3, got %v", got)
+		}
+	})
+
+	t.Run("stops at --limit", func(t *testing.T) {
+		result := h.Run("card", "search", word, "--board", boardID, "--limit", "1")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if got := numbers(result); len(got) != 1 {
+			t.Errorf("expected 1 card, got %v", got)
+		}
+		if result.Response.Pagination == nil || !result.Response.Pagination.HasNext {
+			t.Error("expected more results to be reported")
+		}
+	})
+}
+
 func TestCardActions(t *testing.T) {
 	h := harness.New(t)
 	defer h.Cleanup.CleanupAll(h)
@@ -600,3 +936,51 @@ func TestCardCreateMissingTitle(t *testing.T) {
 		}
 	})
 }
+
+func TestCardTimeline(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	number := strconv.Itoa(createTestCard(t, h, boardID))
+	for _, args := range [][]string{
+		{"comment", "create", "--card", number, "--body", "Looking into it"},
+		{"step", "create", "--card", number, "--content", "Reproduce", "--completed"},
+		{"card", "close", number},
+	} {
+		if result := h.Run(args...); result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("%v failed: %s", args, result.Stdout)
+		}
+	}
+
+	t.Run("lists the card's events", func(t *testing.T) {
+		result := h.Run("card", "timeline", number)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		var types []string
+		for _, item := range result.GetDataArray() {
+			types = append(types, item.(map[string]interface{})["type"].(string))
+		}
+		if got := strings.Join(types, " "); got != "created comment closed step_completed" {
+			t.Errorf("unexpected events %q", got)
+		}
+	})
+
+	t.Run("shows a readable timeline", func(t *testing.T) {
+		result := h.Run("card", "timeline", number, "-o", "table")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if !strings.Contains(result.Stdout, "Commented\n  Looking into it") {
+			t.Errorf("expected the comment in the timeline, got\n%s", result.Stdout)
+		}
+	})
+
+	t.Run("fails for a missing card", func(t *testing.T) {
+		result := h.Run("card", "timeline", "999999")
+		if result.ExitCode != harness.ExitNotFound {
+			t.Errorf("expected exit code %d, got %d", harness.ExitNotFound, result.ExitCode)
+		}
+	})
+}
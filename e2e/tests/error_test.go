@@ -191,9 +191,9 @@ func TestSuccessResponseFormat(t *testing.T) {
 			t.Error("expected no error in success response")
 		}
 
-		// Pagination should be present for list operations
-		if result.Response.Pagination == nil {
-			t.Error("expected pagination in list response")
+		// Pagination is only present when there is another page
+		if p := result.Response.Pagination; p != nil && !p.HasNext {
+			t.Error("expected pagination to be omitted when there is no next page")
 		}
 
 		// Meta should be present
@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/e2e/harness"
+)
+
+func TestAccountExportImport(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	wd, _ := os.Getwd()
+	image := filepath.Join(wd, "..", "testdata", "fixtures", "test_image.png")
+	if _, err := os.Stat(image); os.IsNotExist(err) {
+		t.Skipf("test fixture not found at %s", image)
+	}
+
+	boardID := createTestBoard(t, h)
+	column := h.Run("column", "create", "--board", boardID, "--name", "Doing")
+	if column.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create column: %s", column.Stdout)
+	}
+	columnID := column.GetIDFromLocation()
+
+	title := fmt.Sprintf("Archived Card %d", time.Now().UnixNano())
+	createdAt := "2023-03-01T10:00:00Z"
+	card := h.Run("card", "create", "--board", boardID, "--title", title,
+		"--description", "<p>See attached</p>", "--attach", image, "--created-at", createdAt)
+	if card.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to create card: %s", card.Stdout)
+	}
+	number := strconv.Itoa(card.GetNumberFromLocation())
+	h.Cleanup.AddCard(card.GetNumberFromLocation())
+
+	for _, args := range [][]string{
+		{"card", "column", number, "--column", columnID},
+		{"card", "tag", number, "--tag", "archived"},
+		{"step", "create", "--card", number, "--content", "Write it down", "--completed"},
+		{"comment", "create", "--card", number, "--body", "First!", "--created-at", "2023-03-02T09:00:00Z"},
+	} {
+		if result := h.Run(args...); result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("%v failed: %s", args, result.Stdout)
+		}
+	}
+	comments := h.Run("comment", "list", "--card", number).GetDataArray()
+	commentID := comments[0].(map[string]interface{})["id"].(string)
+	if result := h.Run("reaction", "create", "--card", number, "--comment", commentID, "--content", "👍"); result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to add reaction: %s", result.Stdout)
+	}
+	closed := createTestCard(t, h, boardID)
+	if result := h.Run("card", "close", strconv.Itoa(closed)); result.ExitCode != harness.ExitSuccess {
+		t.Fatalf("failed to close card: %s", result.Stdout)
+	}
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	t.Run("exports the account", func(t *testing.T) {
+		result := h.Run("export", "account", "--out", archive)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		counts, _ := result.GetDataMap()["counts"].(map[string]interface{})
+		for _, kind := range []string{"boards", "columns", "cards", "steps", "comments", "reactions", "attachments", "users", "tags"} {
+			if n, _ := counts[kind].(float64); n < 1 {
+				t.Errorf("expected some %s, got %v", kind, counts[kind])
+			}
+		}
+		if _, err := os.Stat(archive); err != nil {
+			t.Fatalf("expected archive to be written: %v", err)
+		}
+	})
+
+	var newNumber string
+	var boards map[string]interface{}
+
+	t.Run("imports the archive", func(t *testing.T) {
+		result := h.Run("import", "account", archive)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		ids := result.GetDataMap()["ids"].(map[string]interface{})
+		boards = ids["boards"].(map[string]interface{})
+		for _, id := range boards {
+			h.Cleanup.AddBoard(id.(string))
+		}
+		if boards[boardID] == nil {
+			t.Fatalf("expected board %s in the mapping, got %v", boardID, boards)
+		}
+		newNumber, _ = ids["cards"].(map[string]interface{})[number].(string)
+		if newNumber == "" || newNumber == number {
+			t.Fatalf("expected a new number for card %s, got %v", number, ids["cards"])
+		}
+		closedNumber, _ := ids["cards"].(map[string]interface{})[strconv.Itoa(closed)].(string)
+		if show := h.Run("card", "show", closedNumber); !show.GetDataBool("closed") {
+			t.Errorf("expected card %s to be closed", closedNumber)
+		}
+	})
+	if newNumber == "" {
+		return
+	}
+
+	t.Run("recreates the card", func(t *testing.T) {
+		show := h.Run("card", "show", newNumber)
+		data := show.GetDataMap()
+		if data["title"] != title || !sameTime(data["created_at"], createdAt) {
+			t.Errorf("expected title and created_at to be kept, got %v %v", data["title"], data["created_at"])
+		}
+		if board, _ := data["board"].(map[string]interface{}); board["id"] != boards[boardID] {
+			t.Errorf("expected the card on the new board, got %v", board["id"])
+		}
+		if column, _ := data["column"].(map[string]interface{}); column["name"] != "Doing" || column["id"] == columnID {
+			t.Errorf("expected the card in the new Doing column, got %v", column)
+		}
+		if tags := fmt.Sprint(data["tags"]); tags != "[archived]" {
+			t.Errorf("expected tags [archived], got %s", tags)
+		}
+		steps, _ := data["steps"].([]interface{})
+		if len(steps) != 1 || steps[0].(map[string]interface{})["completed"] != true {
+			t.Errorf("expected a completed step, got %v", steps)
+		}
+		description, _ := data["description_html"].(string)
+		if !strings.Contains(description, "test_image.png") || !strings.Contains(description, "See attached") {
+			t.Errorf("expected the attachment to be uploaded again, got %q", description)
+		}
+
+		comments := h.Run("comment", "list", "--card", newNumber).GetDataArray()
+		if len(comments) != 1 {
+			t.Fatalf("expected 1 comment, got %d", len(comments))
+		}
+		comment := comments[0].(map[string]interface{})
+		if !sameTime(comment["created_at"], "2023-03-02T09:00:00Z") {
+			t.Errorf("expected the comment's created_at to be kept, got %v", comment["created_at"])
+		}
+		reactions := h.Run("reaction", "list", "--card", newNumber, "--comment", comment["id"].(string)).GetDataArray()
+		if len(reactions) != 1 {
+			t.Errorf("expected 1 reaction, got %d", len(reactions))
+		}
+	})
+
+	t.Run("resumes without duplicating", func(t *testing.T) {
+		before := len(h.Run("board", "list", "--all").GetDataArray())
+		result := h.Run("import", "account", archive)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		if !result.GetDataBool("resumed") {
+			t.Error("expected the import to resume from its state file")
+		}
+		if after := len(h.Run("board", "list", "--all").GetDataArray()); after != before {
+			t.Errorf("expected no new boards, got %d then %d", before, after)
+		}
+	})
+}
+
+// sameTime reports whether a timestamp from a response is the given time.
+func sameTime(value interface{}, want string) bool {
+	s, _ := value.(string)
+	got, err := time.Parse(time.RFC3339, s)
+	expected, _ := time.Parse(time.RFC3339, want)
+	return err == nil && got.Equal(expected)
+}
+
+func TestImportCards(t *testing.T) {
+	h := harness.New(t)
+	defer h.Cleanup.CleanupAll(h)
+
+	boardID := createTestBoard(t, h)
+	file := filepath.Join(t.TempDir(), "plan.md")
+	checklist := `## Ideas
+
+- [ ] Imported idea #imported
+  From the plan.
+  - [x] Sketch
+  - [ ] Build
+
+## Shipped
+
+- [x] Imported release
+`
+	if err := os.WriteFile(file, []byte(checklist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("dry run lists the cards", func(t *testing.T) {
+		result := h.Run("import", "cards", file, "--from", "markdown", "--board", boardID, "--dry-run")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		if cards, _ := result.GetDataMap()["cards"].([]interface{}); len(cards) != 2 {
+			t.Errorf("expected 2 cards, got %v", cards)
+		}
+		if columns := h.Run("column", "list", "--board", boardID).GetDataArray(); hasColumn(columns, "Ideas") {
+			t.Error("expected the dry run not to create columns")
+		}
+	})
+
+	var numbers []string
+	t.Run("imports the cards", func(t *testing.T) {
+		result := h.Run("import", "cards", file, "--from", "markdown", "--board", boardID)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		cards, _ := result.GetDataMap()["cards"].([]interface{})
+		for _, card := range cards {
+			number, _ := card.(map[string]interface{})["number"].(string)
+			if n, err := strconv.Atoi(number); err == nil {
+				h.Cleanup.AddCard(n)
+			}
+			numbers = append(numbers, number)
+		}
+		if len(numbers) != 2 {
+			t.Fatalf("expected 2 cards, got %v", cards)
+		}
+
+		columns := h.Run("column", "list", "--board", boardID).GetDataArray()
+		if !hasColumn(columns, "Ideas") || !hasColumn(columns, "Shipped") {
+			t.Errorf("expected the Ideas and Shipped columns, got %v", columns)
+		}
+
+		data := h.Run("card", "show", numbers[0]).GetDataMap()
+		if column, _ := data["column"].(map[string]interface{}); column["name"] != "Ideas" {
+			t.Errorf("expected the card in Ideas, got %v", data["column"])
+		}
+		if tags := fmt.Sprint(data["tags"]); tags != "[imported]" {
+			t.Errorf("expected tags [imported], got %s", tags)
+		}
+		if steps, _ := data["steps"].([]interface{}); len(steps) != 2 {
+			t.Errorf("expected 2 steps, got %v", steps)
+		}
+		if !h.Run("card", "show", numbers[1]).GetDataBool("closed") {
+			t.Error("expected the checked card to be closed")
+		}
+	})
+
+	t.Run("skips imported cards when run again", func(t *testing.T) {
+		result := h.Run("import", "cards", file, "--from", "markdown", "--board", boardID)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s", harness.ExitSuccess, result.ExitCode, result.Stdout)
+		}
+		cards, _ := result.GetDataMap()["cards"].([]interface{})
+		for _, card := range cards {
+			if action := card.(map[string]interface{})["action"]; action != "skipped" {
+				t.Errorf("expected the card to be skipped, got %v", action)
+			}
+		}
+	})
+}
+
+func hasColumn(columns []interface{}, name string) bool {
+	for _, column := range columns {
+		if column.(map[string]interface{})["name"] == name {
+			return true
+		}
+	}
+	return false
+}
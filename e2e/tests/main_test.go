@@ -7,43 +7,54 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/robzolkos/fizzy-cli/e2e/fakeserver"
 	"github.com/robzolkos/fizzy-cli/e2e/harness"
 )
 
 func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
 	cfg := harness.LoadConfig()
 
+	// Without live credentials, run against the in-memory fake server.
+	if cfg.Token == "" && cfg.Account == "" {
+		srv := fakeserver.New()
+		defer srv.Close()
+
+		_ = os.Setenv("FIZZY_TEST_TOKEN", srv.Token)
+		_ = os.Setenv("FIZZY_TEST_ACCOUNT", srv.Account)
+		_ = os.Setenv("FIZZY_TEST_API_URL", srv.URL)
+	}
+
 	if cfg.BinaryPath == "" || !fileExists(cfg.BinaryPath) {
 		repoRoot, err := harness.RepoRoot()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
+			return 1
 		}
 
 		tmpDir, err := os.MkdirTemp("", "fizzy-e2e-*")
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
+			return 1
 		}
+		defer os.RemoveAll(tmpDir)
 
 		binPath := filepath.Join(tmpDir, "fizzy")
 		cmd := exec.Command("go", "build", "-o", binPath, "./cmd/fizzy")
 		cmd.Dir = repoRoot
 		if out, err := cmd.CombinedOutput(); err != nil {
-			_ = os.RemoveAll(tmpDir)
 			fmt.Fprintf(os.Stderr, "failed to build e2e binary: %v\n%s\n", err, string(out))
-			os.Exit(1)
+			return 1
 		}
 
 		_ = os.Setenv("FIZZY_TEST_BINARY", binPath)
 		cfg.BinaryPath = binPath
-
-		code := m.Run()
-		_ = os.RemoveAll(tmpDir)
-		os.Exit(code)
 	}
 
-	os.Exit(m.Run())
+	return m.Run()
 }
 
 func fileExists(path string) bool {
@@ -1,6 +1,12 @@
 package tests
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/e2e/harness"
@@ -148,3 +154,125 @@ func TestNotificationReadNotFound(t *testing.T) {
 		}
 	})
 }
+
+func TestNotificationWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("--exec test uses a POSIX shell")
+	}
+	h := harness.New(t)
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state.json")
+	received := filepath.Join(dir, "received.ndjson")
+
+	// splitLines returns the non-empty lines of NDJSON output, each of which
+	// must be a notification.
+	splitLines := func(t *testing.T, output string) []string {
+		t.Helper()
+		var lines []string
+		for _, line := range strings.Split(output, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			var n map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &n); err != nil || n["id"] == nil {
+				t.Fatalf("expected a notification per line, got %q", line)
+			}
+			if n["read"] != false {
+				t.Errorf("expected only unread notifications, got %v", n)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	var printed []string
+	t.Run("prints unread notifications", func(t *testing.T) {
+		result := h.Run("notification", "watch", "--once", "--state", state, "--exec", "cat >> "+received)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstdout: %s\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stdout, result.Stderr)
+		}
+		printed = splitLines(t, result.Stdout)
+		data, _ := os.ReadFile(received)
+		if got := splitLines(t, string(data)); len(got) != len(printed) {
+			t.Errorf("expected the command to get %d notifications, got %d", len(printed), len(got))
+		}
+		if _, err := os.Stat(state); err != nil && len(printed) > 0 {
+			t.Errorf("expected a state file: %v", err)
+		}
+	})
+
+	t.Run("prints nothing already seen", func(t *testing.T) {
+		result := h.Run("notification", "watch", "--once", "--state", state)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		if lines := splitLines(t, result.Stdout); len(lines) != 0 {
+			t.Errorf("expected no notifications, got %v", lines)
+		}
+	})
+}
+
+func TestNotificationListFilters(t *testing.T) {
+	h := harness.New(t)
+
+	t.Run("filters unread notifications", func(t *testing.T) {
+		result := h.Run("notification", "list", "--all", "--unread", "--since", "30d")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		for _, item := range result.GetDataArray() {
+			n := item.(map[string]interface{})
+			if n["read"] != false {
+				t.Errorf("expected an unread notification, got %v", n)
+			}
+		}
+	})
+
+	t.Run("groups by card", func(t *testing.T) {
+		result := h.Run("notification", "list", "--all", "--group-by", "card")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		for _, item := range result.GetDataArray() {
+			group := item.(map[string]interface{})
+			if count, _ := group["count"].(float64); count < 1 {
+				t.Errorf("expected a count, got %v", group)
+			}
+		}
+	})
+
+	t.Run("filters without --all", func(t *testing.T) {
+		result := h.Run("notification", "list", "--unread")
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		for _, item := range result.GetDataArray() {
+			if read, _ := item.(map[string]interface{})["read"].(bool); read {
+				t.Errorf("expected only unread notifications, got %v", item)
+			}
+		}
+	})
+
+	t.Run("marks a card's notifications as read", func(t *testing.T) {
+		list := h.Run("notification", "list", "--all")
+		var number string
+		for _, item := range list.GetDataArray() {
+			if card, ok := item.(map[string]interface{})["card"].(map[string]interface{}); ok {
+				number = fmt.Sprintf("%d", int(card["number"].(float64)))
+				break
+			}
+		}
+		if number == "" {
+			t.Skip("no notifications about cards available to test")
+		}
+
+		result := h.Run("notification", "read", "--card", number)
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", harness.ExitSuccess, result.ExitCode, result.Stderr)
+		}
+		unread := h.Run("notification", "list", "--all", "--unread", "--card", number)
+		if n := len(unread.GetDataArray()); n != 0 {
+			t.Errorf("expected no unread notifications about card %s, got %d", number, n)
+		}
+	})
+}
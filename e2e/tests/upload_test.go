@@ -78,6 +78,42 @@ func TestUploadTextFile(t *testing.T) {
 	})
 }
 
+func TestUploadMultipleFiles(t *testing.T) {
+	h := harness.New(t)
+
+	wd, _ := os.Getwd()
+	image := filepath.Join(wd, "..", "testdata", "fixtures", "test_image.png")
+	document := filepath.Join(wd, "..", "testdata", "fixtures", "test_document.txt")
+	for _, path := range []string{image, document} {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Skipf("test fixture not found at %s", path)
+		}
+	}
+
+	t.Run("uploads each file and returns results in order", func(t *testing.T) {
+		result := h.Run("upload", "file", image, document)
+
+		if result.ExitCode != harness.ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s\nstdout: %s",
+				harness.ExitSuccess, result.ExitCode, result.Stderr, result.Stdout)
+		}
+
+		uploads := result.GetDataArray()
+		if len(uploads) != 2 {
+			t.Fatalf("expected 2 uploads, got %d", len(uploads))
+		}
+		for i, path := range []string{image, document} {
+			upload, _ := uploads[i].(map[string]interface{})
+			if upload["file"] != path {
+				t.Errorf("expected upload %d to be %s, got %v", i, path, upload["file"])
+			}
+			if upload["signed_id"] == nil || upload["signed_id"] == "" {
+				t.Errorf("expected signed_id for %s", path)
+			}
+		}
+	})
+}
+
 func TestUploadFileNotFound(t *testing.T) {
 	h := harness.New(t)
 
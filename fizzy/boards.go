@@ -0,0 +1,114 @@
+package fizzy
+
+import (
+	"context"
+	"iter"
+)
+
+// BoardsService manages boards.
+type BoardsService struct{ c *Client }
+
+// BoardParams are the fields for creating or updating a board. Nil and empty
+// fields are left unchanged on update.
+type BoardParams struct {
+	Name               string `json:"name,omitempty"`
+	AllAccess          *bool  `json:"all_access,omitempty"`
+	AutoPostponePeriod int    `json:"auto_postpone_period,omitempty"`
+}
+
+// List returns every board in the account.
+func (s *BoardsService) List(ctx context.Context) ([]Board, error) {
+	return collect[Board](ctx, s.c, "/boards.json")
+}
+
+// All iterates over the boards in the account, fetching pages as needed.
+func (s *BoardsService) All(ctx context.Context) iter.Seq2[Board, error] {
+	return each[Board](ctx, s.c, "/boards.json")
+}
+
+// Get returns a board by ID.
+func (s *BoardsService) Get(ctx context.Context, id string) (*Board, error) {
+	var board Board
+	if err := s.c.get(ctx, "/boards/"+id+".json", &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// Create creates a board.
+func (s *BoardsService) Create(ctx context.Context, params BoardParams) (*Board, error) {
+	var board Board
+	if err := s.c.create(ctx, "/boards.json", map[string]interface{}{"board": params}, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// Update changes a board and returns it.
+func (s *BoardsService) Update(ctx context.Context, id string, params BoardParams) (*Board, error) {
+	var board Board
+	if err := s.c.update(ctx, "/boards/"+id+".json", map[string]interface{}{"board": params}, &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// Delete deletes a board and its cards.
+func (s *BoardsService) Delete(ctx context.Context, id string) error {
+	return s.c.delete(ctx, "/boards/"+id+".json")
+}
+
+// ColumnsService manages the columns of a board.
+type ColumnsService struct{ c *Client }
+
+// ColumnParams are the fields for creating or updating a column. Color is a
+// CSS value such as "var(--color-card-3)".
+type ColumnParams struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// List returns the columns of a board in board order.
+func (s *ColumnsService) List(ctx context.Context, boardID string) ([]Column, error) {
+	var columns []Column
+	if err := s.c.get(ctx, "/boards/"+boardID+"/columns.json", &columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// Get returns a column by ID.
+func (s *ColumnsService) Get(ctx context.Context, boardID, id string) (*Column, error) {
+	var column Column
+	if err := s.c.get(ctx, columnPath(boardID, id), &column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// Create adds a column to a board.
+func (s *ColumnsService) Create(ctx context.Context, boardID string, params ColumnParams) (*Column, error) {
+	var column Column
+	if err := s.c.create(ctx, "/boards/"+boardID+"/columns.json", map[string]interface{}{"column": params}, &column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// Update changes a column and returns it.
+func (s *ColumnsService) Update(ctx context.Context, boardID, id string, params ColumnParams) (*Column, error) {
+	var column Column
+	if err := s.c.update(ctx, columnPath(boardID, id), map[string]interface{}{"column": params}, &column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// Delete removes a column. Its cards go back to triage.
+func (s *ColumnsService) Delete(ctx context.Context, boardID, id string) error {
+	return s.c.delete(ctx, columnPath(boardID, id))
+}
+
+func columnPath(boardID, id string) string {
+	return "/boards/" + boardID + "/columns/" + id + ".json"
+}
@@ -0,0 +1,186 @@
+package fizzy
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CardsService manages cards and their workflow actions.
+type CardsService struct{ c *Client }
+
+// Card list indexes accepted by CardFilter.IndexedBy.
+const (
+	IndexClosed = "closed"
+	IndexNotNow = "not_now"
+	IndexGolden = "golden"
+	IndexAll    = "all"
+)
+
+// CardFilter narrows a card listing. The zero value lists open cards in
+// every board.
+type CardFilter struct {
+	BoardIDs    []string
+	TagIDs      []string
+	AssigneeIDs []string
+	Terms       []string
+
+	// IndexedBy selects a card index, e.g. IndexClosed. Empty lists open,
+	// triaged and untriaged cards.
+	IndexedBy string
+}
+
+func (f CardFilter) path() string {
+	var params []string
+	add := func(key string, values []string) {
+		for _, v := range values {
+			params = append(params, key+"="+url.QueryEscape(v))
+		}
+	}
+	add("board_ids[]", f.BoardIDs)
+	add("tag_ids[]", f.TagIDs)
+	add("assignee_ids[]", f.AssigneeIDs)
+	add("terms[]", f.Terms)
+	if f.IndexedBy != "" {
+		params = append(params, "indexed_by="+url.QueryEscape(f.IndexedBy))
+	}
+	if len(params) == 0 {
+		return "/cards.json"
+	}
+	return "/cards.json?" + strings.Join(params, "&")
+}
+
+// CardParams are the fields for creating or updating a card. Empty fields are
+// left unchanged on update.
+type CardParams struct {
+	Title       string
+	Description string // HTML
+	TagIDs      []string
+	Image       string // signed_id of an uploaded file
+	CreatedAt   time.Time
+}
+
+func (p CardParams) body() map[string]interface{} {
+	card := map[string]interface{}{}
+	if p.Title != "" {
+		card["title"] = p.Title
+	}
+	if p.Description != "" {
+		card["description"] = p.Description
+	}
+	if len(p.TagIDs) > 0 {
+		card["tag_ids"] = strings.Join(p.TagIDs, ",")
+	}
+	if p.Image != "" {
+		card["image"] = p.Image
+	}
+	if !p.CreatedAt.IsZero() {
+		card["created_at"] = p.CreatedAt.Format(time.RFC3339)
+	}
+	return card
+}
+
+// List returns every card matching filter.
+func (s *CardsService) List(ctx context.Context, filter CardFilter) ([]Card, error) {
+	return collect[Card](ctx, s.c, filter.path())
+}
+
+// All iterates over the cards matching filter, fetching pages as needed.
+func (s *CardsService) All(ctx context.Context, filter CardFilter) iter.Seq2[Card, error] {
+	return each[Card](ctx, s.c, filter.path())
+}
+
+// Get returns a card, including its steps.
+func (s *CardsService) Get(ctx context.Context, number int) (*Card, error) {
+	var card Card
+	if err := s.c.get(ctx, cardPath(number, ""), &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// Create creates a card on a board.
+func (s *CardsService) Create(ctx context.Context, boardID string, params CardParams) (*Card, error) {
+	body := map[string]interface{}{
+		"board_id": boardID,
+		"card":     params.body(),
+	}
+	var card Card
+	if err := s.c.create(ctx, "/cards.json", body, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// Update changes a card and returns it.
+func (s *CardsService) Update(ctx context.Context, number int, params CardParams) (*Card, error) {
+	var card Card
+	if err := s.c.update(ctx, cardPath(number, ""), map[string]interface{}{"card": params.body()}, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// Delete deletes a card.
+func (s *CardsService) Delete(ctx context.Context, number int) error {
+	return s.c.delete(ctx, cardPath(number, ""))
+}
+
+// Close moves a card to Done.
+func (s *CardsService) Close(ctx context.Context, number int) error {
+	return s.c.post(ctx, cardPath(number, "closure"), nil)
+}
+
+// Reopen moves a closed card back to the board.
+func (s *CardsService) Reopen(ctx context.Context, number int) error {
+	return s.c.delete(ctx, cardPath(number, "closure"))
+}
+
+// Postpone moves a card to Not Now.
+func (s *CardsService) Postpone(ctx context.Context, number int) error {
+	return s.c.post(ctx, cardPath(number, "not_now"), nil)
+}
+
+// Triage moves a card into a column.
+func (s *CardsService) Triage(ctx context.Context, number int, columnID string) error {
+	return s.c.post(ctx, cardPath(number, "triage"), map[string]interface{}{"column_id": columnID})
+}
+
+// Untriage sends a card back to Maybe?.
+func (s *CardsService) Untriage(ctx context.Context, number int) error {
+	return s.c.delete(ctx, cardPath(number, "triage"))
+}
+
+// ToggleTag adds a tag to a card, or removes it if already present. The tag
+// is created if it does not exist.
+func (s *CardsService) ToggleTag(ctx context.Context, number int, title string) error {
+	return s.c.post(ctx, cardPath(number, "taggings"), map[string]interface{}{"tag_title": title})
+}
+
+// ToggleAssignee assigns a user to a card, or unassigns them if already
+// assigned.
+func (s *CardsService) ToggleAssignee(ctx context.Context, number int, userID string) error {
+	return s.c.post(ctx, cardPath(number, "assignments"), map[string]interface{}{"assignee_id": userID})
+}
+
+// Watch subscribes the current user to a card.
+func (s *CardsService) Watch(ctx context.Context, number int) error {
+	return s.c.post(ctx, cardPath(number, "watch"), nil)
+}
+
+// Unwatch unsubscribes the current user from a card.
+func (s *CardsService) Unwatch(ctx context.Context, number int) error {
+	return s.c.delete(ctx, cardPath(number, "watch"))
+}
+
+// cardPath returns the path of a card, or of one of its sub-resources.
+func cardPath(number int, sub string) string {
+	path := "/cards/" + strconv.Itoa(number)
+	if sub != "" {
+		path += "/" + sub
+	}
+	return path + ".json"
+}
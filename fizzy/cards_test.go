@@ -0,0 +1,91 @@
+package fizzy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCardFilterPath(t *testing.T) {
+	tests := []struct {
+		filter   CardFilter
+		expected string
+	}{
+		{CardFilter{}, "/cards.json"},
+		{CardFilter{BoardIDs: []string{"b1", "b2"}}, "/cards.json?board_ids[]=b1&board_ids[]=b2"},
+		{CardFilter{Terms: []string{"login bug"}, IndexedBy: IndexClosed}, "/cards.json?terms[]=login+bug&indexed_by=closed"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.path(); got != tt.expected {
+			t.Errorf("path() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestCardWorkflow(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	board, err := c.Boards.Create(ctx, BoardParams{Name: "Workflow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	column, err := c.Columns.Create(ctx, board.ID, ColumnParams{Name: "Doing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card, err := c.Cards.Create(ctx, board.ID, CardParams{Title: "Ship it", Description: "<p>Soon</p>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Number == 0 || card.Description != "Soon" || card.Board.ID != board.ID {
+		t.Fatalf("unexpected card %+v", card)
+	}
+
+	if err := c.Cards.Triage(ctx, card.Number, column.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Cards.ToggleTag(ctx, card.Number, "release"); err != nil {
+		t.Fatal(err)
+	}
+	step, err := c.Steps.Create(ctx, card.Number, StepParams{Content: "Write notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card, err = c.Cards.Get(ctx, card.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.ColumnID() != column.ID {
+		t.Errorf("expected card in column %s, got %q", column.ID, card.ColumnID())
+	}
+	if len(card.Tags) != 1 || card.Tags[0] != "release" {
+		t.Errorf("expected release tag, got %v", card.Tags)
+	}
+	if len(card.Steps) != 1 || card.Steps[0].ID != step.ID {
+		t.Errorf("expected step on card, got %v", card.Steps)
+	}
+
+	open, err := c.Cards.List(ctx, CardFilter{BoardIDs: []string{board.ID}})
+	if err != nil || len(open) != 1 {
+		t.Fatalf("expected 1 open card, got %v (%v)", open, err)
+	}
+
+	if err := c.Cards.Close(ctx, card.Number); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := c.Cards.List(ctx, CardFilter{BoardIDs: []string{board.ID}, IndexedBy: IndexClosed})
+	if err != nil || len(closed) != 1 || !closed[0].Closed {
+		t.Errorf("expected 1 closed card, got %v (%v)", closed, err)
+	}
+
+	comment, err := c.Comments.Create(ctx, card.Number, "Done!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.Body.PlainText != "Done!" {
+		t.Errorf("unexpected comment %+v", comment)
+	}
+}
@@ -0,0 +1,84 @@
+package fizzy
+
+import (
+	"context"
+	"iter"
+	"strconv"
+)
+
+// CommentsService manages comments on cards.
+type CommentsService struct{ c *Client }
+
+// List returns every comment on a card.
+func (s *CommentsService) List(ctx context.Context, cardNumber int) ([]Comment, error) {
+	return collect[Comment](ctx, s.c, cardPath(cardNumber, "comments"))
+}
+
+// All iterates over the comments on a card, fetching pages as needed.
+func (s *CommentsService) All(ctx context.Context, cardNumber int) iter.Seq2[Comment, error] {
+	return each[Comment](ctx, s.c, cardPath(cardNumber, "comments"))
+}
+
+// Get returns a comment.
+func (s *CommentsService) Get(ctx context.Context, cardNumber int, id string) (*Comment, error) {
+	var comment Comment
+	if err := s.c.get(ctx, commentPath(cardNumber, id, ""), &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Create adds a comment with an HTML body to a card.
+func (s *CommentsService) Create(ctx context.Context, cardNumber int, body string) (*Comment, error) {
+	params := map[string]interface{}{"comment": map[string]interface{}{"body": body}}
+	var comment Comment
+	if err := s.c.create(ctx, cardPath(cardNumber, "comments"), params, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Update replaces the body of a comment.
+func (s *CommentsService) Update(ctx context.Context, cardNumber int, id, body string) (*Comment, error) {
+	params := map[string]interface{}{"comment": map[string]interface{}{"body": body}}
+	var comment Comment
+	if err := s.c.update(ctx, commentPath(cardNumber, id, ""), params, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Delete deletes a comment.
+func (s *CommentsService) Delete(ctx context.Context, cardNumber int, id string) error {
+	return s.c.delete(ctx, commentPath(cardNumber, id, ""))
+}
+
+// ReactionsService manages reactions to comments.
+type ReactionsService struct{ c *Client }
+
+// List returns the reactions to a comment.
+func (s *ReactionsService) List(ctx context.Context, cardNumber int, commentID string) ([]Reaction, error) {
+	var reactions []Reaction
+	if err := s.c.get(ctx, commentPath(cardNumber, commentID, "reactions"), &reactions); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+// Create reacts to a comment. The API does not return the new reaction.
+func (s *ReactionsService) Create(ctx context.Context, cardNumber int, commentID, content string) error {
+	return s.c.post(ctx, commentPath(cardNumber, commentID, "reactions"), map[string]interface{}{"content": content})
+}
+
+// Delete removes a reaction.
+func (s *ReactionsService) Delete(ctx context.Context, cardNumber int, commentID, id string) error {
+	return s.c.delete(ctx, commentPath(cardNumber, commentID, "reactions/"+id))
+}
+
+func commentPath(cardNumber int, id, sub string) string {
+	path := "/cards/" + strconv.Itoa(cardNumber) + "/comments/" + id
+	if sub != "" {
+		path += "/" + sub
+	}
+	return path + ".json"
+}
@@ -0,0 +1,245 @@
+// Package fizzy is a typed Go client for the Fizzy API.
+//
+// It wraps the same HTTP client the CLI uses, so requests get the same
+// account prefixing, authentication, retries and error mapping:
+//
+//	c := fizzy.New("https://app.fizzy.do", token, "897362094")
+//	cards, err := c.Cards.List(ctx, fizzy.CardFilter{BoardIDs: []string{boardID}})
+//
+// Options such as WithTimeout and WithRetryPolicy change how requests are
+// made.
+//
+// List methods follow Link headers and return every page. Use the matching
+// All method to iterate lazily instead.
+package fizzy
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// DefaultBaseURL is the base URL of the hosted Fizzy API.
+const DefaultBaseURL = "https://app.fizzy.do"
+
+// Error is returned for failed requests. Status holds the HTTP status code
+// when the API responded, and Code a stable identifier such as "NOT_FOUND".
+// Requests stopped by their context fail with Code "CANCELLED".
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// apiError converts an error from the underlying client to an *Error.
+func apiError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var cliErr *errors.CLIError
+	if stderrors.As(err, &cliErr) {
+		return &Error{Status: cliErr.Status, Code: cliErr.Code, Message: cliErr.Message}
+	}
+	return &Error{Code: "ERROR", Message: err.Error()}
+}
+
+// RetryPolicy controls how requests that were rate limited or found the
+// API unavailable are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values of 1 or less disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the initial backoff delay, doubled on each attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay caps both the computed backoff and any Retry-After value.
+	MaxDelay time.Duration
+
+	// RetryNonIdempotent also retries POST and PATCH requests.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by New.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy(client.DefaultRetryPolicy())
+}
+
+// Option configures a Client made by New.
+type Option func(*client.Client)
+
+// WithHTTPClient makes requests with hc instead of a client with the
+// default timeout. A nil hc is ignored.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *client.Client) {
+		if hc != nil {
+			c.HTTPClient = hc
+		}
+	}
+}
+
+// WithTimeout limits each request to d; zero means no limit. File
+// transfers are never limited.
+func WithTimeout(d time.Duration) Option {
+	return func(c *client.Client) {
+		hc := *c.HTTPClient
+		hc.Timeout = d
+		c.HTTPClient = &hc
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *client.Client) {
+		c.Retry = client.RetryPolicy(p)
+	}
+}
+
+// Client is a Fizzy API client scoped to one account.
+type Client struct {
+	api     client.API
+	baseURL string
+
+	Boards        *BoardsService
+	Columns       *ColumnsService
+	Cards         *CardsService
+	Comments      *CommentsService
+	Reactions     *ReactionsService
+	Steps         *StepsService
+	Users         *UsersService
+	Tags          *TagsService
+	Notifications *NotificationsService
+	Identity      *IdentityService
+}
+
+// New returns a client for the account at baseURL authenticated with token.
+func New(baseURL, token, account string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	c := client.New(baseURL, token, account)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return newClient(c, c.BaseURL)
+}
+
+func newClient(api client.API, baseURL string) *Client {
+	c := &Client{api: api, baseURL: baseURL}
+	c.Boards = &BoardsService{c}
+	c.Columns = &ColumnsService{c}
+	c.Cards = &CardsService{c}
+	c.Comments = &CommentsService{c}
+	c.Reactions = &ReactionsService{c}
+	c.Steps = &StepsService{c}
+	c.Users = &UsersService{c}
+	c.Tags = &TagsService{c}
+	c.Notifications = &NotificationsService{c}
+	c.Identity = &IdentityService{c}
+	return c
+}
+
+// get fetches path and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	resp, err := c.api.GetContext(ctx, path)
+	if err != nil {
+		return apiError(err)
+	}
+	return decode(resp, v)
+}
+
+// create posts body to path and fetches the created resource from the
+// Location header into v.
+func (c *Client) create(ctx context.Context, path string, body, v interface{}) error {
+	resp, err := c.api.PostContext(ctx, path, body)
+	if err != nil {
+		return apiError(err)
+	}
+	if resp.Location == "" {
+		return decode(resp, v)
+	}
+	return c.get(ctx, resp.Location, v)
+}
+
+// update patches path and decodes the updated resource into v, fetching it
+// from path when the API answers without a body.
+func (c *Client) update(ctx context.Context, path string, body, v interface{}) error {
+	resp, err := c.api.PatchContext(ctx, path, body)
+	if err != nil {
+		return apiError(err)
+	}
+	if len(resp.Body) == 0 {
+		return c.get(ctx, path, v)
+	}
+	return decode(resp, v)
+}
+
+// post sends an action request whose response body is ignored.
+func (c *Client) post(ctx context.Context, path string, body interface{}) error {
+	_, err := c.api.PostContext(ctx, path, body)
+	return apiError(err)
+}
+
+func (c *Client) delete(ctx context.Context, path string) error {
+	_, err := c.api.DeleteContext(ctx, path)
+	return apiError(err)
+}
+
+func decode(resp *client.APIResponse, v interface{}) error {
+	if v == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return &Error{Code: "ERROR", Message: fmt.Sprintf("Failed to decode response: %v", err)}
+	}
+	return nil
+}
+
+// each iterates the items of a paginated list, fetching the next page when
+// the current one is exhausted.
+func each[T any](ctx context.Context, c *Client, path string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for next := path; next != ""; {
+			resp, err := c.api.GetContext(ctx, next)
+			if err != nil {
+				yield(zero, apiError(err))
+				return
+			}
+			var page []T
+			if err := decode(resp, &page); err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			next = resp.LinkNext
+		}
+	}
+}
+
+// collect gathers every item of a paginated list.
+func collect[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	items := []T{}
+	for item, err := range each[T](ctx, c, path) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
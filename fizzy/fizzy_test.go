@@ -0,0 +1,142 @@
+package fizzy
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/e2e/fakeserver"
+	"github.com/robzolkos/fizzy-cli/internal/client"
+)
+
+func newTestClient(t *testing.T) (*Client, *fakeserver.Server) {
+	t.Helper()
+	srv := fakeserver.New()
+	t.Cleanup(srv.Close)
+	return New(srv.URL, srv.Token, srv.Account), srv
+}
+
+func TestBoardsListFollowsPages(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.PageSize = 2
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Boards.Create(ctx, BoardParams{Name: fmt.Sprintf("Board %d", i)}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	boards, err := c.Boards.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(boards) != 4 {
+		t.Fatalf("expected 4 boards across pages, got %d", len(boards))
+	}
+	if boards[3].Name != "Board 2" || boards[3].Creator == nil {
+		t.Errorf("unexpected last board %+v", boards[3])
+	}
+
+	var seen int
+	for _, err := range c.Boards.All(ctx) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Errorf("expected iteration to stop after 3 boards, got %d", seen)
+	}
+}
+
+func TestCreateFetchesLocation(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	board, err := c.Boards.Create(ctx, BoardParams{Name: "Roadmap"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if board.ID == "" || board.Name != "Roadmap" {
+		t.Fatalf("expected created board, got %+v", board)
+	}
+
+	column, err := c.Columns.Create(ctx, board.ID, ColumnParams{Name: "Doing", Color: "var(--color-card-3)"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if column.Color.Name != "Yellow" {
+		t.Errorf("expected Yellow column, got %+v", column.Color)
+	}
+
+	updated, err := c.Boards.Update(ctx, board.ID, BoardParams{Name: "Roadmap 2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Name != "Roadmap 2" {
+		t.Errorf("expected updated board after a 204, got %+v", updated)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	_, err := c.Cards.Get(context.Background(), 999999)
+	var apiErr *Error
+	if !stderrors.As(err, &apiErr) || apiErr.Status != 404 {
+		t.Fatalf("expected 404 Error, got %v", err)
+	}
+
+	bad := New(c.baseURL, "wrong", fakeserver.DefaultAccount)
+	_, err = bad.Boards.List(context.Background())
+	if !stderrors.As(err, &apiErr) || apiErr.Code != "AUTH_ERROR" {
+		t.Errorf("expected auth error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Boards.List(ctx); !stderrors.As(err, &apiErr) || apiErr.Code != "CANCELLED" {
+		t.Errorf("expected cancelled error, got %v", err)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	identity, err := c.Identity.Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(identity.Accounts) != 1 || identity.Accounts[0].User.ID != srv.CurrentUserID() {
+		t.Errorf("unexpected identity %+v", identity)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	hc := &http.Client{}
+	c := New("", "token", "account",
+		WithHTTPClient(hc),
+		WithTimeout(5*time.Second),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	api := c.api.(*client.Client)
+	if api.HTTPClient.Timeout != 5*time.Second {
+		t.Errorf("expected a 5s timeout, got %v", api.HTTPClient.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("expected the given HTTP client to be left alone, got timeout %v", hc.Timeout)
+	}
+	if api.Retry.MaxAttempts != 1 {
+		t.Errorf("expected retries to be disabled, got %+v", api.Retry)
+	}
+	if c.baseURL != DefaultBaseURL {
+		t.Errorf("expected the default base URL, got %q", c.baseURL)
+	}
+}
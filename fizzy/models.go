@@ -0,0 +1,173 @@
+package fizzy
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User is a member of an account.
+type User struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	EmailAddress string    `json:"email_address"`
+	AvatarURL    string    `json:"avatar_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url"`
+}
+
+// Board is a collection of cards organised into columns.
+type Board struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	AllAccess          bool      `json:"all_access"`
+	AutoPostponePeriod int       `json:"auto_postpone_period,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	URL                string    `json:"url"`
+	Creator            *User     `json:"creator,omitempty"`
+}
+
+// Color is a column color as a display name and CSS value.
+type Color struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Column is a workflow stage on a board.
+type Column struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     Color     `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Card is a unit of work on a board. Cards are addressed by Number.
+type Card struct {
+	ID               string    `json:"id"`
+	Number           int       `json:"number"`
+	Title            string    `json:"title"`
+	Status           string    `json:"status"`
+	Description      string    `json:"description"`
+	DescriptionHTML  string    `json:"description_html"`
+	ImageURL         string    `json:"image_url,omitempty"`
+	Tags             []string  `json:"tags"`
+	Closed           bool      `json:"closed"`
+	Postponed        bool      `json:"postponed"`
+	Golden           bool      `json:"golden"`
+	Watching         bool      `json:"watching"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastActiveAt     time.Time `json:"last_active_at"`
+	URL              string    `json:"url"`
+	CommentsURL      string    `json:"comments_url"`
+	Board            *Board    `json:"board,omitempty"`
+	Column           *Column   `json:"column,omitempty"`
+	Creator          *User     `json:"creator,omitempty"`
+	Assignees        []User    `json:"assignees"`
+	HasMoreAssignees bool      `json:"has_more_assignees"`
+
+	// Steps is only included when fetching a single card.
+	Steps []Step `json:"steps,omitempty"`
+}
+
+// UnmarshalJSON decodes a card, accepting the column as either an embedded
+// object or a bare column_id.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	type plain Card
+	aux := struct {
+		*plain
+		ColumnID string `json:"column_id"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if c.Column == nil && aux.ColumnID != "" {
+		c.Column = &Column{ID: aux.ColumnID}
+	}
+	return nil
+}
+
+// ColumnID returns the ID of the card's column, or "" for cards that have
+// not been triaged.
+func (c *Card) ColumnID() string {
+	if c.Column == nil {
+		return ""
+	}
+	return c.Column.ID
+}
+
+// RichText is an Action Text body in plain text and HTML.
+type RichText struct {
+	PlainText string `json:"plain_text"`
+	HTML      string `json:"html"`
+}
+
+// CardRef is the short form of a card embedded in other resources.
+type CardRef struct {
+	ID     string `json:"id,omitempty"`
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+	URL    string `json:"url"`
+}
+
+// Comment is a comment on a card.
+type Comment struct {
+	ID           string    `json:"id"`
+	Body         RichText  `json:"body"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Creator      *User     `json:"creator,omitempty"`
+	Card         *CardRef  `json:"card,omitempty"`
+	ReactionsURL string    `json:"reactions_url"`
+	URL          string    `json:"url"`
+}
+
+// Reaction is an emoji or short text reaction to a comment.
+type Reaction struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Reacter *User  `json:"reacter,omitempty"`
+}
+
+// Step is a to-do item on a card.
+type Step struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	Completed bool   `json:"completed"`
+}
+
+// Tag is a label that can be applied to cards.
+type Tag struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	URL       string    `json:"url"`
+}
+
+// Notification tells the user about activity on a card.
+type Notification struct {
+	ID        string     `json:"id"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Creator   *User      `json:"creator,omitempty"`
+	Card      *CardRef   `json:"card,omitempty"`
+	URL       string     `json:"url"`
+}
+
+// Account is an account the authenticated user belongs to.
+type Account struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	User      *User     `json:"user,omitempty"`
+}
+
+// Identity lists the accounts the token can access.
+type Identity struct {
+	Accounts []Account `json:"accounts"`
+}
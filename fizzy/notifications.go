@@ -0,0 +1,34 @@
+package fizzy
+
+import (
+	"context"
+	"iter"
+)
+
+// NotificationsService manages the current user's notifications.
+type NotificationsService struct{ c *Client }
+
+// List returns every notification, newest first.
+func (s *NotificationsService) List(ctx context.Context) ([]Notification, error) {
+	return collect[Notification](ctx, s.c, "/notifications.json")
+}
+
+// All iterates over notifications, newest first, fetching pages as needed.
+func (s *NotificationsService) All(ctx context.Context) iter.Seq2[Notification, error] {
+	return each[Notification](ctx, s.c, "/notifications.json")
+}
+
+// Read marks a notification as read.
+func (s *NotificationsService) Read(ctx context.Context, id string) error {
+	return s.c.post(ctx, "/notifications/"+id+"/read.json", nil)
+}
+
+// Unread marks a notification as unread.
+func (s *NotificationsService) Unread(ctx context.Context, id string) error {
+	return s.c.post(ctx, "/notifications/"+id+"/unread.json", nil)
+}
+
+// ReadAll marks every notification as read.
+func (s *NotificationsService) ReadAll(ctx context.Context) error {
+	return s.c.post(ctx, "/notifications/bulk_reading.json", nil)
+}
@@ -0,0 +1,53 @@
+package fizzy
+
+import (
+	"context"
+	"strconv"
+)
+
+// StepsService manages the to-do steps of a card. Steps are listed with
+// CardsService.Get.
+type StepsService struct{ c *Client }
+
+// StepParams are the fields for creating or updating a step. A nil Completed
+// is left unchanged on update.
+type StepParams struct {
+	Content   string `json:"content,omitempty"`
+	Completed *bool  `json:"completed,omitempty"`
+}
+
+// Get returns a step.
+func (s *StepsService) Get(ctx context.Context, cardNumber int, id string) (*Step, error) {
+	var step Step
+	if err := s.c.get(ctx, stepPath(cardNumber, id), &step); err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+// Create adds a step to a card.
+func (s *StepsService) Create(ctx context.Context, cardNumber int, params StepParams) (*Step, error) {
+	var step Step
+	if err := s.c.create(ctx, cardPath(cardNumber, "steps"), map[string]interface{}{"step": params}, &step); err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+// Update changes a step and returns it.
+func (s *StepsService) Update(ctx context.Context, cardNumber int, id string, params StepParams) (*Step, error) {
+	var step Step
+	if err := s.c.update(ctx, stepPath(cardNumber, id), map[string]interface{}{"step": params}, &step); err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+// Delete deletes a step.
+func (s *StepsService) Delete(ctx context.Context, cardNumber int, id string) error {
+	return s.c.delete(ctx, stepPath(cardNumber, id))
+}
+
+func stepPath(cardNumber int, id string) string {
+	return "/cards/" + strconv.Itoa(cardNumber) + "/steps/" + id + ".json"
+}
@@ -0,0 +1,49 @@
+package fizzy
+
+import (
+	"context"
+	"iter"
+)
+
+// UsersService reads the users of an account.
+type UsersService struct{ c *Client }
+
+// List returns every user in the account.
+func (s *UsersService) List(ctx context.Context) ([]User, error) {
+	return collect[User](ctx, s.c, "/users.json")
+}
+
+// All iterates over the users in the account, fetching pages as needed.
+func (s *UsersService) All(ctx context.Context) iter.Seq2[User, error] {
+	return each[User](ctx, s.c, "/users.json")
+}
+
+// Get returns a user by ID.
+func (s *UsersService) Get(ctx context.Context, id string) (*User, error) {
+	var user User
+	if err := s.c.get(ctx, "/users/"+id+".json", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// TagsService reads the tags of an account.
+type TagsService struct{ c *Client }
+
+// List returns every tag in the account.
+func (s *TagsService) List(ctx context.Context) ([]Tag, error) {
+	return collect[Tag](ctx, s.c, "/tags.json")
+}
+
+// IdentityService reads the identity behind the token.
+type IdentityService struct{ c *Client }
+
+// Get returns the accounts the token can access. It is not scoped to the
+// client's account.
+func (s *IdentityService) Get(ctx context.Context) (*Identity, error) {
+	var identity Identity
+	if err := s.c.get(ctx, s.c.baseURL+"/my/identity.json", &identity); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
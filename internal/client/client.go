@@ -3,6 +3,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
@@ -20,6 +21,9 @@ import (
 	"github.com/robzolkos/fizzy-cli/internal/errors"
 )
 
+// DefaultTimeout is the default limit for a single HTTP request.
+const DefaultTimeout = 30 * time.Second
+
 // Client is an HTTP client for the Fizzy API.
 type Client struct {
 	BaseURL    string
@@ -27,8 +31,23 @@ type Client struct {
 	Account    string
 	HTTPClient *http.Client
 	Verbose    bool
+	Retry      RetryPolicy
+
+	// Context is used by the methods that don't take one, so that every
+	// request made for a command can be cancelled together. Nil means
+	// context.Background().
+	Context context.Context
+
+	// Progress, if set, is called as file uploads are sent.
+	Progress ProgressFunc
+
+	// sleep replaces the wait between retries (overridable for testing).
+	sleep func(time.Duration)
 }
 
+// ProgressFunc receives the bytes sent so far of the file at path.
+type ProgressFunc func(path string, sent, total int64)
+
 // APIResponse represents a response from the API.
 type APIResponse struct {
 	StatusCode int
@@ -45,9 +64,17 @@ func New(baseURL, token, account string) *Client {
 		Token:   token,
 		Account: account,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		Retry: DefaultRetryPolicy(),
+	}
+}
+
+func (c *Client) context() context.Context {
+	if c.Context != nil {
+		return c.Context
 	}
+	return context.Background()
 }
 
 // buildURL constructs the full API URL.
@@ -72,48 +99,106 @@ func (c *Client) buildURL(path string) string {
 
 // Get performs a GET request.
 func (c *Client) Get(path string) (*APIResponse, error) {
-	return c.request("GET", path, nil)
+	return c.GetContext(c.context(), path)
+}
+
+// GetContext performs a GET request with a context.
+func (c *Client) GetContext(ctx context.Context, path string) (*APIResponse, error) {
+	return c.request(ctx, "GET", path, nil)
 }
 
 // Post performs a POST request with JSON body.
 func (c *Client) Post(path string, body interface{}) (*APIResponse, error) {
-	return c.request("POST", path, body)
+	return c.PostContext(c.context(), path, body)
+}
+
+// PostContext performs a POST request with JSON body and a context.
+func (c *Client) PostContext(ctx context.Context, path string, body interface{}) (*APIResponse, error) {
+	return c.request(ctx, "POST", path, body)
 }
 
 // Patch performs a PATCH request with JSON body.
 func (c *Client) Patch(path string, body interface{}) (*APIResponse, error) {
-	return c.request("PATCH", path, body)
+	return c.PatchContext(c.context(), path, body)
+}
+
+// PatchContext performs a PATCH request with JSON body and a context.
+func (c *Client) PatchContext(ctx context.Context, path string, body interface{}) (*APIResponse, error) {
+	return c.request(ctx, "PATCH", path, body)
 }
 
 // Put performs a PUT request with JSON body.
 func (c *Client) Put(path string, body interface{}) (*APIResponse, error) {
-	return c.request("PUT", path, body)
+	return c.PutContext(c.context(), path, body)
+}
+
+// PutContext performs a PUT request with JSON body and a context.
+func (c *Client) PutContext(ctx context.Context, path string, body interface{}) (*APIResponse, error) {
+	return c.request(ctx, "PUT", path, body)
 }
 
 // Delete performs a DELETE request.
 func (c *Client) Delete(path string) (*APIResponse, error) {
-	return c.request("DELETE", path, nil)
+	return c.DeleteContext(c.context(), path)
+}
+
+// DeleteContext performs a DELETE request with a context.
+func (c *Client) DeleteContext(ctx context.Context, path string) (*APIResponse, error) {
+	return c.request(ctx, "DELETE", path, nil)
 }
 
-func (c *Client) request(method, path string, body interface{}) (*APIResponse, error) {
+func (c *Client) request(ctx context.Context, method, path string, body interface{}) (*APIResponse, error) {
 	requestURL := c.buildURL(path)
 
-	var reqBody io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, errors.NewError(fmt.Sprintf("Failed to marshal request body: %v", err))
 		}
+	}
+
+	attempts := c.Retry.MaxAttempts
+	if attempts < 1 || !c.Retry.allowsMethod(method) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, retryAfter, err := c.doRequest(ctx, method, requestURL, body != nil, jsonBody)
+		if attempt >= attempts {
+			return resp, err
+		}
+
+		if resp == nil || !retryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		delay := c.Retry.backoff(attempt, retryAfter)
+		if c.Verbose {
+			fmt.Fprintf(os.Stderr, "! retrying %s %s in %s (attempt %d/%d)\n", method, requestURL, delay, attempt+1, attempts)
+		}
+		if err := c.sleepFor(ctx, delay); err != nil {
+			return resp, err
+		}
+	}
+}
+
+// doRequest performs a single HTTP round trip. It returns the Retry-After
+// header alongside the response so the caller can decide how long to wait.
+func (c *Client) doRequest(ctx context.Context, method, requestURL string, hasBody bool, jsonBody []byte) (*APIResponse, string, error) {
+	var reqBody io.Reader
+	if hasBody {
 		reqBody = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, requestURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reqBody)
 	if err != nil {
-		return nil, errors.NewNetworkError(fmt.Sprintf("Failed to create request: %v", err))
+		return nil, "", errors.NewNetworkError(fmt.Sprintf("Failed to create request: %v", err))
 	}
 
 	c.setHeaders(req)
-	if body != nil {
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -123,19 +208,26 @@ func (c *Client) request(method, path string, body interface{}) (*APIResponse, e
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.NewNetworkError(fmt.Sprintf("Request failed: %v", err))
+		if ctx.Err() != nil {
+			return nil, "", cancelledError(ctx)
+		}
+		return nil, "", errors.NewNetworkError(fmt.Sprintf("Request failed: %v", err))
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.NewNetworkError(fmt.Sprintf("Failed to read response: %v", err))
+		if ctx.Err() != nil {
+			return nil, "", cancelledError(ctx)
+		}
+		return nil, "", errors.NewNetworkError(fmt.Sprintf("Failed to read response: %v", err))
 	}
 
 	if c.Verbose {
 		fmt.Fprintf(os.Stderr, "< %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
+	retryAfter := resp.Header.Get("Retry-After")
 	apiResp := &APIResponse{
 		StatusCode: resp.StatusCode,
 		Body:       respBody,
@@ -146,16 +238,46 @@ func (c *Client) request(method, path string, body interface{}) (*APIResponse, e
 	// Parse JSON body if present
 	if len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, &apiResp.Data); err != nil {
-			return apiResp, errors.NewError(fmt.Sprintf("Failed to parse JSON response: %v", err))
+			if resp.StatusCode >= 400 {
+				return apiResp, retryAfter, c.errorFromResponse(resp.StatusCode, respBody)
+			}
+			return apiResp, retryAfter, errors.NewError(fmt.Sprintf("Failed to parse JSON response: %v", err))
 		}
 	}
 
 	// Check for error status codes
 	if resp.StatusCode >= 400 {
-		return apiResp, c.errorFromResponse(resp.StatusCode, respBody)
+		return apiResp, retryAfter, c.errorFromResponse(resp.StatusCode, respBody)
 	}
 
-	return apiResp, nil
+	return apiResp, retryAfter, nil
+}
+
+// sleepFor waits d before a retry, returning early with a cancellation error
+// if ctx is done.
+func (c *Client) sleepFor(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		c.sleep(d)
+	} else {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	if ctx.Err() != nil {
+		return cancelledError(ctx)
+	}
+	return nil
+}
+
+// cancelledError reports why ctx ended.
+func cancelledError(ctx context.Context) *errors.CLIError {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.NewCancelledError("Request cancelled: deadline exceeded")
+	}
+	return errors.NewCancelledError("Request cancelled")
 }
 
 func (c *Client) setHeaders(req *http.Request) {
@@ -164,6 +286,12 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", "fizzy-cli/1.0")
 }
 
+// isAPIHost reports whether u has the scheme and host of the API.
+func (c *Client) isAPIHost(u *url.URL) bool {
+	base, err := url.Parse(c.BaseURL)
+	return err == nil && u.Scheme == base.Scheme && strings.EqualFold(u.Host, base.Host)
+}
+
 func (c *Client) errorFromResponse(status int, body []byte) error {
 	// Try to parse error message from response
 	var errResp struct {
@@ -192,7 +320,14 @@ func parseLinkNext(linkHeader string) string {
 
 // GetWithPagination fetches all pages of a paginated endpoint.
 func (c *Client) GetWithPagination(path string, fetchAll bool) (*APIResponse, error) {
-	resp, err := c.Get(path)
+	return c.GetWithPaginationContext(c.context(), path, fetchAll)
+}
+
+// GetWithPaginationContext fetches all pages of a paginated endpoint, stopping
+// when ctx is cancelled. A cancelled error carries the items fetched so far
+// in Partial and the URL of the next page in Details.
+func (c *Client) GetWithPaginationContext(ctx context.Context, path string, fetchAll bool) (*APIResponse, error) {
+	resp, err := c.GetContext(ctx, path)
 	if err != nil {
 		return resp, err
 	}
@@ -210,8 +345,12 @@ func (c *Client) GetWithPagination(path string, fetchAll bool) (*APIResponse, er
 	// Fetch remaining pages
 	nextURL := resp.LinkNext
 	for nextURL != "" {
-		pageResp, err := c.Get(nextURL)
+		pageResp, err := c.GetContext(ctx, nextURL)
 		if err != nil {
+			if cliErr, ok := err.(*errors.CLIError); ok && cliErr.Code == "CANCELLED" {
+				cliErr.Partial = allData
+				cliErr.Details = map[string]interface{}{"next_url": nextURL}
+			}
 			return nil, err
 		}
 
@@ -235,8 +374,63 @@ func (c *Client) FollowLocation(location string) (*APIResponse, error) {
 	return c.Get(location)
 }
 
-// UploadFile uploads a file using the direct upload flow.
-func (c *Client) UploadFile(filePath string) (*APIResponse, error) {
+// Download streams the file at url, such as an attachment's URL, to w and
+// returns its content type. Paths are resolved against the API host without
+// the account, the way the API links to blobs. The token is only sent to
+// the API host.
+func (c *Client) Download(url string, w io.Writer) (string, error) {
+	ctx := c.context()
+	if !strings.HasPrefix(url, "http") {
+		url = c.BaseURL + "/" + strings.TrimPrefix(url, "/")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", errors.NewNetworkError(fmt.Sprintf("Failed to create request: %v", err))
+	}
+	c.setHeaders(req)
+	if !c.isAPIHost(req.URL) {
+		req.Header.Del("Authorization")
+	}
+	req.Header.Set("Accept", "*/*")
+
+	if c.Verbose {
+		fmt.Fprintf(os.Stderr, "> GET %s\n", url)
+	}
+
+	// Like uploads, the transfer is bounded by the context rather than the
+	// per-request timeout.
+	downloadClient := *c.HTTPClient
+	downloadClient.Timeout = 0
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", cancelledError(ctx)
+		}
+		return "", errors.NewNetworkError(fmt.Sprintf("Download failed: %v", err))
+	}
+	defer resp.Body.Close()
+
+	if c.Verbose {
+		fmt.Fprintf(os.Stderr, "< %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", c.errorFromResponse(resp.StatusCode, body)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		if ctx.Err() != nil {
+			return "", cancelledError(ctx)
+		}
+		return "", errors.NewNetworkError(fmt.Sprintf("Download failed: %v", err))
+	}
+	return resp.Header.Get("Content-Type"), nil
+}
+
+// UploadFile uploads a file using the direct upload flow. An empty
+// contentType is detected from the file.
+func (c *Client) UploadFile(filePath, contentType string) (*APIResponse, error) {
 	// Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -250,15 +444,27 @@ func (c *Client) UploadFile(filePath string) (*APIResponse, error) {
 		return nil, errors.NewError(fmt.Sprintf("Failed to stat file: %v", err))
 	}
 
-	// Read file content for checksum
-	fileContent, err := io.ReadAll(file)
+	if contentType == "" {
+		head, err := readHead(file)
+		if err != nil {
+			return nil, errors.NewError(fmt.Sprintf("Failed to read file: %v", err))
+		}
+		contentType = detectContentType(filePath, head)
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.NewError(fmt.Sprintf("Failed to read file: %v", err))
+		}
+	}
+
+	// Compute the checksum in one pass, then rewind for the upload
+	checksum, err := computeChecksum(file)
 	if err != nil {
 		return nil, errors.NewError(fmt.Sprintf("Failed to read file: %v", err))
 	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.NewError(fmt.Sprintf("Failed to read file: %v", err))
+	}
 
 	filename := filepath.Base(filePath)
-	contentType := detectContentType(filePath)
-	checksum := computeChecksum(fileContent)
 
 	// Step 1: Create blob
 	blobReq := map[string]interface{}{
@@ -301,11 +507,19 @@ func (c *Client) UploadFile(filePath string) (*APIResponse, error) {
 	// Get attachable_sgid for use in action-text-attachment
 	attachableSGID, _ := blobData["attachable_sgid"].(string)
 
-	// Step 2: Upload file to the direct upload URL
-	uploadReq, err := http.NewRequest("PUT", uploadURL, bytes.NewReader(fileContent))
+	// Step 2: Stream the file to the direct upload URL
+	var body io.Reader = http.NoBody
+	if fileInfo.Size() > 0 {
+		body = file
+		if c.Progress != nil {
+			body = &progressReader{r: file, name: filePath, total: fileInfo.Size(), report: c.Progress}
+		}
+	}
+	uploadReq, err := http.NewRequestWithContext(c.context(), "PUT", uploadURL, body)
 	if err != nil {
 		return nil, errors.NewNetworkError(fmt.Sprintf("Failed to create upload request: %v", err))
 	}
+	uploadReq.ContentLength = fileInfo.Size()
 
 	// Set headers from the direct_upload response
 	for key, value := range headers {
@@ -314,8 +528,15 @@ func (c *Client) UploadFile(filePath string) (*APIResponse, error) {
 		}
 	}
 
-	uploadResp, err := c.HTTPClient.Do(uploadReq)
+	// The transfer takes as long as the file needs, so it is bounded by the
+	// context rather than the per-request timeout.
+	uploadClient := *c.HTTPClient
+	uploadClient.Timeout = 0
+	uploadResp, err := uploadClient.Do(uploadReq)
 	if err != nil {
+		if c.context().Err() != nil {
+			return nil, cancelledError(c.context())
+		}
 		return nil, errors.NewNetworkError(fmt.Sprintf("Upload failed: %v", err))
 	}
 	defer uploadResp.Body.Close()
@@ -372,7 +593,7 @@ func (c *Client) UploadFileMultipart(path, fieldName, filePath string, extraFiel
 	}
 
 	reqURL := c.buildURL(path)
-	req, err := http.NewRequest("POST", reqURL, &buf)
+	req, err := http.NewRequestWithContext(c.context(), "POST", reqURL, &buf)
 	if err != nil {
 		return nil, errors.NewNetworkError(fmt.Sprintf("Failed to create request: %v", err))
 	}
@@ -382,6 +603,9 @@ func (c *Client) UploadFileMultipart(path, fieldName, filePath string, extraFiel
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
+		if c.context().Err() != nil {
+			return nil, cancelledError(c.context())
+		}
 		return nil, errors.NewNetworkError(fmt.Sprintf("Request failed: %v", err))
 	}
 	defer resp.Body.Close()
@@ -410,33 +634,32 @@ func (c *Client) UploadFileMultipart(path, fieldName, filePath string, extraFiel
 	return apiResp, nil
 }
 
-// computeChecksum computes the base64-encoded MD5 checksum of content.
-func computeChecksum(content []byte) string {
-	hash := md5.Sum(content)
-	return base64.StdEncoding.EncodeToString(hash[:])
+// computeChecksum computes the base64-encoded MD5 checksum of everything
+// read from r.
+func computeChecksum(r io.Reader) (string, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
 }
 
-func detectContentType(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	contentTypes := map[string]string{
-		".png":  "image/png",
-		".jpg":  "image/jpeg",
-		".jpeg": "image/jpeg",
-		".gif":  "image/gif",
-		".webp": "image/webp",
-		".svg":  "image/svg+xml",
-		".pdf":  "application/pdf",
-		".txt":  "text/plain",
-		".html": "text/html",
-		".json": "application/json",
-		".xml":  "application/xml",
-		".zip":  "application/zip",
-	}
+// progressReader reports how much of an upload has been read.
+type progressReader struct {
+	r      io.Reader
+	name   string
+	sent   int64
+	total  int64
+	report ProgressFunc
+}
 
-	if ct, ok := contentTypes[ext]; ok {
-		return ct
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.report(p.name, p.sent, p.total)
 	}
-	return "application/octet-stream"
+	return n, err
 }
 
 // ParsePage extracts page number from a URL query string.
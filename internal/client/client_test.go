@@ -1,12 +1,14 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/internal/errors"
@@ -445,59 +447,76 @@ func TestFollowLocation(t *testing.T) {
 	})
 }
 
-func TestDetectContentType(t *testing.T) {
-	tests := []struct {
-		filename string
-		expected string
-	}{
-		{"image.png", "image/png"},
-		{"image.PNG", "image/png"},
-		{"photo.jpg", "image/jpeg"},
-		{"photo.jpeg", "image/jpeg"},
-		{"animation.gif", "image/gif"},
-		{"image.webp", "image/webp"},
-		{"icon.svg", "image/svg+xml"},
-		{"document.pdf", "application/pdf"},
-		{"readme.txt", "text/plain"},
-		{"page.html", "text/html"},
-		{"data.json", "application/json"},
-		{"config.xml", "application/xml"},
-		{"archive.zip", "application/zip"},
-		{"unknown.xyz", "application/octet-stream"},
-	}
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("expected the token to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/rails/active_storage/blobs/abc/report.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Blob not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer server.Close()
 
-	for _, tt := range tests {
-		t.Run(tt.filename, func(t *testing.T) {
-			result := detectContentType(tt.filename)
-			if result != tt.expected {
-				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+	c := New(server.URL, "test-token", "account")
+
+	t.Run("relative url skips the account", func(t *testing.T) {
+		var buf strings.Builder
+		contentType, err := c.Download("/rails/active_storage/blobs/abc/report.pdf", &buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "%PDF-1.4" || contentType != "application/pdf" {
+			t.Errorf("got %q (%s)", buf.String(), contentType)
+		}
+	})
+
+	t.Run("other hosts don't get the token", func(t *testing.T) {
+		storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auth := r.Header.Get("Authorization"); auth != "" {
+				t.Errorf("expected no token, got %q", auth)
 			}
-		})
-	}
-}
+			w.Write([]byte("%PDF-1.4"))
+		}))
+		defer storage.Close()
 
-func TestComputeChecksum(t *testing.T) {
-	content := []byte("test content")
-	checksum := computeChecksum(content)
+		var buf strings.Builder
+		if _, err := c.Download(storage.URL+"/blobs/abc/report.pdf", &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "%PDF-1.4" {
+			t.Errorf("got %q", buf.String())
+		}
+	})
 
-	if checksum == "" {
-		t.Error("expected non-empty checksum")
-	}
+	t.Run("not found", func(t *testing.T) {
+		var buf strings.Builder
+		_, err := c.Download(server.URL+"/rails/active_storage/blobs/missing", &buf)
+		cliErr, ok := err.(*errors.CLIError)
+		if !ok || cliErr.Status != 404 || cliErr.Message != "Blob not found" {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+}
 
-	// Checksum should be base64 encoded
-	if len(checksum) < 20 {
-		t.Errorf("checksum seems too short: '%s'", checksum)
+func TestComputeChecksum(t *testing.T) {
+	checksum, err := computeChecksum(strings.NewReader("test content"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Same content should produce same checksum
-	checksum2 := computeChecksum(content)
-	if checksum != checksum2 {
-		t.Error("expected same checksum for same content")
+	// MD5 of "test content", base64 encoded
+	if checksum != "lHP90NiApDwht3eNNIchVw==" {
+		t.Errorf("unexpected checksum '%s'", checksum)
 	}
 
 	// Different content should produce different checksum
-	checksum3 := computeChecksum([]byte("different content"))
-	if checksum == checksum3 {
+	checksum2, _ := computeChecksum(strings.NewReader("different content"))
+	if checksum == checksum2 {
 		t.Error("expected different checksum for different content")
 	}
 }
@@ -571,7 +590,7 @@ func TestUploadFile(t *testing.T) {
 	serverURL = server.URL
 
 	c := New(server.URL, "test-token", "")
-	resp, err := c.UploadFile(tempFile)
+	resp, err := c.UploadFile(tempFile, "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -591,9 +610,64 @@ func TestUploadFile(t *testing.T) {
 	}
 }
 
+func TestUploadFile_StreamsWithProgress(t *testing.T) {
+	content := strings.Repeat("fizzy", 100000)
+	tempFile := filepath.Join(t.TempDir(), "large.txt")
+	os.WriteFile(tempFile, []byte(content), 0644)
+	want, _ := computeChecksum(strings.NewReader(content))
+
+	var checksum, received string
+	var contentLength int64
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var req map[string]map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			checksum, _ = req["blob"]["checksum"].(string)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"signed_id":     "signed",
+				"direct_upload": map[string]interface{}{"url": serverURL + "/upload"},
+			})
+			return
+		}
+		contentLength = r.ContentLength
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	var reports int
+	var lastSent, lastTotal int64
+	c := New(server.URL, "test-token", "")
+	c.Progress = func(path string, sent, total int64) {
+		if path != tempFile {
+			t.Errorf("expected progress for %s, got %s", tempFile, path)
+		}
+		reports++
+		lastSent, lastTotal = sent, total
+	}
+
+	if _, err := c.UploadFile(tempFile, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checksum != want {
+		t.Errorf("expected checksum %s, got %s", want, checksum)
+	}
+	if contentLength != int64(len(content)) {
+		t.Errorf("expected Content-Length %d, got %d", len(content), contentLength)
+	}
+	if received != content {
+		t.Errorf("uploaded %d bytes, want %d", len(received), len(content))
+	}
+	if reports < 2 || lastSent != lastTotal || lastTotal != int64(len(content)) {
+		t.Errorf("expected incremental progress ending at %d, got %d reports ending at %d/%d", len(content), reports, lastSent, lastTotal)
+	}
+}
+
 func TestUploadFile_FileNotFound(t *testing.T) {
 	c := New("https://api.example.com", "token", "account")
-	_, err := c.UploadFile("/nonexistent/file.txt")
+	_, err := c.UploadFile("/nonexistent/file.txt", "")
 
 	if err == nil {
 		t.Fatal("expected error for non-existent file")
@@ -621,6 +695,79 @@ func TestNetworkError(t *testing.T) {
 	}
 }
 
+func TestContextCancellation(t *testing.T) {
+	t.Run("cancelled context returns CANCELLED", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{"id": "1"})
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := New(server.URL, "token", "")
+		_, err := c.GetContext(ctx, "/resource.json")
+
+		cliErr, ok := err.(*errors.CLIError)
+		if !ok || cliErr.Code != "CANCELLED" {
+			t.Fatalf("expected CANCELLED error, got %v", err)
+		}
+		if cliErr.ExitCode != errors.ExitCancelled {
+			t.Errorf("expected exit code %d, got %d", errors.ExitCancelled, cliErr.ExitCode)
+		}
+	})
+
+	t.Run("client context applies to plain methods", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := New(server.URL, "token", "")
+		c.Context = ctx
+		if _, err := c.Delete("/resource.json"); err == nil || err.(*errors.CLIError).Code != "CANCELLED" {
+			t.Errorf("expected CANCELLED error, got %v", err)
+		}
+	})
+
+	t.Run("pagination returns partial results", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+server.URL+`/resources.json?page=2>; rel="next"`)
+				json.NewEncoder(w).Encode([]map[string]string{{"id": "1"}})
+				return
+			}
+			// Simulate Ctrl-C while the second page is in flight.
+			cancel()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		c := New(server.URL, "token", "")
+		_, err := c.GetWithPaginationContext(ctx, "/resources.json", true)
+
+		cliErr, ok := err.(*errors.CLIError)
+		if !ok || cliErr.Code != "CANCELLED" {
+			t.Fatalf("expected CANCELLED error, got %v", err)
+		}
+		partial, ok := cliErr.Partial.([]interface{})
+		if !ok || len(partial) != 1 {
+			t.Errorf("expected first page as partial results, got %v", cliErr.Partial)
+		}
+		details, _ := cliErr.Details.(map[string]interface{})
+		if details["next_url"] != server.URL+"/resources.json?page=2" {
+			t.Errorf("expected next_url in details, got %v", cliErr.Details)
+		}
+	})
+}
+
 func TestVerboseMode(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
@@ -1,5 +1,10 @@
 package client
 
+import (
+	"context"
+	"io"
+)
+
 // API defines the interface for API operations.
 // This allows for mocking in tests.
 type API interface {
@@ -10,7 +15,17 @@ type API interface {
 	Delete(path string) (*APIResponse, error)
 	GetWithPagination(path string, fetchAll bool) (*APIResponse, error)
 	FollowLocation(location string) (*APIResponse, error)
-	UploadFile(filePath string) (*APIResponse, error)
+	UploadFile(filePath, contentType string) (*APIResponse, error)
+	Download(url string, w io.Writer) (string, error)
+
+	// Context-aware variants. The methods above use the client's default
+	// context.
+	GetContext(ctx context.Context, path string) (*APIResponse, error)
+	PostContext(ctx context.Context, path string, body interface{}) (*APIResponse, error)
+	PatchContext(ctx context.Context, path string, body interface{}) (*APIResponse, error)
+	PutContext(ctx context.Context, path string, body interface{}) (*APIResponse, error)
+	DeleteContext(ctx context.Context, path string) (*APIResponse, error)
+	GetWithPaginationContext(ctx context.Context, path string, fetchAll bool) (*APIResponse, error)
 }
 
 // Ensure Client implements API interface
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLen is how much of a file is read to detect its content type. Office
+// documents name their parts in zip headers, which can be past the 512 bytes
+// http.DetectContentType looks at.
+const sniffLen = 4096
+
+// genericTypes are sniffed types that the file extension may refine, e.g. a
+// .csv file sniffs as text/plain and a .docx as application/zip.
+var genericTypes = map[string]bool{
+	"application/octet-stream":  true,
+	"application/zip":           true,
+	"application/x-ole-storage": true,
+	"text/plain":                true,
+	"text/xml":                  true,
+}
+
+// extensionTypes maps extensions to content types. It is consulted before
+// mime.TypeByExtension so that results don't depend on the system's MIME
+// database.
+var extensionTypes = map[string]string{
+	// Images
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".avif": "image/avif",
+	".heic": "image/heic",
+	".heif": "image/heif",
+	".bmp":  "image/bmp",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".ico":  "image/vnd.microsoft.icon",
+
+	// Video and audio
+	".mp4":  "video/mp4",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".avi":  "video/x-msvideo",
+	".mpeg": "video/mpeg",
+	".mpg":  "video/mpeg",
+	".3gp":  "video/3gpp",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".wav":  "audio/wav",
+	".ogg":  "audio/ogg",
+	".flac": "audio/flac",
+
+	// Documents
+	".pdf":     "application/pdf",
+	".txt":     "text/plain",
+	".md":      "text/markdown",
+	".csv":     "text/csv",
+	".tsv":     "text/tab-separated-values",
+	".html":    "text/html",
+	".htm":     "text/html",
+	".css":     "text/css",
+	".js":      "text/javascript",
+	".json":    "application/json",
+	".xml":     "application/xml",
+	".yaml":    "application/yaml",
+	".yml":     "application/yaml",
+	".rtf":     "application/rtf",
+	".doc":     "application/msword",
+	".xls":     "application/vnd.ms-excel",
+	".ppt":     "application/vnd.ms-powerpoint",
+	".docx":    "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx":    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx":    "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":     "application/vnd.oasis.opendocument.text",
+	".ods":     "application/vnd.oasis.opendocument.spreadsheet",
+	".odp":     "application/vnd.oasis.opendocument.presentation",
+	".key":     "application/vnd.apple.keynote",
+	".pages":   "application/vnd.apple.pages",
+	".numbers": "application/vnd.apple.numbers",
+
+	// Archives
+	".zip": "application/zip",
+	".gz":  "application/gzip",
+	".tar": "application/x-tar",
+	".7z":  "application/x-7z-compressed",
+}
+
+// readHead reads up to sniffLen bytes from the start of r.
+func readHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// detectContentType picks a content type from the first bytes of a file,
+// falling back to its extension when the bytes are unrecognised or only
+// identify a container such as zip or plain text.
+func detectContentType(filePath string, head []byte) string {
+	sniffed := ""
+	if len(head) > 0 {
+		sniffed = sniffSignature(head)
+		if sniffed == "" {
+			sniffed = baseType(http.DetectContentType(head))
+		}
+		if !genericTypes[sniffed] {
+			return sniffed
+		}
+	}
+
+	if ct := contentTypeByExtension(filePath); ct != "" {
+		return ct
+	}
+	if sniffed != "" && sniffed != "application/x-ole-storage" {
+		return sniffed
+	}
+	return "application/octet-stream"
+}
+
+// contentTypeByExtension returns the content type for the extension of
+// filePath, or "" if it is unknown.
+func contentTypeByExtension(filePath string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext == "" {
+		return ""
+	}
+	if ct, ok := extensionTypes[ext]; ok {
+		return ct
+	}
+	return baseType(mime.TypeByExtension(ext))
+}
+
+// sniffSignature recognises formats that http.DetectContentType doesn't:
+// ISO media files (MP4, QuickTime, HEIC), Matroska, and office documents.
+func sniffSignature(head []byte) string {
+	switch {
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		return isoMediaType(string(head[8:12]))
+	case bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		if bytes.Contains(head, []byte("webm")) {
+			return "video/webm"
+		}
+		return "video/x-matroska"
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return zipDocumentType(head)
+	case bytes.HasPrefix(head, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}):
+		// Legacy Office formats share this container; the extension tells
+		// them apart.
+		return "application/x-ole-storage"
+	}
+	return ""
+}
+
+// isoMediaType maps the major brand of an ISO base media file. Other
+// brands return "", since the format is used for much besides video (raw
+// photos, JPEG XL, ...), and the extension is the better guide.
+func isoMediaType(brand string) string {
+	switch brand {
+	case "isom", "iso2", "iso4", "iso5", "iso6", "mp41", "mp42", "avc1", "dash", "mmp4", "MSNV", "f4v ":
+		return "video/mp4"
+	case "qt  ":
+		return "video/quicktime"
+	case "heic", "heix", "heim", "heis", "hevc", "hevx":
+		return "image/heic"
+	case "mif1", "msf1":
+		return "image/heif"
+	case "avif", "avis":
+		return "image/avif"
+	case "M4A ", "M4B ":
+		return "audio/mp4"
+	case "M4V ", "M4VH", "M4VP":
+		return "video/x-m4v"
+	case "3gp4", "3gp5", "3gp6", "3g2a":
+		return "video/3gpp"
+	}
+	return ""
+}
+
+// zipDocumentType identifies Office Open XML and OpenDocument files from
+// the part names near the start of the archive. Other archives are zip.
+func zipDocumentType(head []byte) string {
+	// OpenDocument stores its type uncompressed as the first entry
+	if len(head) > 30 && string(head[30:min(38, len(head))]) == "mimetype" {
+		size := int(binary.LittleEndian.Uint32(head[18:22]))
+		start := 30 + int(binary.LittleEndian.Uint16(head[26:28])) + int(binary.LittleEndian.Uint16(head[28:30]))
+		if start+size <= len(head) {
+			// The entry is untrusted, so only a well-formed OpenDocument
+			// type is used; anything else is treated as a plain archive.
+			mediaType := string(head[start : start+size])
+			if _, _, err := mime.ParseMediaType(mediaType); err == nil && strings.HasPrefix(mediaType, "application/vnd.oasis.opendocument.") {
+				return mediaType
+			}
+			return "application/zip"
+		}
+	}
+	switch {
+	case bytes.Contains(head, []byte("word/")):
+		return extensionTypes[".docx"]
+	case bytes.Contains(head, []byte("xl/")):
+		return extensionTypes[".xlsx"]
+	case bytes.Contains(head, []byte("ppt/")):
+		return extensionTypes[".pptx"]
+	}
+	return "application/zip"
+}
+
+// baseType strips parameters such as charset from a media type.
+func baseType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
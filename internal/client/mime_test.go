@@ -0,0 +1,219 @@
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected string
+	}{
+		// Recognised from magic bytes
+		{"image.png", "image/png"},
+		{"document.pdf", "application/pdf"},
+		{"clip.mp4", "video/mp4"},
+		{"screen.mov", "video/quicktime"},
+		{"photo.heic", "image/heic"},
+		{"voice.m4a", "audio/mp4"},
+		{"clip.webm", "video/webm"},
+		{"clip.mkv", "video/x-matroska"},
+		{"report.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"budget.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{"slides.pptx", "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+		{"notes.odt", "application/vnd.oasis.opendocument.text"},
+		{"archive.zip", "application/zip"},
+
+		// Contents win over a wrong extension
+		{"misnamed.txt", "image/png"},
+
+		// Generic contents refined by the extension
+		{"data.csv", "text/csv"},
+		{"notes.md", "text/markdown"},
+		{"icon.svg", "image/svg+xml"},
+		{"legacy.doc", "application/msword"},
+		{"empty.txt", "text/plain"},
+
+		// Unrecognised
+		{"random.xyz", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			file, err := os.Open(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			head, err := readHead(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := detectContentType(tt.fixture, head)
+			if result != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestISOMediaType(t *testing.T) {
+	// isoHead builds the start of an ISO media file with a major brand.
+	isoHead := func(brand string) []byte {
+		return append([]byte("\x00\x00\x00\x18ftyp"+brand), make([]byte, 12)...)
+	}
+
+	tests := []struct {
+		filename string
+		brand    string
+		expected string
+	}{
+		{"upload", "isom", "video/mp4"},
+		{"upload", "mp42", "video/mp4"},
+		{"upload", "heic", "image/heic"},
+		{"upload", "crx ", "application/octet-stream"},
+		{"photo.heic", "xxxx", "image/heic"},
+	}
+
+	for _, tt := range tests {
+		if result := detectContentType(tt.filename, isoHead(tt.brand)); result != tt.expected {
+			t.Errorf("%s with brand %q: expected '%s', got '%s'", tt.filename, tt.brand, tt.expected, result)
+		}
+	}
+}
+
+func TestZipDocumentType(t *testing.T) {
+	// odfHead builds the start of an archive whose first, stored entry is
+	// the OpenDocument mimetype.
+	odfHead := func(mediaType string) []byte {
+		head := make([]byte, 30)
+		copy(head, "PK\x03\x04")
+		binary.LittleEndian.PutUint32(head[18:22], uint32(len(mediaType)))
+		binary.LittleEndian.PutUint16(head[26:28], uint16(len("mimetype")))
+		return append(append(head, "mimetype"...), mediaType...)
+	}
+
+	tests := []struct {
+		mediaType string
+		expected  string
+	}{
+		{"application/vnd.oasis.opendocument.spreadsheet", "application/vnd.oasis.opendocument.spreadsheet"},
+		{"text/html", "application/zip"},
+		{"application/vnd.oasis.opendocument.text\r\nX-Injected: 1", "application/zip"},
+		{"", "application/zip"},
+	}
+
+	for _, tt := range tests {
+		if result := zipDocumentType(odfHead(tt.mediaType)); result != tt.expected {
+			t.Errorf("mimetype %q: expected '%s', got '%s'", tt.mediaType, tt.expected, result)
+		}
+	}
+}
+
+func TestDetectContentType_WithoutExtension(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected string
+	}{
+		{"report.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"screen.mov", "video/quicktime"},
+		{"data.csv", "text/plain"},
+		{"legacy.doc", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			head, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := detectContentType("upload", head)
+			if result != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestContentTypeByExtension(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"image.png", "image/png"},
+		{"image.PNG", "image/png"},
+		{"photo.jpg", "image/jpeg"},
+		{"photo.jpeg", "image/jpeg"},
+		{"animation.gif", "image/gif"},
+		{"image.webp", "image/webp"},
+		{"icon.svg", "image/svg+xml"},
+		{"photo.HEIC", "image/heic"},
+		{"clip.mp4", "video/mp4"},
+		{"screen.mov", "video/quicktime"},
+		{"document.pdf", "application/pdf"},
+		{"readme.txt", "text/plain"},
+		{"readme.md", "text/markdown"},
+		{"export.csv", "text/csv"},
+		{"page.html", "text/html"},
+		{"data.json", "application/json"},
+		{"config.xml", "application/xml"},
+		{"archive.zip", "application/zip"},
+		{"report.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"Makefile", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			result := contentTypeByExtension(tt.filename)
+			if result != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestUploadFile_ContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		expected    string
+	}{
+		{"detected from contents", "", "video/quicktime"},
+		{"override", "video/mp4", "video/mp4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			var serverURL string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" {
+					var req map[string]map[string]interface{}
+					json.NewDecoder(r.Body).Decode(&req)
+					sent, _ = req["blob"]["content_type"].(string)
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"signed_id":     "signed",
+						"direct_upload": map[string]interface{}{"url": serverURL + "/upload"},
+					})
+				}
+			}))
+			defer server.Close()
+			serverURL = server.URL
+
+			c := New(server.URL, "test-token", "")
+			if _, err := c.UploadFile(filepath.Join("testdata", "screen.mov"), tt.contentType); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sent != tt.expected {
+				t.Errorf("expected content_type '%s', got '%s'", tt.expected, sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func newRetryTestClient(url string) (*Client, *[]time.Duration) {
+	var slept []time.Duration
+	c := New(url, "test-token", "")
+	c.sleep = func(d time.Duration) { slept = append(slept, d) }
+	return c, &slept
+}
+
+func TestRetry_SucceedsAfterServiceUnavailable(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	c, slept := newRetryTestClient(server.URL)
+	resp, err := c.Get("/resource.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if len(*slept) != 2 {
+		t.Errorf("expected 2 sleeps, got %d", len(*slept))
+	}
+}
+
+func TestRetry_HonoursRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, slept := newRetryTestClient(server.URL)
+	if _, err := c.Get("/resource.json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 7*time.Second {
+		t.Errorf("expected a single 7s sleep, got %v", *slept)
+	}
+}
+
+func TestRetry_RetryAfterCappedByMaxDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	if d := p.backoff(1, "120"); d != 5*time.Second {
+		t.Errorf("expected Retry-After to be capped at 5s, got %s", d)
+	}
+}
+
+func TestRetry_ExhaustedReturnsRateLimited(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": "Slow down"}`))
+	}))
+	defer server.Close()
+
+	c, _ := newRetryTestClient(server.URL)
+	c.Retry.MaxAttempts = 2
+	_, err := c.Get("/resource.json")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	cliErr, ok := err.(*errors.CLIError)
+	if !ok {
+		t.Fatalf("expected CLIError, got %T", err)
+	}
+	if cliErr.Code != "RATE_LIMITED" {
+		t.Errorf("expected code 'RATE_LIMITED', got '%s'", cliErr.Code)
+	}
+	if cliErr.ExitCode != errors.ExitRateLimited {
+		t.Errorf("expected exit code %d, got %d", errors.ExitRateLimited, cliErr.ExitCode)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetry_SkipsNonIdempotentByDefault(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c, _ := newRetryTestClient(server.URL)
+	c.Post("/resource.json", map[string]string{"a": "b"})
+	if calls != 1 {
+		t.Errorf("expected POST not to be retried, got %d calls", calls)
+	}
+
+	calls = 0
+	c.Retry.RetryNonIdempotent = true
+	c.Post("/resource.json", map[string]string{"a": "b"})
+	if calls != DefaultRetryAttempts {
+		t.Errorf("expected %d calls with RetryNonIdempotent, got %d", DefaultRetryAttempts, calls)
+	}
+}
+
+func TestRetry_DoesNotRetryInternalServerError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, _ := newRetryTestClient(server.URL)
+	c.Get("/resource.json")
+	if calls != 1 {
+		t.Errorf("expected 1 call for 500, got %d", calls)
+	}
+}
+
+func TestRetry_Disabled(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := newRetryTestClient(server.URL)
+	c.Retry.MaxAttempts = 1
+	c.Get("/resource.json")
+	if calls != 1 {
+		t.Errorf("expected 1 call with retries disabled, got %d", calls)
+	}
+}
+
+func TestBackoff_GrowsAndCaps(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		d := p.backoff(tt.retry, "")
+		if d < tt.max/2 || d > tt.max {
+			t.Errorf("retry %d: expected delay in [%s, %s], got %s", tt.retry, tt.max/2, tt.max, d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("expected 3s, got %s (ok=%v)", d, ok)
+	}
+	if _, ok := parseRetryAfter(""); ok {
+		t.Error("expected empty header to be ignored")
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("expected invalid header to be ignored")
+	}
+	future := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if d, ok := parseRetryAfter(future); !ok || d <= 0 || d > 10*time.Second {
+		t.Errorf("expected HTTP-date to parse to <=10s, got %s (ok=%v)", d, ok)
+	}
+}
+
+func TestRetry_StopsWaitingWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := New(server.URL, "test-token", "")
+	start := time.Now()
+	_, err := c.GetContext(ctx, "/resource.json")
+
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected retry wait to stop on cancellation, took %s", time.Since(start))
+	}
+	cliErr, ok := err.(*errors.CLIError)
+	if !ok || cliErr.Code != "CANCELLED" {
+		t.Errorf("expected CANCELLED error, got %v", err)
+	}
+}
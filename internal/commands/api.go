@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// API command flags
+var apiFields []string
+var apiInput string
+var apiPaginate bool
+
+var apiCmd = &cobra.Command{
+	Use:   "api METHOD PATH",
+	Short: "Make an authenticated API request",
+	Long: `Makes an authenticated request to any Fizzy API endpoint and prints the response.
+
+PATH is relative to the account, e.g. /cards/42.json. Paths under /my/ (such as
+/my/identity.json) are sent without the account prefix. Full URLs are used as-is
+but must be on the configured API URL, so your token is never sent elsewhere.
+
+Request parameters are given with --field key=value. Use brackets for nested
+and array parameters, e.g. --field card[title]=Hello --field tag_ids[]=ID.
+A value starting with @ is read from a file. For GET and DELETE requests,
+fields are sent as query parameters; otherwise they form the JSON body.
+--input sends a JSON file (or - for stdin) as the body instead.`,
+	Example: `  fizzy api GET /boards.json --paginate
+  fizzy api POST /cards/42/comments.json --field comment[body]="Looks good"
+  fizzy api PATCH /cards/42.json --input card.json
+  fizzy api GET /my/identity.json`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		method := strings.ToUpper(args[0])
+		path := args[1]
+
+		switch method {
+		case "GET", "POST", "PATCH", "PUT", "DELETE":
+		default:
+			exitWithError(errors.NewInvalidArgsError("Unsupported method " + args[0] + " (use GET, POST, PATCH, PUT or DELETE)"))
+		}
+
+		if err := requireAuth(); err != nil {
+			exitWithError(err)
+		}
+		if strings.HasPrefix(path, "/my/") {
+			path = cfg.APIURL + path
+		} else if strings.HasPrefix(path, "http") {
+			if err := checkAPIURL(path); err != nil {
+				exitWithError(err)
+			}
+		} else {
+			if err := requireAccount(); err != nil {
+				exitWithError(err)
+			}
+		}
+
+		if apiPaginate && method != "GET" {
+			exitWithError(errors.NewInvalidArgsError("--paginate can only be used with GET"))
+		}
+		if apiInput != "" && len(apiFields) > 0 {
+			exitWithError(errors.NewInvalidArgsError("--input cannot be combined with --field"))
+		}
+
+		params, err := parseAPIFields(apiFields)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		var body interface{}
+		if method == "GET" || method == "DELETE" {
+			if apiInput != "" {
+				exitWithError(errors.NewInvalidArgsError("--input cannot be used with " + method))
+			}
+			path = withQuery(path, apiFields)
+		} else if apiInput != "" {
+			body, err = readAPIInput(apiInput)
+			if err != nil {
+				exitWithError(err)
+			}
+		} else if len(params) > 0 {
+			body = params
+		}
+
+		client := getClient()
+		resp, err := sendAPIRequest(client, method, path, body)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		switch {
+		case resp.Location != "":
+			printSuccessWithLocation(resp.Data, resp.Location)
+		case method == "GET":
+			printSuccessWithPagination(resp.Data, resp.LinkNext != "", resp.LinkNext)
+		default:
+			printSuccess(resp.Data)
+		}
+	},
+}
+
+// checkAPIURL rejects a full URL unless its scheme and host match the
+// configured API URL, since the request carries the token.
+func checkAPIURL(raw string) error {
+	u, err := url.Parse(raw)
+	api, apiErr := url.Parse(cfg.APIURL)
+	if err != nil || apiErr != nil || u.Scheme != api.Scheme || !strings.EqualFold(u.Host, api.Host) {
+		return errors.NewInvalidArgsError("URL " + raw + " is not on the API host " + cfg.APIURL + " (use a path like /boards.json)")
+	}
+	return nil
+}
+
+func sendAPIRequest(c client.API, method, path string, body interface{}) (*client.APIResponse, error) {
+	ctx := commandContext()
+	switch method {
+	case "GET":
+		return c.GetWithPaginationContext(ctx, path, apiPaginate)
+	case "POST":
+		return c.PostContext(ctx, path, body)
+	case "PATCH":
+		return c.PatchContext(ctx, path, body)
+	case "PUT":
+		return c.PutContext(ctx, path, body)
+	default:
+		return c.DeleteContext(ctx, path)
+	}
+}
+
+// parseAPIFields builds a request body from key=value fields. Keys use Rails
+// bracket notation: card[title] nests, and a trailing [] appends to an array.
+func parseAPIFields(fields []string) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for _, field := range fields {
+		key, value, err := splitAPIField(field)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := fieldKeyParts(key)
+		target := params
+		for i, part := range parts {
+			last := i == len(parts)-1
+			if last {
+				target[part] = value
+				break
+			}
+			if parts[i+1] == "" {
+				// key[] collects values into an array
+				list, _ := target[part].([]interface{})
+				target[part] = append(list, value)
+				break
+			}
+			next, ok := target[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[part] = next
+			}
+			target = next
+		}
+	}
+	return params, nil
+}
+
+// splitAPIField splits key=value, reading the value from a file when it
+// starts with @.
+func splitAPIField(field string) (string, string, error) {
+	key, value, ok := strings.Cut(field, "=")
+	if !ok || key == "" {
+		return "", "", errors.NewInvalidArgsError("Invalid field " + field + " (expected key=value)")
+	}
+	if strings.HasPrefix(value, "@") {
+		content, err := os.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return "", "", errors.NewError("Failed to read field file: " + err.Error())
+		}
+		value = string(content)
+	}
+	return key, value, nil
+}
+
+// fieldKeyParts splits "card[steps][]" into ["card", "steps", ""].
+func fieldKeyParts(key string) []string {
+	name, rest, found := strings.Cut(key, "[")
+	parts := []string{name}
+	if !found {
+		return parts
+	}
+	return append(parts, strings.Split(strings.TrimSuffix(rest, "]"), "][")...)
+}
+
+// withQuery appends fields to path as query parameters, keeping their order.
+func withQuery(path string, fields []string) string {
+	if len(fields) == 0 {
+		return path
+	}
+	var pairs []string
+	for _, field := range fields {
+		key, value, _ := splitAPIField(field)
+		pairs = append(pairs, key+"="+url.QueryEscape(value))
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + strings.Join(pairs, "&")
+}
+
+// readAPIInput reads a JSON request body from a file, or stdin for "-".
+func readAPIInput(path string) (interface{}, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.NewError("Failed to read input: " + err.Error())
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(content, &body); err != nil {
+		return nil, errors.NewInvalidArgsError("Input is not valid JSON: " + err.Error())
+	}
+	return body, nil
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+	apiCmd.Flags().StringArrayVarP(&apiFields, "field", "f", nil, "Request parameter as key=value (repeatable; @file reads the value from a file)")
+	apiCmd.Flags().StringVar(&apiInput, "input", "", "JSON file to send as the request body (- for stdin)")
+	apiCmd.Flags().BoolVar(&apiPaginate, "paginate", false, "Fetch all pages of a GET request")
+}
@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func runAPICommand(args ...string) {
+	RunTestCommand(func() {
+		apiCmd.Run(apiCmd, args)
+	})
+}
+
+func resetAPIFlags() {
+	apiFields = nil
+	apiInput = ""
+	apiPaginate = false
+}
+
+func TestAPICommand(t *testing.T) {
+	t.Run("GET uses pagination and prints envelope", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data:       []interface{}{map[string]interface{}{"id": "1"}},
+			LinkNext:   "https://api.example.com/account/boards.json?page=2",
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		apiPaginate = true
+		runAPICommand("get", "/boards.json")
+		resetAPIFlags()
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		call := mock.GetWithPaginationCalls[0]
+		if call.Path != "/boards.json" || call.Body != true {
+			t.Errorf("expected paginated GET of '/boards.json', got %+v", call)
+		}
+		if result.Response.Pagination == nil || !result.Response.Pagination.HasNext {
+			t.Error("expected pagination in response")
+		}
+	})
+
+	t.Run("GET fields become query parameters", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		apiFields = []string{"board_ids[]=b1", "terms[]=login bug"}
+		runAPICommand("GET", "/cards.json")
+		resetAPIFlags()
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		expected := "/cards.json?board_ids[]=b1&terms[]=login+bug"
+		if mock.GetWithPaginationCalls[0].Path != expected {
+			t.Errorf("expected path %q, got %q", expected, mock.GetWithPaginationCalls[0].Path)
+		}
+	})
+
+	t.Run("POST builds nested body and reports location", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		apiFields = []string{"board_id=b1", "card[title]=Hello", "card[tag_ids][]=t1", "card[tag_ids][]=t2"}
+		runAPICommand("POST", "/cards.json")
+		resetAPIFlags()
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		expected := map[string]interface{}{
+			"board_id": "b1",
+			"card": map[string]interface{}{
+				"title":   "Hello",
+				"tag_ids": []interface{}{"t1", "t2"},
+			},
+		}
+		if !reflect.DeepEqual(mock.PostCalls[0].Body, expected) {
+			t.Errorf("expected body %v, got %v", expected, mock.PostCalls[0].Body)
+		}
+		if result.Response.Location != "https://api.example.com/resource/123" {
+			t.Errorf("expected location in response, got %q", result.Response.Location)
+		}
+	})
+
+	t.Run("PATCH sends input file", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		input := filepath.Join(t.TempDir(), "card.json")
+		os.WriteFile(input, []byte(`{"card":{"title":"Renamed"}}`), 0644)
+
+		apiInput = input
+		runAPICommand("PATCH", "/cards/42.json")
+		resetAPIFlags()
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		body := mock.PatchCalls[0].Body.(map[string]interface{})
+		if body["card"].(map[string]interface{})["title"] != "Renamed" {
+			t.Errorf("unexpected body %v", body)
+		}
+	})
+
+	t.Run("identity paths skip the account prefix", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "", "https://api.example.com")
+		defer ResetTestMode()
+
+		runAPICommand("GET", "/my/identity.json")
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if mock.GetWithPaginationCalls[0].Path != "https://api.example.com/my/identity.json" {
+			t.Errorf("unexpected path %q", mock.GetWithPaginationCalls[0].Path)
+		}
+	})
+
+	t.Run("full URLs must be on the API host", func(t *testing.T) {
+		for _, tt := range []struct {
+			url  string
+			code int
+		}{
+			{"https://api.example.com/account/boards.json", 0},
+			{"https://evil.example.com/account/boards.json", errors.ExitInvalidArgs},
+			{"http://api.example.com/account/boards.json", errors.ExitInvalidArgs},
+			{"https://api.example.com.evil.com/boards.json", errors.ExitInvalidArgs},
+		} {
+			mock := NewMockClient()
+			result := SetTestMode(mock)
+			SetTestConfig("token", "account", "https://api.example.com")
+
+			runAPICommand("GET", tt.url)
+			ResetTestMode()
+
+			if result.ExitCode != tt.code {
+				t.Errorf("%s: expected exit code %d, got %d", tt.url, tt.code, result.ExitCode)
+			}
+			if tt.code != 0 && len(mock.GetWithPaginationCalls) != 0 {
+				t.Errorf("%s: expected no request, got %v", tt.url, mock.GetWithPaginationCalls)
+			}
+		}
+	})
+
+	t.Run("rejects unknown method", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		runAPICommand("FETCH", "/boards.json")
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+
+	t.Run("rejects paginate on POST", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		apiPaginate = true
+		runAPICommand("POST", "/boards.json")
+		resetAPIFlags()
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+
+	t.Run("maps API errors", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.DeleteError = errors.NewNotFoundError("Card not found")
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		runAPICommand("DELETE", "/cards/999.json")
+
+		if result.ExitCode != errors.ExitNotFound {
+			t.Errorf("expected exit code %d, got %d", errors.ExitNotFound, result.ExitCode)
+		}
+	})
+}
+
+func TestParseAPIFields(t *testing.T) {
+	if _, err := parseAPIFields([]string{"novalue"}); err == nil {
+		t.Error("expected error for field without '='")
+	}
+
+	params, err := parseAPIFields([]string{"comment[body]=a=b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["comment"].(map[string]interface{})["body"] != "a=b" {
+		t.Errorf("expected value to keep '=', got %v", params)
+	}
+}
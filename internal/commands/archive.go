@@ -0,0 +1,339 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// Account archives are gzipped tarballs holding a manifest, one JSON file
+// per kind of record, as returned by the API, and the attached files under
+// blobs/. Boards carry their columns, and cards their steps and comments,
+// each comment with its reactions.
+const (
+	archiveFormat  = "fizzy-account"
+	archiveVersion = 1
+)
+
+// archiveManifest describes an account archive.
+type archiveManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	Account    string         `json:"account"`
+	ExportedAt string         `json:"exported_at"`
+	Counts     map[string]int `json:"counts"`
+}
+
+// archiveAttachment is a file referred to from rich text or a card image,
+// stored in the archive at File.
+type archiveAttachment struct {
+	URL         string `json:"url"`
+	File        string `json:"file"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// accountArchive is the content of an account archive.
+type accountArchive struct {
+	Manifest    archiveManifest
+	Users       []interface{}
+	Tags        []interface{}
+	Boards      []interface{}
+	Cards       []interface{}
+	Attachments []archiveAttachment
+}
+
+// archiveFiles are the JSON files of an archive, manifest first.
+var archiveFiles = []string{"manifest.json", "users.json", "tags.json", "boards.json", "cards.json", "attachments.json"}
+
+// files returns pointers to the records of each JSON file by name.
+func (a *accountArchive) files() map[string]interface{} {
+	return map[string]interface{}{
+		"manifest.json":    &a.Manifest,
+		"users.json":       &a.Users,
+		"tags.json":        &a.Tags,
+		"boards.json":      &a.Boards,
+		"cards.json":       &a.Cards,
+		"attachments.json": &a.Attachments,
+	}
+}
+
+// writeArchive writes the archive to out, taking the attached files from
+// blobDir. The archive is written to a temporary file first so that an
+// interrupted export doesn't leave a truncated archive behind.
+func writeArchive(out string, archive *accountArchive, blobDir string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*.tmp")
+	if err != nil {
+		return errors.NewError("Failed to create archive: " + err.Error())
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchiveTo(tmp, archive, blobDir); err != nil {
+		tmp.Close()
+		return errors.NewError("Failed to write archive: " + err.Error())
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewError("Failed to write archive: " + err.Error())
+	}
+	if err := os.Rename(tmp.Name(), out); err != nil {
+		return errors.NewError("Failed to write archive: " + err.Error())
+	}
+	return nil
+}
+
+func writeArchiveTo(w io.Writer, archive *accountArchive, blobDir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	mtime := archiveTime(archive.Manifest.ExportedAt)
+
+	files := archive.files()
+	for _, name := range archiveFiles {
+		data, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: mtime}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	for _, attachment := range archive.Attachments {
+		if err := addArchiveFile(tw, attachment.File, filepath.Join(blobDir, filepath.FromSlash(attachment.File)), mtime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addArchiveFile(tw *tar.Writer, name, src string, mtime time.Time) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: info.Size(), ModTime: mtime}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extractArchive unpacks an archive into dir and reads its records.
+func extractArchive(src, dir string) (*accountArchive, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, errors.NewError("Failed to open archive: " + err.Error())
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.NewInvalidArgsError(src + " is not a gzipped archive: " + err.Error())
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewError("Failed to read archive: " + err.Error())
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, ok := archivePath(header.Name)
+		if !ok {
+			return nil, errors.NewError("Archive contains an invalid path " + strconv.Quote(header.Name))
+		}
+		if err := extractFile(tr, filepath.Join(dir, name)); err != nil {
+			return nil, errors.NewError("Failed to extract archive: " + err.Error())
+		}
+	}
+
+	return readArchive(dir)
+}
+
+func extractFile(r io.Reader, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// archivePath returns a relative path in the archive as a local path,
+// refusing paths that would land outside the extraction directory.
+func archivePath(name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return filepath.FromSlash(name), true
+}
+
+// readArchive reads the records of an extracted archive, checking that this
+// version of the CLI understands it.
+func readArchive(dir string) (*accountArchive, error) {
+	archive := &accountArchive{}
+	files := archive.files()
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, errors.NewInvalidArgsError("Not a fizzy account archive: manifest.json is missing")
+	}
+	if err := json.Unmarshal(data, &archive.Manifest); err != nil || archive.Manifest.Format != archiveFormat {
+		return nil, errors.NewInvalidArgsError("Not a fizzy account archive: unrecognized manifest.json")
+	}
+	if archive.Manifest.Version > archiveVersion {
+		return nil, errors.NewInvalidArgsError(fmt.Sprintf("Archive version %d is newer than this fizzy supports (%d); upgrade fizzy to import it", archive.Manifest.Version, archiveVersion))
+	}
+
+	for _, name := range archiveFiles[1:] {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, errors.NewInvalidArgsError("Archive is incomplete: " + name + " is missing")
+		}
+		if err := json.Unmarshal(data, files[name]); err != nil {
+			return nil, errors.NewInvalidArgsError("Archive is damaged: " + name + ": " + err.Error())
+		}
+	}
+	return archive, nil
+}
+
+// archiveTime parses an export timestamp for file times in the archive.
+func archiveTime(timestamp string) time.Time {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
+
+var (
+	attachmentElementRE = regexp.MustCompile(`(?is)<action-text-attachment\b([^>]*?)\s*/?>(.*?)</action-text-attachment>`)
+	htmlAttrRE          = regexp.MustCompile(`([\w:-]+)\s*=\s*"([^"]*)"`)
+	imgSrcRE            = regexp.MustCompile(`(?i)<img\b[^>]*?\bsrc\s*=\s*"([^"]*)"`)
+)
+
+// richTextAttachment is an attachment found in rich text.
+type richTextAttachment struct {
+	URL         string
+	Filename    string
+	ContentType string
+	Caption     string
+}
+
+// findAttachments returns the attachments in rich text that link to their
+// file, in order.
+func findAttachments(richText string) []richTextAttachment {
+	var found []richTextAttachment
+	for _, m := range attachmentElementRE.FindAllStringSubmatch(richText, -1) {
+		if attachment, ok := parseAttachment(m[1], m[2]); ok {
+			found = append(found, attachment)
+		}
+	}
+	return found
+}
+
+// parseAttachment reads an attachment's attributes, taking its URL from the
+// url attribute or, failing that, an image inside it.
+func parseAttachment(attrs, content string) (richTextAttachment, bool) {
+	values := map[string]string{}
+	for _, a := range htmlAttrRE.FindAllStringSubmatch(attrs, -1) {
+		values[strings.ToLower(a[1])] = html.UnescapeString(a[2])
+	}
+	attachment := richTextAttachment{
+		URL:         values["url"],
+		Filename:    values["filename"],
+		ContentType: values["content-type"],
+		Caption:     values["caption"],
+	}
+	if attachment.URL == "" {
+		if m := imgSrcRE.FindStringSubmatch(content); m != nil {
+			attachment.URL = html.UnescapeString(m[1])
+		}
+	}
+	if attachment.URL == "" {
+		return attachment, false
+	}
+	if attachment.Filename == "" {
+		attachment.Filename = urlFilename(attachment.URL)
+	}
+	return attachment, true
+}
+
+// replaceAttachments replaces the attachments in rich text whose URL is in
+// sgids with tags that embed the upload with that SGID, keeping captions.
+// Other attachments are left as they are.
+func replaceAttachments(richText string, sgids map[string]string) string {
+	return attachmentElementRE.ReplaceAllStringFunc(richText, func(element string) string {
+		m := attachmentElementRE.FindStringSubmatch(element)
+		attachment, ok := parseAttachment(m[1], m[2])
+		if !ok {
+			return element
+		}
+		sgid, ok := sgids[attachment.URL]
+		if !ok {
+			return element
+		}
+		return attachmentTag(sgid, attachment.Caption)
+	})
+}
+
+// urlFilename returns the last part of a URL's path, or "file".
+func urlFilename(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = u.Path
+	}
+	name := path.Base(rawURL)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return safeFilename(name)
+}
+
+// safeFilename makes a filename safe to store in an archive.
+func safeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
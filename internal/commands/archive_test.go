@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	blobDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(blobDir, "blobs", "0001"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(blobDir, "blobs", "0001", "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := &accountArchive{
+		Manifest:    archiveManifest{Format: archiveFormat, Version: archiveVersion, Account: "acme", ExportedAt: "2024-05-01T10:00:00Z"},
+		Users:       []interface{}{map[string]interface{}{"id": "u1"}},
+		Tags:        []interface{}{},
+		Boards:      []interface{}{map[string]interface{}{"id": "b1", "columns": []interface{}{}}},
+		Cards:       []interface{}{},
+		Attachments: []archiveAttachment{{URL: "https://x/a.txt", File: "blobs/0001/a.txt", Filename: "a.txt"}},
+	}
+	out := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if err := writeArchive(out, archive, blobDir); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	read, err := extractArchive(out, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Manifest.Account != "acme" || len(read.Users) != 1 || len(read.Boards) != 1 || read.Attachments[0].URL != "https://x/a.txt" {
+		t.Errorf("unexpected archive %+v", read)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "blobs", "0001", "a.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("expected the blob to be extracted, got %q (%v)", data, err)
+	}
+}
+
+// writeTestTar writes a gzipped tar with the given files.
+func writeTestTar(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return path
+}
+
+func TestExtractArchiveErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"newer version", map[string]string{"manifest.json": `{"format":"fizzy-account","version":2}`}, "Archive version 2 is newer than this fizzy supports (1)"},
+		{"not an archive", map[string]string{"manifest.json": `{"format":"other","version":1}`}, "Not a fizzy account archive"},
+		{"missing file", map[string]string{"manifest.json": `{"format":"fizzy-account","version":1}`}, "Archive is incomplete: users.json is missing"},
+		{"path outside", map[string]string{"../evil": "x"}, `Archive contains an invalid path "../evil"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extractArchive(writeTestTar(t, tt.files), t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestFindAndReplaceAttachments(t *testing.T) {
+	richText := `<p>Hi</p><action-text-attachment sgid="old" content-type="image/png" url="https://x/blobs/k/a%20b.png" filename="a b.png" caption="Chart"><figure><img src="https://x/blobs/k/a%20b.png"></figure></action-text-attachment>` +
+		`<action-text-attachment sgid="other"><img src="https://x/blobs/j/c.png"></action-text-attachment>` +
+		`<action-text-attachment sgid="mention"></action-text-attachment>`
+
+	found := findAttachments(richText)
+	if len(found) != 2 {
+		t.Fatalf("expected 2 attachments with files, got %+v", found)
+	}
+	if found[0].URL != "https://x/blobs/k/a%20b.png" || found[0].Filename != "a b.png" || found[0].Caption != "Chart" {
+		t.Errorf("unexpected first attachment %+v", found[0])
+	}
+	if found[1].URL != "https://x/blobs/j/c.png" || found[1].Filename != "c.png" {
+		t.Errorf("unexpected second attachment %+v", found[1])
+	}
+
+	replaced := replaceAttachments(richText, map[string]string{"https://x/blobs/k/a%20b.png": "new"})
+	want := `<p>Hi</p><action-text-attachment sgid="new" caption="Chart"></action-text-attachment>` +
+		`<action-text-attachment sgid="other"><img src="https://x/blobs/j/c.png"></action-text-attachment>` +
+		`<action-text-attachment sgid="mention"></action-text-attachment>`
+	if replaced != want {
+		t.Errorf("replaceAttachments =\n%s\nwant\n%s", replaced, want)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// uploadAttachments uploads paths and returns the <action-text-attachment>
+// tags that embed them in rich text, one per line.
+func uploadAttachments(c client.API, paths []string) (string, error) {
+	sgids, err := uploadAttachables(c, paths)
+	if err != nil {
+		return "", err
+	}
+	tags := make([]string, len(sgids))
+	for i, sgid := range sgids {
+		tags[i] = attachmentTag(sgid, "")
+	}
+	return strings.Join(tags, "\n"), nil
+}
+
+// uploadAttachables uploads paths and returns their attachable SGIDs.
+func uploadAttachables(c client.API, paths []string) ([]string, error) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, errors.NewError("File not found: " + path)
+		}
+	}
+
+	done := showUploadProgress(c, paths)
+	results, err := uploadFiles(c, paths, "", defaultUploadConcurrency)
+	done()
+	if err != nil {
+		return nil, err
+	}
+
+	sgids := make([]string, len(paths))
+	for i, result := range results {
+		data, _ := result.(map[string]interface{})
+		sgid, _ := data["attachable_sgid"].(string)
+		if sgid == "" {
+			return nil, errors.NewError("Upload of " + paths[i] + " returned no attachable_sgid")
+		}
+		sgids[i] = sgid
+	}
+	return sgids, nil
+}
+
+// attachmentTag embeds an uploaded file in rich text.
+func attachmentTag(sgid, caption string) string {
+	attrs := `sgid="` + html.EscapeString(sgid) + `"`
+	if caption != "" {
+		attrs += ` caption="` + html.EscapeString(caption) + `"`
+	}
+	return "<action-text-attachment " + attrs + "></action-text-attachment>"
+}
+
+// withAttachments appends attachment tags to a rich text body.
+func withAttachments(body, attachments string) string {
+	if body == "" {
+		return attachments
+	}
+	return strings.TrimRight(body, "\n") + "\n" + attachments
+}
+
+// richTextHTML returns the editable HTML of a rich text field from an API
+// response, without the wrapper div the API renders around it.
+func richTextHTML(value interface{}) string {
+	var content string
+	switch v := value.(type) {
+	case string:
+		content = v
+	case map[string]interface{}:
+		content, _ = v["html"].(string)
+	}
+	content = strings.TrimSpace(content)
+	const open = `<div class="action-text-content">`
+	if strings.HasPrefix(content, open) && strings.HasSuffix(content, "</div>") {
+		content = strings.TrimSpace(content[len(open) : len(content)-len("</div>")])
+	}
+	return content
+}
+
+// resolveImage returns the signed ID for a header image given as either a
+// local file, which is uploaded, or an existing signed ID. A value with a
+// path separator or file extension is always taken to be a file.
+func resolveImage(c client.API, image string) (string, error) {
+	info, err := os.Stat(image)
+	if err != nil {
+		if strings.ContainsAny(image, `/\`) || filepath.Ext(image) != "" {
+			if os.IsNotExist(err) {
+				return "", errors.NewNotFoundError("Image file not found: " + image)
+			}
+			return "", errors.NewError("Failed to read " + image + ": " + err.Error())
+		}
+		return image, nil
+	}
+	if info.IsDir() {
+		return "", errors.NewInvalidArgsError(image + " is a directory, not an image")
+	}
+
+	done := showUploadProgress(c, []string{image})
+	resp, err := c.UploadFile(image, "")
+	done()
+	if err != nil {
+		return "", err
+	}
+	data, _ := resp.Data.(map[string]interface{})
+	signedID, _ := data["signed_id"].(string)
+	if signedID == "" {
+		return "", errors.NewError("Upload of " + image + " returned no signed_id")
+	}
+	return signedID, nil
+}
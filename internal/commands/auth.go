@@ -2,6 +2,8 @@ package commands
 
 import (
 	"github.com/robzolkos/fizzy-cli/internal/config"
+	"github.com/robzolkos/fizzy-cli/internal/credentials"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -11,42 +13,129 @@ var authCmd = &cobra.Command{
 	Long:  "Commands for managing API authentication.",
 }
 
+// Auth login flags
+var authLoginStore string
+
 var authLoginCmd = &cobra.Command{
 	Use:   "login TOKEN",
 	Short: "Save API token to config file",
-	Long:  "Saves the provided API token to ~/.config/fizzy/config.yaml for future use.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Saves the provided API token to ~/.config/fizzy/config.yaml for future use.
+
+With --profile, the token is saved to that named profile, creating it if needed.
+
+With --store, the token is kept out of the config file:
+  file         encrypted file next to the global config
+  helper:NAME  external fizzy-credential-NAME program (like git credential helpers)
+  config       plaintext in the config file (default)`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		token := args[0]
+		current := effectiveConfig()
+		profile := current.Profile
+
+		storeName := current.CredentialStore
+		if cmd.Flags().Changed("store") {
+			storeName = authLoginStore
+		}
+		store, err := credentials.New(storeName, config.ConfigDir())
+		if err != nil {
+			exitWithError(errors.NewInvalidArgsError(err.Error()))
+		}
 
 		// Load existing config or create new
 		globalCfg := config.LoadGlobal()
-		globalCfg.Token = token
+		target := globalCfg
+		if profile != "" {
+			target = globalCfg.EnsureProfile(profile)
+		}
+
+		if store != nil {
+			key := credentials.Key{APIURL: current.APIURL, Profile: profile}
+			if err := store.Set(key, token); err != nil {
+				exitWithError(err)
+			}
+			target.Token = ""
+		} else {
+			target.Token = token
+		}
+		if cmd.Flags().Changed("store") {
+			target.CredentialStore = authLoginStore
+			if authLoginStore == credentials.BackendConfig && profile == "" {
+				target.CredentialStore = ""
+			}
+		}
 
 		if err := globalCfg.Save(); err != nil {
 			exitWithError(err)
 		}
 
-		printSuccess(map[string]interface{}{
+		result := map[string]interface{}{
 			"authenticated": true,
 			"message":       "Token saved to config file",
-		})
+		}
+		if store != nil {
+			result["credential_store"] = storeName
+			result["message"] = "Token saved to credential store " + storeName
+		}
+		if profile != "" {
+			result["profile"] = profile
+			if store == nil {
+				result["message"] = "Token saved to profile " + profile
+			}
+		}
+		printSuccess(result)
 	},
 }
 
 var authLogoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Remove saved credentials",
-	Long:  "Removes the config file containing saved credentials.",
+	Long: `Removes the config file containing saved credentials.
+
+With --profile, only that profile is removed. If other profiles are defined,
+logging out without --profile clears the top-level token and keeps them.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := config.Delete(); err != nil {
-			exitWithError(err)
+		current := effectiveConfig()
+		profile := current.Profile
+		globalCfg := config.LoadGlobal()
+
+		// Erase the token from the credential store, if one is used
+		store, err := current.Credentials()
+		if err != nil {
+			exitWithError(errors.NewInvalidArgsError(err.Error()))
+		}
+		if store != nil {
+			if err := store.Delete(current.CredentialKey()); err != nil {
+				exitWithError(err)
+			}
 		}
 
-		printSuccess(map[string]interface{}{
+		result := map[string]interface{}{
 			"authenticated": false,
 			"message":       "Logged out successfully",
-		})
+		}
+
+		switch {
+		case profile != "":
+			if !globalCfg.RemoveProfile(profile) {
+				exitWithError(errors.NewNotFoundError("Profile not found: " + profile))
+			}
+			if err := globalCfg.Save(); err != nil {
+				exitWithError(err)
+			}
+			result["profile"] = profile
+		case len(globalCfg.Profiles) > 0:
+			globalCfg.Token = ""
+			if err := globalCfg.Save(); err != nil {
+				exitWithError(err)
+			}
+		default:
+			if err := config.Delete(); err != nil {
+				exitWithError(err)
+			}
+		}
+
+		printSuccess(result)
 	},
 }
 
@@ -63,6 +152,18 @@ var authStatusCmd = &cobra.Command{
 		status := map[string]interface{}{
 			"authenticated": effectiveCfg.Token != "",
 		}
+		if effectiveCfg.Profile != "" {
+			status["profile"] = effectiveCfg.Profile
+		}
+		if effectiveCfg.CredentialStore != "" && effectiveCfg.CredentialStore != credentials.BackendConfig {
+			status["credential_store"] = effectiveCfg.CredentialStore
+			if effectiveCfg.CredentialError != nil {
+				status["credential_error"] = effectiveCfg.CredentialError.Error()
+			}
+		}
+		if names := config.LoadGlobal().ProfileNames(); len(names) > 0 {
+			status["profiles"] = names
+		}
 
 		if effectiveCfg.Token != "" {
 			status["token_configured"] = true
@@ -80,6 +181,7 @@ var authStatusCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(authCmd)
+	authLoginCmd.Flags().StringVar(&authLoginStore, "store", "", "Where to keep the token: config, file, or helper:NAME")
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authLogoutCmd)
 	authCmd.AddCommand(authStatusCmd)
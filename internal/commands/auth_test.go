@@ -1,11 +1,14 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/internal/config"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
 	"gopkg.in/yaml.v3"
 )
 
@@ -263,3 +266,184 @@ func TestAuthStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthProfiles(t *testing.T) {
+	t.Run("login saves token to named profile", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config.SetTestConfigDir(tempDir)
+		defer config.ResetTestConfigDir()
+
+		os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte("token: top-token\n"), 0600)
+
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("", "", "")
+		cfg.Profile = "work"
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			authLoginCmd.Run(authLoginCmd, []string{"work-token"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+
+		saved := config.LoadGlobal()
+		if saved.Token != "top-token" {
+			t.Errorf("expected top-level token to be preserved, got '%s'", saved.Token)
+		}
+		if saved.Profiles["work"] == nil || saved.Profiles["work"].Token != "work-token" {
+			t.Errorf("expected profile 'work' to have token 'work-token', got %+v", saved.Profiles["work"])
+		}
+	})
+
+	t.Run("logout removes only the named profile", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config.SetTestConfigDir(tempDir)
+		defer config.ResetTestConfigDir()
+
+		configData := "token: top-token\nprofiles:\n  work:\n    token: work-token\n  oss:\n    token: oss-token\n"
+		os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(configData), 0600)
+
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("", "", "")
+		cfg.Profile = "work"
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			authLogoutCmd.Run(authLogoutCmd, []string{})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+
+		saved := config.LoadGlobal()
+		if _, ok := saved.Profiles["work"]; ok {
+			t.Error("expected profile 'work' to be removed")
+		}
+		if saved.Profiles["oss"] == nil {
+			t.Error("expected profile 'oss' to be kept")
+		}
+		if saved.Token != "top-token" {
+			t.Errorf("expected top-level token to be kept, got '%s'", saved.Token)
+		}
+	})
+
+	t.Run("logout without profile keeps other profiles", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config.SetTestConfigDir(tempDir)
+		defer config.ResetTestConfigDir()
+
+		configData := "token: top-token\nprofiles:\n  oss:\n    token: oss-token\n"
+		os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(configData), 0600)
+
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("", "", "")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			authLogoutCmd.Run(authLogoutCmd, []string{})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+
+		saved := config.LoadGlobal()
+		if saved.Token != "" {
+			t.Errorf("expected top-level token to be cleared, got '%s'", saved.Token)
+		}
+		if saved.Profiles["oss"] == nil {
+			t.Error("expected profile 'oss' to be kept")
+		}
+	})
+
+	t.Run("status reports active profile", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config.SetTestConfigDir(tempDir)
+		defer config.ResetTestConfigDir()
+
+		configData := "profiles:\n  work:\n    token: work-token\n    account: work-account\n"
+		os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(configData), 0600)
+
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		defer ResetTestMode()
+
+		var err error
+		cfg, err = config.LoadProfile("work")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		RunTestCommand(func() {
+			authStatusCmd.Run(authStatusCmd, []string{})
+		})
+
+		data := result.Response.Data.(map[string]interface{})
+		if data["profile"] != "work" {
+			t.Errorf("expected profile 'work', got %v", data["profile"])
+		}
+		if data["account"] != "work-account" {
+			t.Errorf("expected account 'work-account', got %v", data["account"])
+		}
+	})
+
+	t.Run("unknown profile fails auth check", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		cfgProfileErr = fmt.Errorf("profile \"nope\" not found in global config")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			boardListCmd.Run(boardListCmd, []string{})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
+
+func TestAuthLoginCredentialStore(t *testing.T) {
+	tempDir := t.TempDir()
+	config.SetTestConfigDir(tempDir)
+	defer config.ResetTestConfigDir()
+
+	mock := NewMockClient()
+	result := SetTestMode(mock)
+	SetTestConfig("", "", config.DefaultAPIURL)
+	defer ResetTestMode()
+
+	authLoginCmd.Flags().Set("store", "file")
+	defer func() {
+		authLoginCmd.Flags().Set("store", "")
+		authLoginCmd.Flags().Lookup("store").Changed = false
+	}()
+
+	RunTestCommand(func() {
+		authLoginCmd.Run(authLoginCmd, []string{"secret-token"})
+	})
+
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(tempDir, "config.yaml"))
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("expected token not to be written to config file")
+	}
+	if !strings.Contains(string(data), "credential_store: file") {
+		t.Errorf("expected credential_store to be saved, got:\n%s", string(data))
+	}
+
+	loaded := config.Load()
+	if loaded.Token != "secret-token" {
+		t.Errorf("expected token to resolve through file store, got '%s'", loaded.Token)
+	}
+}
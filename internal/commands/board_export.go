@@ -0,0 +1,450 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/richtext"
+	"github.com/spf13/cobra"
+)
+
+// Board export flags
+var boardExportFormat string
+var boardExportOut string
+var boardExportComments bool
+var boardExportConcurrency int
+
+var boardExportCmd = &cobra.Command{
+	Use:   "export [BOARD]",
+	Short: "Export a board as a report",
+	Long: `Writes a report of a board as Markdown, CSV, HTML or JSON.
+
+Lanes are in board order: Not Now, Maybe?, the board's columns, then Done.
+Each card is listed with its number, title, assignees, tags and steps (as
+checkboxes), and with --comments, its comments. Cards are in number order
+and nothing in the report depends on when it was written, so reports of an
+unchanged board are identical and can be committed and diffed.
+
+The report is written to stdout as is, or with --out to a file, in which
+case a summary of the export is printed instead.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+		render, ok := boardReportFormats[boardExportFormat]
+		if !ok {
+			exitWithError(errors.NewInvalidArgsError("Invalid format " + boardExportFormat + " (use markdown, csv, html or json)"))
+		}
+		if boardExportConcurrency < 1 {
+			exitWithError(errors.NewInvalidArgsError("--concurrency must be at least 1"))
+		}
+
+		board := ""
+		if len(args) > 0 {
+			board = args[0]
+		}
+		boardID, err := requireBoard(board)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		report, err := buildBoardReport(getClient(), boardID, boardExportComments, boardExportConcurrency)
+		if err != nil {
+			exitWithError(err)
+		}
+		content, err := render(report)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		if boardExportOut == "" || boardExportOut == "-" {
+			if _, err := stdout.Write(content); err != nil {
+				exitWithError(errors.NewError("Failed to write report: " + err.Error()))
+			}
+			return
+		}
+		if err := os.WriteFile(boardExportOut, content, 0o644); err != nil {
+			exitWithError(errors.NewError("Failed to write " + boardExportOut + ": " + err.Error()))
+		}
+		cards := 0
+		for _, lane := range report.Lanes {
+			cards += len(lane.Cards)
+		}
+		printSuccess(map[string]interface{}{
+			"file":   boardExportOut,
+			"format": boardExportFormat,
+			"board":  map[string]interface{}{"id": report.ID, "name": report.Name},
+			"lanes":  len(report.Lanes),
+			"cards":  cards,
+		})
+	},
+}
+
+// boardReport is a board laid out for a report. It is also the JSON
+// format, so field order and names are part of the output.
+type boardReport struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Lanes []reportLane `json:"lanes"`
+}
+
+type reportLane struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Pseudo bool         `json:"pseudo"`
+	Cards  []reportCard `json:"cards"`
+}
+
+type reportCard struct {
+	Number    int             `json:"number"`
+	Title     string          `json:"title"`
+	Assignees []string        `json:"assignees"`
+	Tags      []string        `json:"tags"`
+	Steps     []reportStep    `json:"steps"`
+	Comments  []reportComment `json:"comments,omitempty"`
+}
+
+type reportStep struct {
+	Content   string `json:"content"`
+	Completed bool   `json:"completed"`
+}
+
+type reportComment struct {
+	Author    string `json:"author"`
+	CreatedAt string `json:"created_at"`
+	Body      string `json:"body"`
+}
+
+// boardReportFormats renders a report in each --format.
+var boardReportFormats = map[string]func(*boardReport) ([]byte, error){
+	"markdown": renderBoardMarkdown,
+	"csv":      renderBoardCSV,
+	"html":     renderBoardHTML,
+	"json":     renderBoardJSON,
+}
+
+// buildBoardReport fetches a board's columns and cards, with each card's
+// steps and optionally its comments, and sorts the cards into lanes.
+func buildBoardReport(c client.API, boardID string, comments bool, concurrency int) (*boardReport, error) {
+	report := &boardReport{ID: boardID, Name: boardID}
+	resp, err := c.Get("/boards/" + boardID + ".json")
+	if err != nil {
+		return nil, err
+	}
+	if board, ok := resp.Data.(map[string]interface{}); ok {
+		if name, _ := board["name"].(string); name != "" {
+			report.Name = name
+		}
+	}
+
+	resp, err = c.Get("/boards/" + boardID + "/columns.json")
+	if err != nil {
+		return nil, err
+	}
+	columns, _ := resp.Data.([]interface{})
+
+	// Real columns go between the pseudo columns for untriaged and closed
+	// cards, as on the board.
+	laneIndex := map[string]int{}
+	for _, p := range pseudoColumnsInBoardOrder() {
+		if p.Kind == "closed" {
+			for _, item := range columns {
+				column, _ := item.(map[string]interface{})
+				id, _ := column["id"].(string)
+				name, _ := column["name"].(string)
+				laneIndex[id] = len(report.Lanes)
+				report.Lanes = append(report.Lanes, reportLane{ID: id, Name: name, Cards: []reportCard{}})
+			}
+		}
+		laneIndex[p.ID] = len(report.Lanes)
+		report.Lanes = append(report.Lanes, reportLane{ID: p.ID, Name: p.Name, Pseudo: true, Cards: []reportCard{}})
+	}
+
+	// Each card goes in the first lane that lists it
+	lanes := map[string]string{}
+	var numbers []string
+	for _, p := range []pseudoColumn{pseudoColumnNotNow, pseudoColumnDone, pseudoColumnMaybe} {
+		path := "/cards.json?board_ids[]=" + boardID
+		if p.Kind != "triage" {
+			path += "&indexed_by=" + p.Kind
+		}
+		items, err := listAll(c, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			card, _ := item.(map[string]interface{})
+			number := cardNumber(card)
+			if number == "" || lanes[number] != "" {
+				continue
+			}
+			lane := p.ID
+			if columnID := cardColumnID(card); p.Kind == "triage" && columnID != "" {
+				if _, ok := laneIndex[columnID]; ok {
+					lane = columnID
+				}
+			}
+			lanes[number] = lane
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool {
+		a, _ := strconv.Atoi(numbers[i])
+		b, _ := strconv.Atoi(numbers[j])
+		return a < b
+	})
+	if len(numbers) == 0 {
+		return report, nil
+	}
+
+	results, err := runBulk(numbers, concurrency, func(number string) (interface{}, error) {
+		return reportCardFor(c, number, comments)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		card := result.(map[string]interface{})["data"].(reportCard)
+		lane := &report.Lanes[laneIndex[lanes[numbers[i]]]]
+		lane.Cards = append(lane.Cards, card)
+	}
+	return report, nil
+}
+
+// reportCardFor fetches a card, and its comments if wanted, for a report.
+func reportCardFor(c client.API, number string, comments bool) (reportCard, error) {
+	resp, err := c.Get("/cards/" + number + ".json")
+	if err != nil {
+		return reportCard{}, err
+	}
+	card, _ := decodeCard(resp.Data)
+	result := reportCard{
+		Number:    card.Number,
+		Title:     card.Title,
+		Assignees: []string{},
+		Tags:      []string{},
+		Steps:     []reportStep{},
+	}
+	if result.Number == 0 {
+		result.Number, _ = strconv.Atoi(number)
+	}
+	for _, user := range card.Assignees {
+		result.Assignees = append(result.Assignees, user.Name)
+	}
+	sort.Strings(result.Assignees)
+	result.Tags = append(result.Tags, card.Tags...)
+	sort.Strings(result.Tags)
+	for _, step := range card.Steps {
+		result.Steps = append(result.Steps, reportStep{Content: step.Content, Completed: step.Completed})
+	}
+
+	if !comments {
+		return result, nil
+	}
+	items, err := listAll(c, "/cards/"+number+"/comments.json")
+	if err != nil {
+		return reportCard{}, err
+	}
+	result.Comments = []reportComment{}
+	for _, item := range items {
+		comment, _ := item.(map[string]interface{})
+		entry := reportComment{Author: "Unknown", Body: richtext.Render(richTextHTML(comment["body"]), richtext.Options{Plain: true})}
+		if creator, ok := comment["creator"].(map[string]interface{}); ok {
+			if name, _ := creator["name"].(string); name != "" {
+				entry.Author = name
+			}
+		}
+		if createdAt, _ := comment["created_at"].(string); createdAt != "" {
+			entry.CreatedAt = createdAt
+			if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+				entry.CreatedAt = t.UTC().Format(time.RFC3339)
+			}
+		}
+		result.Comments = append(result.Comments, entry)
+	}
+	sort.SliceStable(result.Comments, func(i, j int) bool {
+		return result.Comments[i].CreatedAt < result.Comments[j].CreatedAt
+	})
+	return result, nil
+}
+
+// reportTime formats a comment time for Markdown and HTML.
+func reportTime(createdAt string) string {
+	t, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return createdAt
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+func renderBoardMarkdown(report *boardReport) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", reportText(report.Name))
+	for _, lane := range report.Lanes {
+		fmt.Fprintf(&b, "\n## %s\n", reportText(lane.Name))
+		if len(lane.Cards) == 0 {
+			b.WriteString("\n_No cards_\n")
+			continue
+		}
+		for _, card := range lane.Cards {
+			fmt.Fprintf(&b, "\n### #%d %s\n", card.Number, reportText(card.Title))
+			if len(card.Assignees) > 0 || len(card.Tags) > 0 {
+				b.WriteString("\n")
+			}
+			if len(card.Assignees) > 0 {
+				fmt.Fprintf(&b, "- Assignees: %s\n", reportText(strings.Join(card.Assignees, ", ")))
+			}
+			if len(card.Tags) > 0 {
+				fmt.Fprintf(&b, "- Tags: %s\n", reportText(strings.Join(card.Tags, ", ")))
+			}
+			if len(card.Steps) > 0 {
+				b.WriteString("\n")
+				for _, step := range card.Steps {
+					fmt.Fprintf(&b, "- %s %s\n", checkbox(step.Completed), reportText(step.Content))
+				}
+			}
+			for _, comment := range card.Comments {
+				fmt.Fprintf(&b, "\n> **%s** · %s\n>\n", reportText(comment.Author), reportTime(comment.CreatedAt))
+				for _, line := range strings.Split(comment.Body, "\n") {
+					b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+				}
+			}
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// reportText escapes plain text, such as a card title, for one line of a
+// Markdown report. Line breaks become spaces, and # is escaped so a title
+// can't close a heading early.
+func reportText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(richtext.EscapeMarkdown(text), "#", `\#`)
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func renderBoardCSV(report *boardReport) ([]byte, error) {
+	comments := false
+	for _, lane := range report.Lanes {
+		for _, card := range lane.Cards {
+			comments = comments || card.Comments != nil
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"lane", "number", "title", "assignees", "tags", "steps"}
+	if comments {
+		header = append(header, "comments")
+	}
+	w.Write(header)
+	for _, lane := range report.Lanes {
+		for _, card := range lane.Cards {
+			steps := make([]string, len(card.Steps))
+			for i, step := range card.Steps {
+				steps[i] = checkbox(step.Completed) + " " + step.Content
+			}
+			row := []string{
+				lane.Name,
+				strconv.Itoa(card.Number),
+				card.Title,
+				strings.Join(card.Assignees, ", "),
+				strings.Join(card.Tags, ", "),
+				strings.Join(steps, "\n"),
+			}
+			if comments {
+				bodies := make([]string, len(card.Comments))
+				for i, comment := range card.Comments {
+					bodies[i] = comment.Author + " (" + reportTime(comment.CreatedAt) + "): " + comment.Body
+				}
+				row = append(row, strings.Join(bodies, "\n\n"))
+			}
+			w.Write(row)
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+const boardReportStyle = `body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+section { margin-bottom: 2rem; }
+article { border: 1px solid #ddd; border-radius: 6px; padding: 0.5rem 1rem; margin: 0.5rem 0; }
+.meta { color: #666; margin: 0.25rem 0; }
+ul.steps { list-style: none; padding-left: 0; }
+blockquote { border-left: 3px solid #ddd; margin: 0.5rem 0; padding-left: 0.75rem; white-space: pre-wrap; }
+.empty { color: #999; font-style: italic; }`
+
+func renderBoardHTML(report *boardReport) ([]byte, error) {
+	esc := html.EscapeString
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", esc(report.Name), boardReportStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", esc(report.Name))
+	for _, lane := range report.Lanes {
+		fmt.Fprintf(&b, "<section>\n<h2>%s</h2>\n", esc(lane.Name))
+		if len(lane.Cards) == 0 {
+			b.WriteString("<p class=\"empty\">No cards</p>\n")
+		}
+		for _, card := range lane.Cards {
+			fmt.Fprintf(&b, "<article>\n<h3>#%d %s</h3>\n", card.Number, esc(card.Title))
+			if len(card.Assignees) > 0 {
+				fmt.Fprintf(&b, "<p class=\"meta\">Assignees: %s</p>\n", esc(strings.Join(card.Assignees, ", ")))
+			}
+			if len(card.Tags) > 0 {
+				fmt.Fprintf(&b, "<p class=\"meta\">Tags: %s</p>\n", esc(strings.Join(card.Tags, ", ")))
+			}
+			if len(card.Steps) > 0 {
+				b.WriteString("<ul class=\"steps\">\n")
+				for _, step := range card.Steps {
+					checked := ""
+					if step.Completed {
+						checked = " checked"
+					}
+					fmt.Fprintf(&b, "<li><input type=\"checkbox\" disabled%s> %s</li>\n", checked, esc(step.Content))
+				}
+				b.WriteString("</ul>\n")
+			}
+			for _, comment := range card.Comments {
+				fmt.Fprintf(&b, "<blockquote><strong>%s</strong> · %s\n%s</blockquote>\n",
+					esc(comment.Author), esc(reportTime(comment.CreatedAt)), esc(comment.Body))
+			}
+			b.WriteString("</article>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return []byte(b.String()), nil
+}
+
+func renderBoardJSON(report *boardReport) ([]byte, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.NewError("Failed to encode report: " + err.Error())
+	}
+	return append(data, '\n'), nil
+}
+
+func init() {
+	boardExportCmd.Flags().StringVar(&boardExportFormat, "format", "markdown", "Report format: markdown, csv, html or json")
+	boardExportCmd.Flags().StringVar(&boardExportOut, "out", "", "File to write instead of stdout")
+	boardExportCmd.Flags().BoolVar(&boardExportComments, "comments", false, "Include each card's comments")
+	boardExportCmd.Flags().IntVar(&boardExportConcurrency, "concurrency", defaultBulkConcurrency, "Number of cards to fetch at once")
+	boardCmd.AddCommand(boardExportCmd)
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// newBoardExportMock returns a board with a column and a card in each lane.
+func newBoardExportMock() *MockClient {
+	list := func(items ...interface{}) *client.APIResponse {
+		return &client.APIResponse{StatusCode: 200, Data: items}
+	}
+	get := func(data interface{}) *client.APIResponse {
+		return &client.APIResponse{StatusCode: 200, Data: data}
+	}
+	column := map[string]interface{}{"id": "col-1", "name": "Doing"}
+	card := func(number float64, title string, extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"number": number, "title": title}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	mock := NewMockClient()
+	mock.GetWithPaginationResponses = map[string]*client.APIResponse{
+		"/cards.json?board_ids[]=board-1&indexed_by=not_now": list(card(5, "Later", nil)),
+		"/cards.json?board_ids[]=board-1&indexed_by=closed":  list(card(2, "Shipped", nil)),
+		"/cards.json?board_ids[]=board-1":                    list(card(9, "Second", map[string]interface{}{"column": column}), card(4, "First", map[string]interface{}{"column": column}), card(1, "Idea", nil)),
+		"/cards/4/comments.json": list(
+			map[string]interface{}{"created_at": "2024-01-03T00:00:00Z", "creator": map[string]interface{}{"name": "Bob"}, "body": map[string]interface{}{"html": "<p>Second</p>"}},
+			map[string]interface{}{"created_at": "2024-01-02T00:00:00Z", "creator": map[string]interface{}{"name": "Ann"}, "body": map[string]interface{}{"html": "<p>First</p>"}},
+		),
+	}
+	mock.GetResponses = map[string]*client.APIResponse{
+		"/boards/board-1.json":         get(map[string]interface{}{"id": "board-1", "name": "Roadmap"}),
+		"/boards/board-1/columns.json": get([]interface{}{column}),
+		"/cards/4.json": get(card(4, "First", map[string]interface{}{
+			"tags":      []interface{}{"ux", "bug"},
+			"assignees": []interface{}{map[string]interface{}{"name": "Zoe"}, map[string]interface{}{"name": "Ann"}},
+			"steps": []interface{}{
+				map[string]interface{}{"content": "Design", "completed": true},
+				map[string]interface{}{"content": "Build", "completed": false},
+			},
+		})),
+	}
+	for _, c := range []map[string]interface{}{card(1, "Idea", nil), card(2, "Shipped", nil), card(5, "Later", nil), card(9, "Second", nil)} {
+		mock.GetResponses["/cards/"+cardNumber(c)+".json"] = get(c)
+	}
+	return mock
+}
+
+func TestBoardExport(t *testing.T) {
+	run := func(t *testing.T, format string, comments bool) (*CommandResult, string) {
+		t.Helper()
+		result := SetTestMode(newBoardExportMock())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		out := filepath.Join(t.TempDir(), "report")
+		boardExportFormat, boardExportOut, boardExportComments, boardExportConcurrency = format, out, comments, 2
+		defer func() {
+			boardExportFormat, boardExportOut, boardExportComments, boardExportConcurrency = "markdown", "", false, defaultBulkConcurrency
+		}()
+
+		RunTestCommand(func() {
+			boardExportCmd.Run(boardExportCmd, []string{"board-1"})
+		})
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result, string(data)
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		result, got := run(t, "markdown", true)
+		want := `# Roadmap
+
+## Not Now
+
+### #5 Later
+
+## Maybe?
+
+### #1 Idea
+
+## Doing
+
+### #4 First
+
+- Assignees: Ann, Zoe
+- Tags: bug, ux
+
+- [x] Design
+- [ ] Build
+
+> **Ann** · 2024-01-02 00:00 UTC
+>
+> First
+
+> **Bob** · 2024-01-03 00:00 UTC
+>
+> Second
+
+### #9 Second
+
+## Done
+
+### #2 Shipped
+`
+		if got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+		data := result.Response.Data.(map[string]interface{})
+		if data["cards"] != 5 || data["lanes"] != 4 {
+			t.Errorf("unexpected summary %v", data)
+		}
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		for _, format := range []string{"markdown", "csv", "html", "json"} {
+			_, first := run(t, format, true)
+			_, second := run(t, format, true)
+			if first != second {
+				t.Errorf("%s output changed between runs", format)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		_, got := run(t, "csv", false)
+		lines := strings.SplitN(got, "\n", 3)
+		if lines[0] != "lane,number,title,assignees,tags,steps" || lines[1] != "Not Now,5,Later,,," {
+			t.Errorf("unexpected csv\n%s", got)
+		}
+		if !strings.Contains(got, "Doing,4,First,\"Ann, Zoe\",\"bug, ux\",\"[x] Design\n[ ] Build\"") {
+			t.Errorf("expected the card's steps as checkboxes\n%s", got)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		_, got := run(t, "html", false)
+		for _, want := range []string{"<h1>Roadmap</h1>", "<h2>Doing</h2>", `<input type="checkbox" disabled checked> Design`, `<input type="checkbox" disabled> Build`} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		_, got := run(t, "json", false)
+		if !strings.Contains(got, `"name": "Maybe?"`) || strings.Contains(got, `"comments"`) {
+			t.Errorf("unexpected json\n%s", got)
+		}
+	})
+
+	t.Run("writes to stdout by default", func(t *testing.T) {
+		result := SetTestMode(newBoardExportMock())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		var out bytes.Buffer
+		stdout = &out
+		defer func() { stdout = os.Stdout }()
+
+		RunTestCommand(func() {
+			boardExportCmd.Run(boardExportCmd, []string{"board-1"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		if result.Response != nil && result.Response.Data != nil {
+			t.Errorf("expected no response envelope, got %v", result.Response.Data)
+		}
+		if !strings.HasPrefix(out.String(), "# Roadmap\n\n## Not Now\n") {
+			t.Errorf("expected the report on stdout, got\n%s", out.String())
+		}
+	})
+
+	t.Run("escapes titles in markdown", func(t *testing.T) {
+		mock := newBoardExportMock()
+		mock.GetResponses["/boards/board-1.json"] = &client.APIResponse{StatusCode: 200, Data: map[string]interface{}{"name": "*Road* map"}}
+		mock.GetResponses["/cards/2.json"] = &client.APIResponse{StatusCode: 200, Data: map[string]interface{}{
+			"number": float64(2), "title": "Fix [login](x) for C#\n# now",
+		}}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		var out bytes.Buffer
+		stdout = &out
+		defer func() { stdout = os.Stdout }()
+
+		RunTestCommand(func() {
+			boardExportCmd.Run(boardExportCmd, []string{"board-1"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		for _, want := range []string{"# \\*Road\\* map\n", "### #2 Fix \\[login\\](x) for C\\# \\# now\n"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("rejects an unknown format", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		boardExportFormat, boardExportOut = "pdf", "report.pdf"
+		defer func() { boardExportFormat, boardExportOut = "markdown", "" }()
+
+		RunTestCommand(func() {
+			boardExportCmd.Run(boardExportCmd, []string{"board-1"})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
@@ -0,0 +1,618 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var boardTUICmd = &cobra.Command{
+	Use:   "tui [BOARD]",
+	Short: "Open an interactive kanban view of a board",
+	Long: `Opens a full-screen kanban view of a board.
+
+Lanes are shown in board order: Not Now, Maybe?, the board's columns, then Done.
+
+Keys:
+  ←/→ or h/l        select lane
+  ↑/↓ or k/j        select card
+  shift+←/→ or H/L  move the selected card to the previous/next lane
+  enter             open the selected card
+  c                 add a comment (in the card view)
+  r                 reload the board
+  esc               back
+  q                 quit`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+
+		board := ""
+		if len(args) > 0 {
+			board = args[0]
+		}
+		boardID, err := requireBoard(board)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		p := tea.NewProgram(newBoardTUI(getClient(), boardID), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	boardCmd.AddCommand(boardTUICmd)
+}
+
+type tuiMode int
+
+const (
+	tuiModeBoard tuiMode = iota
+	tuiModeCard
+	tuiModeComment
+)
+
+// tuiLane is one lane of the kanban view: a pseudo column or a real column.
+type tuiLane struct {
+	ID     string
+	Name   string
+	Pseudo bool
+	Cards  []map[string]interface{}
+}
+
+// boardTUI is the bubbletea model behind `fizzy board tui`.
+type boardTUI struct {
+	client  client.API
+	boardID string
+
+	boardName string
+	lanes     []tuiLane
+	lane      int
+	row       int
+
+	mode     tuiMode
+	card     map[string]interface{}
+	comments []interface{}
+	input    textinput.Model
+
+	loading bool
+	status  string
+	err     error
+	width   int
+	height  int
+}
+
+type boardLoadedMsg struct {
+	name  string
+	lanes []tuiLane
+	err   error
+}
+
+type cardMovedMsg struct {
+	number string
+	lane   string
+	err    error
+}
+
+type cardLoadedMsg struct {
+	card     map[string]interface{}
+	comments []interface{}
+	err      error
+}
+
+type commentAddedMsg struct {
+	err error
+}
+
+func newBoardTUI(c client.API, boardID string) *boardTUI {
+	input := textinput.New()
+	input.Placeholder = "Write a comment…"
+	input.CharLimit = 0
+	input.Cursor.SetMode(cursor.CursorStatic)
+
+	return &boardTUI{
+		client:  c,
+		boardID: boardID,
+		input:   input,
+		loading: true,
+		width:   80,
+		height:  24,
+	}
+}
+
+func (m *boardTUI) Init() tea.Cmd {
+	return m.loadBoard
+}
+
+// loadBoard fetches the board, its columns and its cards, and groups the
+// cards into lanes.
+func (m *boardTUI) loadBoard() tea.Msg {
+	name := m.boardID
+	if resp, err := m.client.Get("/boards/" + m.boardID + ".json"); err == nil {
+		if board, ok := resp.Data.(map[string]interface{}); ok {
+			if n, ok := board["name"].(string); ok && n != "" {
+				name = n
+			}
+		}
+	}
+
+	resp, err := m.client.Get("/boards/" + m.boardID + "/columns.json")
+	if err != nil {
+		return boardLoadedMsg{err: err}
+	}
+	columns, _ := resp.Data.([]interface{})
+
+	lanes := []tuiLane{
+		{ID: pseudoColumnNotNow.ID, Name: pseudoColumnNotNow.Name, Pseudo: true},
+		{ID: pseudoColumnMaybe.ID, Name: pseudoColumnMaybe.Name, Pseudo: true},
+	}
+	laneIndex := map[string]int{}
+	for _, item := range columns {
+		col, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := col["id"].(string)
+		colName, _ := col["name"].(string)
+		laneIndex[id] = len(lanes)
+		lanes = append(lanes, tuiLane{ID: id, Name: colName})
+	}
+	lanes = append(lanes, tuiLane{ID: pseudoColumnDone.ID, Name: pseudoColumnDone.Name, Pseudo: true})
+
+	for _, p := range pseudoColumnsInBoardOrder() {
+		if p.Kind == "triage" {
+			continue
+		}
+		cards, err := m.fetchCards("&indexed_by=" + p.Kind)
+		if err != nil {
+			return boardLoadedMsg{err: err}
+		}
+		idx := 0
+		if p.Kind == "closed" {
+			idx = len(lanes) - 1
+		}
+		lanes[idx].Cards = cards
+	}
+
+	cards, err := m.fetchCards("")
+	if err != nil {
+		return boardLoadedMsg{err: err}
+	}
+	for _, card := range cards {
+		idx, ok := laneIndex[cardColumnID(card)]
+		if !ok {
+			idx = 1 // Maybe?
+		}
+		lanes[idx].Cards = append(lanes[idx].Cards, card)
+	}
+
+	return boardLoadedMsg{name: name, lanes: lanes}
+}
+
+func (m *boardTUI) fetchCards(params string) ([]map[string]interface{}, error) {
+	resp, err := m.client.GetWithPagination("/cards.json?board_ids[]="+m.boardID+params, true)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := resp.Data.([]interface{})
+	cards := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if card, ok := item.(map[string]interface{}); ok {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+// cardColumnID returns the ID of the column a card is in, or "" if the card
+// is untriaged.
+func cardColumnID(card map[string]interface{}) string {
+	typed, _ := decodeCard(card)
+	return typed.ColumnID()
+}
+
+func cardNumber(card map[string]interface{}) string {
+	switch n := card["number"].(type) {
+	case float64:
+		return fmt.Sprintf("%d", int64(n))
+	case string:
+		return n
+	}
+	return ""
+}
+
+func (m *boardTUI) selectedCard() map[string]interface{} {
+	if m.lane < 0 || m.lane >= len(m.lanes) {
+		return nil
+	}
+	cards := m.lanes[m.lane].Cards
+	if m.row < 0 || m.row >= len(cards) {
+		return nil
+	}
+	return cards[m.row]
+}
+
+// moveSelected moves the selected card by delta lanes.
+func (m *boardTUI) moveSelected(delta int) tea.Cmd {
+	card := m.selectedCard()
+	target := m.lane + delta
+	if card == nil || target < 0 || target >= len(m.lanes) {
+		return nil
+	}
+	from, to := m.lanes[m.lane], m.lanes[target]
+	number := cardNumber(card)
+	c := m.client
+
+	// Optimistically move the card so the cursor follows it.
+	m.lanes[m.lane].Cards = append(from.Cards[:m.row:m.row], from.Cards[m.row+1:]...)
+	m.lanes[target].Cards = append(m.lanes[target].Cards, card)
+	m.lane, m.row = target, len(m.lanes[target].Cards)-1
+	m.status = fmt.Sprintf("Moving #%s to %s…", number, to.Name)
+
+	return func() tea.Msg {
+		// Closed cards must be reopened before they can go anywhere else.
+		if from.ID == pseudoColumnDone.ID {
+			if _, err := c.Delete("/cards/" + number + "/closure.json"); err != nil {
+				return cardMovedMsg{number: number, lane: to.Name, err: err}
+			}
+		}
+		_, err := moveCardToColumn(c, number, to.ID)
+		return cardMovedMsg{number: number, lane: to.Name, err: err}
+	}
+}
+
+func (m *boardTUI) openSelected() tea.Cmd {
+	card := m.selectedCard()
+	if card == nil {
+		return nil
+	}
+	number := cardNumber(card)
+	c := m.client
+	m.mode = tuiModeCard
+	m.card = card
+	m.comments = nil
+	m.loading = true
+
+	return func() tea.Msg {
+		resp, err := c.Get("/cards/" + number + ".json")
+		if err != nil {
+			return cardLoadedMsg{err: err}
+		}
+		full, _ := resp.Data.(map[string]interface{})
+		comments, err := c.GetWithPagination("/cards/"+number+"/comments.json", true)
+		if err != nil {
+			return cardLoadedMsg{card: full, err: err}
+		}
+		items, _ := comments.Data.([]interface{})
+		return cardLoadedMsg{card: full, comments: items}
+	}
+}
+
+func (m *boardTUI) submitComment() tea.Cmd {
+	body := strings.TrimSpace(m.input.Value())
+	if body == "" || m.card == nil {
+		return nil
+	}
+	number := cardNumber(m.card)
+	c := m.client
+	m.status = "Adding comment…"
+
+	return func() tea.Msg {
+		// Comments are Markdown, as with comment create
+		html, err := markdownToHTML(body, ".")
+		if err != nil {
+			return commentAddedMsg{err: err}
+		}
+		reqBody := map[string]interface{}{
+			"comment": map[string]interface{}{
+				"body": html,
+			},
+		}
+		_, err = c.Post("/cards/"+number+"/comments.json", reqBody)
+		return commentAddedMsg{err: err}
+	}
+}
+
+func (m *boardTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case boardLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.boardName = msg.name
+			m.lanes = msg.lanes
+			m.clampCursor()
+		}
+		return m, nil
+
+	case cardMovedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.status = ""
+			return m, m.loadBoard
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("Moved #%s to %s", msg.number, msg.lane)
+		return m, nil
+
+	case cardLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.card != nil {
+			m.card = msg.card
+		}
+		m.comments = msg.comments
+		return m, nil
+
+	case commentAddedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.status = ""
+			return m, nil
+		}
+		m.err = nil
+		m.status = "Comment added"
+		m.input.Reset()
+		m.mode = tuiModeCard
+		return m, m.openSelected()
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case tuiModeComment:
+			return m.updateComment(msg)
+		case tuiModeCard:
+			return m.updateCard(msg)
+		default:
+			return m.updateBoard(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m *boardTUI) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "left", "h":
+		if m.lane > 0 {
+			m.lane--
+			m.clampCursor()
+		}
+	case "right", "l":
+		if m.lane < len(m.lanes)-1 {
+			m.lane++
+			m.clampCursor()
+		}
+	case "up", "k":
+		if m.row > 0 {
+			m.row--
+		}
+	case "down", "j":
+		if m.lane < len(m.lanes) && m.row < len(m.lanes[m.lane].Cards)-1 {
+			m.row++
+		}
+	case "shift+left", "H":
+		return m, m.moveSelected(-1)
+	case "shift+right", "L":
+		return m, m.moveSelected(1)
+	case "enter":
+		return m, m.openSelected()
+	case "r":
+		m.loading = true
+		m.status = ""
+		return m, m.loadBoard
+	}
+	return m, nil
+}
+
+func (m *boardTUI) updateCard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "backspace":
+		m.mode = tuiModeBoard
+		m.card = nil
+		m.comments = nil
+	case "c":
+		m.mode = tuiModeComment
+		m.input.Reset()
+		return m, m.input.Focus()
+	}
+	return m, nil
+}
+
+func (m *boardTUI) updateComment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.input.Blur()
+		m.mode = tuiModeCard
+		return m, nil
+	case "enter":
+		m.input.Blur()
+		return m, m.submitComment()
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *boardTUI) clampCursor() {
+	if m.lane >= len(m.lanes) {
+		m.lane = len(m.lanes) - 1
+	}
+	if m.lane < 0 {
+		m.lane = 0
+	}
+	if m.lane >= len(m.lanes) {
+		m.row = 0
+		return
+	}
+	if n := len(m.lanes[m.lane].Cards); m.row >= n {
+		m.row = n - 1
+	}
+	if m.row < 0 {
+		m.row = 0
+	}
+}
+
+var (
+	tuiTitleStyle    = lipgloss.NewStyle().Bold(true)
+	tuiDimStyle      = lipgloss.NewStyle().Faint(true)
+	tuiErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	tuiLaneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	tuiActiveLane    = tuiLaneStyle.BorderForeground(lipgloss.Color("12"))
+	tuiSelectedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+const tuiLaneWidth = 28
+
+func (m *boardTUI) View() string {
+	var b strings.Builder
+	switch {
+	case m.mode != tuiModeBoard:
+		b.WriteString(m.cardView())
+	case m.loading && m.lanes == nil:
+		b.WriteString("Loading board…\n")
+	default:
+		b.WriteString(tuiTitleStyle.Render(m.boardName) + "\n")
+		b.WriteString(m.lanesView() + "\n")
+		b.WriteString(tuiDimStyle.Render("←/→ lane  ↑/↓ card  H/L move  enter open  r reload  q quit") + "\n")
+	}
+	if m.err != nil {
+		b.WriteString(tuiErrorStyle.Render("Error: "+m.err.Error()) + "\n")
+	} else if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	return b.String()
+}
+
+// lanesView renders as many lanes as fit the terminal, keeping the selected
+// lane visible.
+func (m *boardTUI) lanesView() string {
+	visible := m.width / (tuiLaneWidth + 2)
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if m.lane >= visible {
+		start = m.lane - visible + 1
+	}
+	end := start + visible
+	if end > len(m.lanes) {
+		end = len(m.lanes)
+	}
+
+	maxCards := m.height - 8
+	if maxCards < 1 {
+		maxCards = 1
+	}
+
+	rendered := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		lane := m.lanes[i]
+		lines := []string{tuiTitleStyle.Render(fmt.Sprintf("%s (%d)", lane.Name, len(lane.Cards)))}
+		for j, card := range lane.Cards {
+			if j >= maxCards {
+				lines = append(lines, tuiDimStyle.Render(fmt.Sprintf("… %d more", len(lane.Cards)-j)))
+				break
+			}
+			title, _ := card["title"].(string)
+			line := truncate(fmt.Sprintf("#%s %s", cardNumber(card), title), tuiLaneWidth-2)
+			if i == m.lane && j == m.row {
+				line = tuiSelectedStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+
+		style := tuiLaneStyle
+		if i == m.lane {
+			style = tuiActiveLane
+		}
+		rendered = append(rendered, style.Width(tuiLaneWidth).Render(strings.Join(lines, "\n")))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m *boardTUI) cardView() string {
+	var b strings.Builder
+	card := m.card
+	title, _ := card["title"].(string)
+	b.WriteString(tuiTitleStyle.Render(fmt.Sprintf("#%s %s", cardNumber(card), title)) + "\n\n")
+
+	if desc, _ := card["description"].(string); strings.TrimSpace(desc) != "" {
+		b.WriteString(desc + "\n\n")
+	}
+
+	if steps, ok := card["steps"].([]interface{}); ok && len(steps) > 0 {
+		b.WriteString(tuiTitleStyle.Render("Steps") + "\n")
+		for _, item := range steps {
+			step, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mark := "[ ]"
+			if done, _ := step["completed"].(bool); done {
+				mark = "[x]"
+			}
+			content, _ := step["content"].(string)
+			b.WriteString(fmt.Sprintf("%s %s\n", mark, content))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(tuiTitleStyle.Render(fmt.Sprintf("Comments (%d)", len(m.comments))) + "\n")
+	if m.loading {
+		b.WriteString(tuiDimStyle.Render("Loading…") + "\n")
+	}
+	for _, item := range m.comments {
+		comment, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		author := ""
+		if creator, ok := comment["creator"].(map[string]interface{}); ok {
+			author, _ = creator["name"].(string)
+		}
+		text := ""
+		if body, ok := comment["body"].(map[string]interface{}); ok {
+			text, _ = body["plain_text"].(string)
+		}
+		b.WriteString(tuiDimStyle.Render(author+":") + " " + strings.TrimSpace(text) + "\n")
+	}
+	b.WriteString("\n")
+
+	if m.mode == tuiModeComment {
+		b.WriteString(m.input.View() + "\n")
+		b.WriteString(tuiDimStyle.Render("enter send  esc cancel") + "\n")
+	} else {
+		b.WriteString(tuiDimStyle.Render("c comment  esc back") + "\n")
+	}
+	return b.String()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
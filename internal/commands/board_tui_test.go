@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robzolkos/fizzy-cli/internal/client"
+)
+
+// tuiBoardAPI returns a mock serving a board with two columns and a card
+// in most lanes.
+func tuiBoardAPI() *MockClient {
+	mock := NewMockClient()
+	mock.GetResponses = map[string]*client.APIResponse{}
+	mock.GetWithPaginationResponses = map[string]*client.APIResponse{}
+	serve(mock, "/boards/b1.json", map[string]interface{}{"id": "b1", "name": "Engineering"})
+	serve(mock, "/boards/b1/columns.json", []interface{}{
+		map[string]interface{}{"id": "col-1", "name": "Doing"},
+		map[string]interface{}{"id": "col-2", "name": "Review"},
+	})
+	serve(mock, "/cards.json?board_ids[]=b1", []interface{}{
+		map[string]interface{}{"number": float64(1), "title": "Untriaged"},
+		map[string]interface{}{"number": float64(2), "title": "In progress", "column": map[string]interface{}{"id": "col-1"}},
+	})
+	serve(mock, "/cards.json?board_ids[]=b1&indexed_by=not_now", []interface{}{
+		map[string]interface{}{"number": float64(3), "title": "Later"},
+	})
+	serve(mock, "/cards.json?board_ids[]=b1&indexed_by=closed", []interface{}{
+		map[string]interface{}{"number": float64(4), "title": "Shipped"},
+	})
+	return mock
+}
+
+// serve makes the mock return data for path, whether it's fetched as a
+// single page or a list.
+func serve(mock *MockClient, path string, data interface{}) {
+	resp := &client.APIResponse{StatusCode: 200, Data: data}
+	mock.GetResponses[path] = resp
+	mock.GetWithPaginationResponses[path] = resp
+}
+
+// loadedTUI returns a board TUI with the initial load applied.
+func loadedTUI(t *testing.T, api *MockClient) *boardTUI {
+	t.Helper()
+	m := newBoardTUI(api, "b1")
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	m.Update(m.Init()())
+	if m.err != nil {
+		t.Fatalf("unexpected load error: %v", m.err)
+	}
+	return m
+}
+
+// press sends a key to the model and runs any resulting command once.
+func press(m *boardTUI, key string) {
+	var msg tea.KeyMsg
+	switch key {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "shift+right":
+		msg = tea.KeyMsg{Type: tea.KeyShiftRight}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	_, cmd := m.Update(msg)
+	if cmd != nil {
+		if next := cmd(); next != nil {
+			if _, ok := next.(tea.KeyMsg); !ok {
+				m.Update(next)
+			}
+		}
+	}
+}
+
+func TestBoardTUI_LanesInBoardOrder(t *testing.T) {
+	m := loadedTUI(t, tuiBoardAPI())
+
+	var names []string
+	for _, lane := range m.lanes {
+		names = append(names, lane.Name)
+	}
+	expected := "Not Now,Maybe?,Doing,Review,Done"
+	if got := strings.Join(names, ","); got != expected {
+		t.Fatalf("expected lanes %q, got %q", expected, got)
+	}
+
+	counts := []int{1, 1, 1, 0, 1}
+	for i, lane := range m.lanes {
+		if len(lane.Cards) != counts[i] {
+			t.Errorf("lane %s: expected %d cards, got %d", lane.Name, counts[i], len(lane.Cards))
+		}
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Engineering") || !strings.Contains(view, "#2 In progress") {
+		t.Errorf("expected board name and cards in view, got:\n%s", view)
+	}
+}
+
+func TestBoardTUI_MoveCard(t *testing.T) {
+	t.Run("maybe to first column triages", func(t *testing.T) {
+		mock := tuiBoardAPI()
+		m := loadedTUI(t, mock)
+
+		press(m, "l") // Maybe?
+		press(m, "L") // → Doing
+
+		if len(mock.PostCalls) != 1 || mock.PostCalls[0].Path != "/cards/1/triage.json" {
+			t.Fatalf("expected triage call, got %v", mock.PostCalls)
+		}
+		body := mock.PostCalls[0].Body.(map[string]interface{})
+		if body["column_id"] != "col-1" {
+			t.Errorf("expected column_id 'col-1', got %v", body["column_id"])
+		}
+		if m.lane != 2 || len(m.lanes[2].Cards) != 2 {
+			t.Errorf("expected card to move to Doing with the cursor, lane=%d cards=%d", m.lane, len(m.lanes[2].Cards))
+		}
+		if !strings.Contains(m.status, "Moved #1 to Doing") {
+			t.Errorf("unexpected status %q", m.status)
+		}
+	})
+
+	t.Run("into done closes", func(t *testing.T) {
+		mock := tuiBoardAPI()
+		m := loadedTUI(t, mock)
+
+		press(m, "l")
+		press(m, "l") // Doing
+		press(m, "l") // Review
+		press(m, "h") // back to Doing
+		press(m, "shift+right")
+		press(m, "L") // Done
+
+		want := []string{"/cards/2/triage.json", "/cards/2/closure.json"}
+		if got := mock.PostPaths(); strings.Join(got, ";") != strings.Join(want, ";") {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("out of done reopens first", func(t *testing.T) {
+		mock := tuiBoardAPI()
+		m := loadedTUI(t, mock)
+		m.lane = len(m.lanes) - 1
+
+		press(m, "H")
+
+		if len(mock.DeleteCalls) != 1 || mock.DeleteCalls[0].Path != "/cards/4/closure.json" {
+			t.Errorf("expected the card to be reopened, got %v", mock.DeleteCalls)
+		}
+		if got := mock.PostPaths(); strings.Join(got, ";") != "/cards/4/triage.json" {
+			t.Errorf("expected the card to be triaged, got %v", got)
+		}
+	})
+
+	t.Run("into not now postpones", func(t *testing.T) {
+		mock := tuiBoardAPI()
+		m := loadedTUI(t, mock)
+		m.lane = 1
+
+		press(m, "H")
+
+		if got := mock.PostPaths(); strings.Join(got, ";") != "/cards/1/not_now.json" {
+			t.Errorf("expected not_now call, got %v", got)
+		}
+	})
+}
+
+func TestBoardTUI_OpenCardAndComment(t *testing.T) {
+	mock := tuiBoardAPI()
+	serve(mock, "/cards/2.json", map[string]interface{}{
+		"number":      float64(2),
+		"title":       "In progress",
+		"description": "Some details",
+		"steps": []interface{}{
+			map[string]interface{}{"content": "Write tests", "completed": true},
+		},
+	})
+	serve(mock, "/cards/2/comments.json", []interface{}{
+		map[string]interface{}{
+			"creator": map[string]interface{}{"name": "Ada"},
+			"body":    map[string]interface{}{"plain_text": "Looks good"},
+		},
+	})
+	m := loadedTUI(t, mock)
+	m.lane = 2
+
+	press(m, "enter")
+	if m.mode != tuiModeCard {
+		t.Fatalf("expected card mode, got %d", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"Some details", "[x] Write tests", "Ada:", "Looks good"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected card view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	press(m, "c")
+	if m.mode != tuiModeComment {
+		t.Fatalf("expected comment mode, got %d", m.mode)
+	}
+	m.input.SetValue("Ship *it* <now>")
+	press(m, "enter")
+
+	if len(mock.PostCalls) != 1 || mock.PostCalls[0].Path != "/cards/2/comments.json" {
+		t.Fatalf("expected comment post, got %v", mock.PostPaths())
+	}
+	body := mock.PostCalls[0].Body.(map[string]interface{})
+	comment := body["comment"].(map[string]interface{})
+	if comment["body"] != "<p>Ship <em>it</em> &lt;now&gt;</p>" {
+		t.Errorf("expected the comment as HTML, got %v", comment["body"])
+	}
+	if m.mode != tuiModeCard {
+		t.Errorf("expected to return to card mode, got %d", m.mode)
+	}
+
+	press(m, "esc")
+	if m.mode != tuiModeBoard {
+		t.Errorf("expected board mode after esc, got %d", m.mode)
+	}
+}
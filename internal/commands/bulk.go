@@ -0,0 +1,333 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// defaultBulkConcurrency is how many cards are changed at once.
+const defaultBulkConcurrency = 4
+
+// stdin is where card numbers are read from for "-" (can be overridden for
+// testing).
+var stdin io.Reader = os.Stdin
+
+// stdout is where output that isn't a JSON response, such as a report, is
+// written (can be overridden for testing).
+var stdout io.Writer = os.Stdout
+
+// Bulk card action flags, shared by the card action commands
+var bulkWhere string
+var bulkBoard string
+var bulkIndexedBy string
+var bulkDryRun bool
+var bulkConcurrency int
+var bulkYes bool
+
+// bulkHelp is appended to the help of commands that take several cards.
+const bulkHelp = `
+
+Several cards can be given at once: as arguments, as "-" to read card
+numbers (or the JSON output of card list) from stdin, or with --where to
+select cards with a card list expression, narrowed by --board and
+--indexed-by. Cards are changed concurrently and the result lists each card
+with its data or error; the command fails if any card does. Use --dry-run to
+list the cards without changing them.`
+
+// addBulkFlags registers the flags for acting on several cards.
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Long += bulkHelp
+	cmd.Flags().StringVar(&bulkWhere, "where", "", "Act on the cards matching a card list expression")
+	cmd.Flags().StringVar(&bulkBoard, "board", "", "With --where, only cards on this board (ID or name)")
+	cmd.Flags().StringVar(&bulkIndexedBy, "indexed-by", "", "With --where, only cards in this lane/index (e.g. closed, not_now, all)")
+	cmd.Flags().BoolVar(&bulkDryRun, "dry-run", false, "List the cards that would be affected without changing them")
+	cmd.Flags().IntVar(&bulkConcurrency, "concurrency", defaultBulkConcurrency, "Number of cards to change at once")
+}
+
+// addBulkConfirmFlag registers --yes for commands whose changes can't be
+// undone.
+func addBulkConfirmFlag(cmd *cobra.Command) {
+	cmd.Long += `
+
+Cards selected with --where or read from stdin are only changed after you
+confirm, or with --yes when there's no terminal to ask on.`
+	cmd.Flags().BoolVarP(&bulkYes, "yes", "y", false, "Don't ask before changing cards selected with --where or from stdin")
+}
+
+// confirmBulk asks on the terminal whether to go ahead with a bulk action.
+// It reports false without asking when there's no terminal (can be
+// overridden for testing).
+var confirmBulk = func(title string) bool {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return false
+	}
+	var confirmed bool
+	err := huh.NewConfirm().
+		Title(title).
+		Value(&confirmed).
+		Run()
+	return err == nil && confirmed
+}
+
+// cardAction changes one card and returns the response data.
+type cardAction func(number string) (interface{}, error)
+
+// actionData returns the data of an action response, or fallback if there
+// is none.
+func actionData(resp *client.APIResponse, fallback map[string]interface{}) interface{} {
+	if resp != nil && resp.Data != nil {
+		return resp.Data
+	}
+	return fallback
+}
+
+// runCardAction applies action to the cards given by args and the bulk
+// flags. A single card number is handled as before: its data is printed or
+// its error returned as is.
+func runCardAction(c client.API, args []string, action cardAction) {
+	runDestructiveCardAction(c, args, "", action)
+}
+
+// runDestructiveCardAction is runCardAction for actions that can't be
+// undone, such as delete, named by verb. Cards selected with --where or read
+// from stdin are only changed once the user confirms, or with --yes. An
+// empty verb needs no confirmation.
+func runDestructiveCardAction(c client.API, args []string, verb string, action cardAction) {
+	if bulkConcurrency < 1 {
+		exitWithError(errors.NewInvalidArgsError("--concurrency must be at least 1"))
+	}
+	if bulkWhere == "" && (bulkBoard != "" || bulkIndexedBy != "") {
+		exitWithError(errors.NewInvalidArgsError("--board and --indexed-by select cards for --where"))
+	}
+
+	if len(args) == 1 && args[0] != "-" && bulkWhere == "" && !bulkDryRun {
+		data, err := action(args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+		printSuccess(data)
+		return
+	}
+
+	numbers, cards, err := bulkCardNumbers(c, args)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	if bulkDryRun {
+		// Show the cards, fetching the ones given by number
+		if cards == nil {
+			results, err := runBulk(numbers, bulkConcurrency, func(number string) (interface{}, error) {
+				resp, err := c.Get("/cards/" + number + ".json")
+				if err != nil {
+					return nil, err
+				}
+				return resp.Data, nil
+			})
+			if err != nil {
+				currentResource = "card_bulk"
+				exitWithError(err)
+			}
+			for _, result := range results {
+				cards = append(cards, result.(map[string]interface{})["data"])
+			}
+		}
+		currentResource = "card"
+		printSuccess(cards)
+		return
+	}
+
+	if verb != "" && !bulkYes && (bulkWhere != "" || slices.Contains(args, "-")) {
+		count := fmt.Sprintf("%d cards", len(numbers))
+		if len(numbers) == 1 {
+			count = "1 card"
+		}
+		if !confirmBulk(fmt.Sprintf("%s %s? This can't be undone.", strings.ToUpper(verb[:1])+verb[1:], count)) {
+			exitWithError(errors.NewInvalidArgsError(fmt.Sprintf("Not going to %s %s without confirmation (use --yes, or --dry-run to list them)", verb, count)))
+		}
+	}
+
+	results, err := runBulk(numbers, bulkConcurrency, action)
+	currentResource = "card_bulk"
+	if err != nil {
+		exitWithError(err)
+	}
+	printSuccess(results)
+}
+
+// bulkCardNumbers returns the card numbers to act on, and the cards
+// themselves when they were selected with --where.
+func bulkCardNumbers(c client.API, args []string) ([]string, []interface{}, error) {
+	if bulkWhere != "" {
+		if len(args) > 0 {
+			return nil, nil, errors.NewInvalidArgsError("Give card numbers or --where, not both")
+		}
+		query, err := buildCardQuery(c, cardFilterFlags{
+			board:     bulkBoard,
+			indexedBy: bulkIndexedBy,
+			where:     bulkWhere,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := c.GetWithPagination(cardsPath(query.params), true)
+		if err != nil {
+			return nil, nil, err
+		}
+		items, ok := resp.Data.([]interface{})
+		if !ok {
+			return nil, nil, errors.NewError("Unexpected cards list response")
+		}
+		cards, err := query.filter(c, items)
+		if err != nil {
+			return nil, nil, err
+		}
+		numbers := make([]string, 0, len(cards))
+		for _, item := range cards {
+			card, _ := item.(map[string]interface{})
+			numbers = append(numbers, cardNumber(card))
+		}
+		return numbers, cards, nil
+	}
+
+	if len(args) == 0 {
+		return nil, nil, errors.NewInvalidArgsError("Give card numbers, - to read them from stdin, or --where")
+	}
+	var numbers []string
+	for _, arg := range args {
+		if arg != "-" {
+			numbers = append(numbers, arg)
+			continue
+		}
+		read, err := readCardNumbers(stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		numbers = append(numbers, read...)
+	}
+	numbers, err := uniqueCardNumbers(numbers)
+	return numbers, nil, err
+}
+
+// readCardNumbers reads card numbers separated by whitespace or commas, or
+// the JSON output of a card listing.
+func readCardNumbers(r io.Reader) ([]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.NewError("Failed to read card numbers: " + err.Error())
+	}
+	text := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(text, "{") && !strings.HasPrefix(text, "[") {
+		return strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		}), nil
+	}
+
+	var input interface{}
+	if err := json.Unmarshal([]byte(text), &input); err != nil {
+		return nil, errors.NewInvalidArgsError("Input is not valid JSON: " + err.Error())
+	}
+	if envelope, ok := input.(map[string]interface{}); ok {
+		input = envelope["data"]
+	}
+	items, ok := input.([]interface{})
+	if !ok {
+		return nil, errors.NewInvalidArgsError("Expected a list of cards on stdin")
+	}
+	var numbers []string
+	for _, item := range items {
+		card, _ := item.(map[string]interface{})
+		number := cardNumber(card)
+		if number == "" {
+			return nil, errors.NewInvalidArgsError("Expected a list of cards on stdin")
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+// uniqueCardNumbers checks card numbers, dropping repeats. A leading # is
+// allowed.
+func uniqueCardNumbers(numbers []string) ([]string, error) {
+	seen := make(map[string]bool, len(numbers))
+	unique := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		number = strings.TrimPrefix(number, "#")
+		if n, err := strconv.Atoi(number); err != nil || n < 1 {
+			return nil, errors.NewInvalidArgsError("Invalid card number " + strconv.Quote(number))
+		}
+		if !seen[number] {
+			seen[number] = true
+			unique = append(unique, number)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, errors.NewInvalidArgsError("No card numbers given")
+	}
+	return unique, nil
+}
+
+// runBulk applies action to each card number with at most concurrency
+// actions in flight. The results list each card in order with its data or
+// error; if any card failed, the error carries them as partial data.
+func runBulk(numbers []string, concurrency int, action cardAction) ([]interface{}, error) {
+	data := make([]interface{}, len(numbers))
+	errs := make([]error, len(numbers))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(numbers)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				data[i], errs[i] = action(numbers[i])
+			}
+		}()
+	}
+	for i := range numbers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	results := make([]interface{}, len(numbers))
+	failed := 0
+	for i, number := range numbers {
+		n, _ := strconv.Atoi(number)
+		result := map[string]interface{}{"number": n, "success": errs[i] == nil}
+		if errs[i] != nil {
+			failed++
+			cliErr, ok := errs[i].(*errors.CLIError)
+			if !ok {
+				cliErr = errors.NewError(errs[i].Error())
+			}
+			detail := map[string]interface{}{"code": cliErr.Code, "message": cliErr.Message}
+			if cliErr.Status != 0 {
+				detail["status"] = cliErr.Status
+			}
+			result["error"] = detail
+		} else {
+			result["data"] = data[i]
+		}
+		results[i] = result
+	}
+
+	if failed > 0 {
+		err := errors.NewError(fmt.Sprintf("%d of %d cards failed", failed, len(numbers)))
+		err.Partial = results
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,342 @@
+package commands
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func TestReadCardNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"lines", "1\n2\r\n3\n", []string{"1", "2", "3"}},
+		{"commas and spaces", "1, 2 #3", []string{"1", "2", "#3"}},
+		{"card list output", `{"success": true, "data": [{"number": 4}, {"number": 5}]}`, []string{"4", "5"}},
+		{"card array", `[{"number": 6}]`, []string{"6"}},
+		{"empty", "", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCardNumbers(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+
+	for _, input := range []string{`{"data": {"number": 1}}`, `[{"title": "x"}]`, `[1`} {
+		if _, err := readCardNumbers(strings.NewReader(input)); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestUniqueCardNumbers(t *testing.T) {
+	got, err := uniqueCardNumbers([]string{"3", "#1", "3", "1"})
+	if err != nil || !reflect.DeepEqual(got, []string{"3", "1"}) {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := uniqueCardNumbers([]string{"12", "abc"}); err == nil || err.Error() != `Invalid card number "abc"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := uniqueCardNumbers(nil); err == nil {
+		t.Error("expected an error for no numbers")
+	}
+}
+
+func TestCardCloseBulk(t *testing.T) {
+	t.Run("closes each card once", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.PostResponse.Data = nil
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardCloseCmd.Run(cardCloseCmd, []string{"2", "1", "#2"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		got := mock.PostPaths()
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, []string{"/cards/1/closure.json", "/cards/2/closure.json"}) {
+			t.Errorf("unexpected posts %v", got)
+		}
+		want := []interface{}{
+			map[string]interface{}{"number": 2, "success": true, "data": map[string]interface{}{}},
+			map[string]interface{}{"number": 1, "success": true, "data": map[string]interface{}{}},
+		}
+		if !reflect.DeepEqual(result.Response.Data, want) {
+			t.Errorf("unexpected results %v", result.Response.Data)
+		}
+		if result.Response.Resource != "card_bulk" {
+			t.Errorf("expected card_bulk resource, got %q", result.Response.Resource)
+		}
+	})
+
+	t.Run("reports each failure", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.PostErrors = map[string]error{"/cards/2/closure.json": errors.NewNotFoundError("Card not found")}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardCloseCmd.Run(cardCloseCmd, []string{"1", "2"})
+		})
+
+		if result.ExitCode != errors.ExitError {
+			t.Fatalf("expected exit code %d, got %d", errors.ExitError, result.ExitCode)
+		}
+		if msg := result.Response.Error.Message; msg != "1 of 2 cards failed" {
+			t.Errorf("unexpected message %q", msg)
+		}
+		results := result.Response.Data.([]interface{})
+		failed := results[1].(map[string]interface{})
+		wantErr := map[string]interface{}{"code": "NOT_FOUND", "message": "Card not found", "status": 404}
+		if failed["success"] != false || !reflect.DeepEqual(failed["error"], wantErr) {
+			t.Errorf("unexpected failure %v", failed)
+		}
+		if results[0].(map[string]interface{})["success"] != true {
+			t.Errorf("expected card 1 to succeed, got %v", results[0])
+		}
+	})
+
+	t.Run("reads card numbers from stdin", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		stdin = strings.NewReader("3\n4\n")
+		defer func() { stdin = os.Stdin }()
+		RunTestCommand(func() {
+			cardPostponeCmd.Run(cardPostponeCmd, []string{"-"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		got := mock.PostPaths()
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, []string{"/cards/3/not_now.json", "/cards/4/not_now.json"}) {
+			t.Errorf("unexpected posts %v", got)
+		}
+	})
+
+	t.Run("selects cards with --where", func(t *testing.T) {
+		mock := NewMockClient().WithListData([]interface{}{
+			map[string]interface{}{"number": float64(7), "last_active_at": "2020-01-01T00:00:00Z"},
+			map[string]interface{}{"number": float64(8), "last_active_at": "2999-01-01T00:00:00Z"},
+		})
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		bulkWhere = "last_active_at < -30d"
+		bulkBoard = "123"
+		RunTestCommand(func() {
+			cardCloseCmd.Run(cardCloseCmd, []string{})
+		})
+		bulkWhere = ""
+		bulkBoard = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if path := mock.GetWithPaginationCalls[0].Path; path != "/cards.json?board_ids[]=123" {
+			t.Errorf("unexpected list path %q", path)
+		}
+		got := mock.PostPaths()
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, []string{"/cards/7/closure.json"}) {
+			t.Errorf("unexpected posts %v", got)
+		}
+	})
+
+	t.Run("lists the cards with --dry-run", func(t *testing.T) {
+		mock := NewMockClient().WithGetData(map[string]interface{}{"number": float64(5), "title": "Card"})
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		bulkDryRun = true
+		RunTestCommand(func() {
+			cardDeleteCmd.Run(cardDeleteCmd, []string{"5"})
+		})
+		bulkDryRun = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.DeleteCalls) != 0 {
+			t.Errorf("expected no deletes, got %v", mock.DeleteCalls)
+		}
+		want := []interface{}{map[string]interface{}{"number": float64(5), "title": "Card"}}
+		if !reflect.DeepEqual(result.Response.Data, want) {
+			t.Errorf("unexpected data %v", result.Response.Data)
+		}
+	})
+
+	t.Run("confirms deleting cards from stdin", func(t *testing.T) {
+		defer func(orig func(string) bool) { confirmBulk = orig }(confirmBulk)
+		tests := []struct {
+			name    string
+			yes     bool
+			answer  bool
+			deletes int
+		}{
+			{"declined", false, false, 0},
+			{"confirmed", false, true, 2},
+			{"--yes", true, false, 2},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mock := NewMockClient()
+				result := SetTestMode(mock)
+				SetTestConfig("token", "account", "https://api.example.com")
+				defer ResetTestMode()
+
+				var asked []string
+				confirmBulk = func(title string) bool {
+					asked = append(asked, title)
+					return tt.answer
+				}
+				stdin = strings.NewReader("3 4")
+				bulkYes = tt.yes
+				RunTestCommand(func() {
+					cardDeleteCmd.Run(cardDeleteCmd, []string{"-"})
+				})
+				bulkYes = false
+				stdin = os.Stdin
+
+				if len(mock.DeleteCalls) != tt.deletes {
+					t.Errorf("expected %d deletes, got %v", tt.deletes, mock.DeleteCalls)
+				}
+				if tt.yes && len(asked) != 0 {
+					t.Errorf("expected no question with --yes, got %v", asked)
+				}
+				if !tt.yes && !reflect.DeepEqual(asked, []string{"Delete 2 cards? This can't be undone."}) {
+					t.Errorf("unexpected questions %v", asked)
+				}
+				if tt.deletes == 0 && result.ExitCode != errors.ExitInvalidArgs {
+					t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+				}
+			})
+		}
+	})
+
+	t.Run("doesn't confirm reversible actions", func(t *testing.T) {
+		defer func(orig func(string) bool) { confirmBulk = orig }(confirmBulk)
+		confirmBulk = func(string) bool {
+			t.Error("unexpected confirmation")
+			return false
+		}
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		stdin = strings.NewReader("3 4")
+		RunTestCommand(func() {
+			cardCloseCmd.Run(cardCloseCmd, []string{"-"})
+		})
+		stdin = os.Stdin
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+	})
+
+	t.Run("rejects conflicting selections", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			args    []string
+			where   string
+			board   string
+			message string
+		}{
+			{"numbers and --where", []string{"1"}, "closed", "", "Give card numbers or --where, not both"},
+			{"--board without --where", []string{"1"}, "", "123", "--board and --indexed-by select cards for --where"},
+			{"nothing", nil, "", "", "Give card numbers, - to read them from stdin, or --where"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mock := NewMockClient()
+				result := SetTestMode(mock)
+				SetTestConfig("token", "account", "https://api.example.com")
+				defer ResetTestMode()
+
+				bulkWhere = tt.where
+				bulkBoard = tt.board
+				RunTestCommand(func() {
+					cardCloseCmd.Run(cardCloseCmd, tt.args)
+				})
+				bulkWhere = ""
+				bulkBoard = ""
+
+				if result.ExitCode != errors.ExitInvalidArgs {
+					t.Fatalf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+				}
+				if msg := result.Response.Error.Message; msg != tt.message {
+					t.Errorf("expected message %q, got %q", tt.message, msg)
+				}
+			})
+		}
+	})
+}
+
+func TestCardColumnBulk(t *testing.T) {
+	mock := NewMockClient().WithGetData(map[string]interface{}{"board": map[string]interface{}{"id": "b1"}})
+	mock.GetResponses = map[string]*client.APIResponse{
+		"/boards/b1/columns.json": {
+			StatusCode: 200,
+			Data:       []interface{}{map[string]interface{}{"id": "col-1", "name": "Doing"}},
+		},
+	}
+	result := SetTestMode(mock)
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	cardColumnColumn = "Doing"
+	RunTestCommand(func() {
+		cardColumnCmd.Run(cardColumnCmd, []string{"1", "2", "3"})
+	})
+	cardColumnColumn = ""
+
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+	lookups := 0
+	for _, call := range mock.GetCalls {
+		if call.Path == "/boards/b1/columns.json" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected the column to be resolved once, got %d lookups", lookups)
+	}
+	if len(mock.PostCalls) != 3 {
+		t.Fatalf("expected 3 moves, got %d", len(mock.PostCalls))
+	}
+	for _, call := range mock.PostCalls {
+		if body := call.Body.(map[string]interface{}); body["column_id"] != "col-1" {
+			t.Errorf("unexpected body %v for %s", body, call.Path)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package commands
 
 import (
+	"encoding/json"
+	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/robzolkos/fizzy-cli/fizzy"
+	"github.com/robzolkos/fizzy-cli/internal/client"
 	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/filter"
 	"github.com/spf13/cobra"
 )
 
@@ -21,133 +28,183 @@ var cardListColumn string
 var cardListTag string
 var cardListIndexedBy string
 var cardListAssignee string
+var cardListWhere string
+var cardListSort string
+var cardListLimit int
 var cardListPage int
 var cardListAll bool
 
 var cardListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List cards",
-	Long:  "Lists cards with optional filters.",
+	Long: `Lists cards with optional filters.
+
+--where filters the cards with an expression, for example
+
+  assignees = [] and created_at > -7d
+  column.name = "In progress" or (golden and not closed)
+  steps.completed = false
+
+Fields are card JSON fields, with dots for nested fields; a comparison with
+a list field holds if it holds for any element. Operators are =, !=, <, <=,
+>, >=, ~ (contains) and !~. Values can be strings, numbers, true, false,
+null, [] (empty list), dates (2024-05-01), times and relative times (-7d,
+-12h, -2w). A field on its own tests that it is set. Combine comparisons
+with and, or, not and parentheses. Expressions using steps fetch each card,
+since lists leave steps out.
+
+--sort orders the cards by fields, e.g. last_active_at:desc,number.
+--where, --sort and --column filters are applied to the fetched cards, so
+they need --all (or --page).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
-		boardID := defaultBoard(cardListBoard)
-		columnFilter := strings.TrimSpace(cardListColumn)
-		indexedByFilter := strings.TrimSpace(cardListIndexedBy)
-		effectiveIndexedBy := indexedByFilter
-
 		client := getClient()
-		path := "/cards.json"
-
-		var params []string
-		if boardID != "" {
-			params = append(params, "board_ids[]="+boardID)
-		}
-
-		clientSideColumnFilter := ""
-		clientSideTriage := false
-		if columnFilter != "" {
-			if pseudo, ok := parsePseudoColumnID(columnFilter); ok {
-				switch pseudo.Kind {
-				case "not_now":
-					if effectiveIndexedBy != "" && effectiveIndexedBy != "not_now" {
-						exitWithError(errors.NewInvalidArgsError("cannot combine --indexed-by with --column maybe"))
-					}
-					effectiveIndexedBy = "not_now"
-				case "closed":
-					if effectiveIndexedBy != "" && effectiveIndexedBy != "closed" {
-						exitWithError(errors.NewInvalidArgsError("cannot combine --indexed-by with --column done"))
-					}
-					effectiveIndexedBy = "closed"
-				case "triage":
-					if effectiveIndexedBy != "" {
-						exitWithError(errors.NewInvalidArgsError("cannot combine --indexed-by with --column not-yet"))
-					}
-					clientSideTriage = true
-				default:
-					clientSideColumnFilter = columnFilter
-				}
-			} else {
-				if effectiveIndexedBy != "" {
-					exitWithError(errors.NewInvalidArgsError("cannot combine --indexed-by with --column"))
-				}
-				clientSideColumnFilter = columnFilter
+		query, err := buildCardQuery(client, cardFilterFlags{
+			board:     cardListBoard,
+			column:    cardListColumn,
+			indexedBy: cardListIndexedBy,
+			tag:       cardListTag,
+			assignee:  cardListAssignee,
+			where:     cardListWhere,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+		var sortKeys []filter.SortKey
+		if cardListSort != "" {
+			if sortKeys, err = filter.ParseSort(cardListSort); err != nil {
+				exitWithError(errors.NewInvalidArgsError("Invalid --sort: " + err.Error()))
 			}
 		}
-
-		if effectiveIndexedBy != "" {
-			params = append(params, "indexed_by="+effectiveIndexedBy)
+		if cardListLimit < 0 {
+			exitWithError(errors.NewInvalidArgsError("--limit must not be negative"))
 		}
 
-		if cardListTag != "" {
-			params = append(params, "tag_ids[]="+cardListTag)
-		}
-		if cardListAssignee != "" {
-			params = append(params, "assignee_ids[]="+cardListAssignee)
-		}
+		params := query.params
 		if cardListPage > 0 {
 			params = append(params, "page="+strconv.Itoa(cardListPage))
 		}
-		if len(params) > 0 {
-			path += "?" + strings.Join(params, "&")
-		}
 
-		if (clientSideTriage || clientSideColumnFilter != "") && !cardListAll && cardListPage == 0 {
-			exitWithError(errors.NewInvalidArgsError("Filtering by column requires --all (or --page) because it is applied client-side"))
+		if !cardListAll && cardListPage == 0 {
+			switch {
+			case query.triage || query.columnID != "":
+				exitWithError(errors.NewInvalidArgsError("Filtering by column requires --all (or --page) because it is applied client-side"))
+			case query.where != nil:
+				exitWithError(errors.NewInvalidArgsError("--where requires --all (or --page) because it is applied client-side"))
+			case sortKeys != nil:
+				exitWithError(errors.NewInvalidArgsError("--sort requires --all (or --page) because it is applied client-side"))
+			}
 		}
 
-		resp, err := client.GetWithPagination(path, cardListAll)
+		resp, err := client.GetWithPagination(cardsPath(params), cardListAll)
 		if err != nil {
 			exitWithError(err)
 		}
 
-		if clientSideTriage || clientSideColumnFilter != "" {
+		if query.clientSide() || sortKeys != nil || cardListLimit > 0 {
 			arr, ok := resp.Data.([]interface{})
 			if !ok {
 				exitWithError(errors.NewError("Unexpected cards list response"))
 			}
+			if arr, err = query.filter(client, arr); err != nil {
+				exitWithError(err)
+			}
+			filter.Sort(arr, sortKeys)
+			if cardListLimit > 0 && len(arr) > cardListLimit {
+				arr = arr[:cardListLimit]
+			}
+			resp.Data = arr
+		}
 
-			filtered := make([]interface{}, 0, len(arr))
-			for _, item := range arr {
-				card, ok := item.(map[string]interface{})
-				if !ok {
-					continue
-				}
+		hasNext := resp.LinkNext != ""
+		printSuccessWithPagination(resp.Data, hasNext, resp.LinkNext)
+	},
+}
 
-				columnID := ""
-				if v, ok := card["column_id"].(string); ok {
-					columnID = v
-				}
-				if columnID == "" {
-					if col, ok := card["column"].(map[string]interface{}); ok {
-						if id, ok := col["id"].(string); ok {
-							columnID = id
-						}
-					}
-				}
+// Card search flags
+var cardSearchBoard string
+var cardSearchColumn string
+var cardSearchTag string
+var cardSearchIndexedBy string
+var cardSearchAssignee string
+var cardSearchLimit int
+var cardSearchAll bool
+var cardSearchLocal bool
+var cardSearchConcurrency int
+
+var cardSearchCmd = &cobra.Command{
+	Use:   "search QUERY",
+	Short: "Search cards",
+	Long: `Searches cards for words in their title and description, best matches first.
+
+Cards must contain every word; wrap words in double quotes to search for a
+phrase. Results include a "search" object with a score and highlighted
+snippets of the text that matched.
+
+By default the API's card search finds the cards, and those that don't
+contain every word in their title or description are checked against their
+steps and comments and dropped if they don't match. With --local, cards are
+listed and matched by the CLI instead, which also searches steps and
+comments and works with servers that don't support search, at the cost of
+extra requests for each card.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
 
-				if clientSideTriage {
-					if columnID == "" {
-						filtered = append(filtered, item)
-					}
-					continue
-				}
+		terms := searchTerms(strings.Join(args, " "))
+		if len(terms) == 0 {
+			exitWithError(errors.NewInvalidArgsError("Search query is empty"))
+		}
+		if cardSearchLimit < 0 {
+			exitWithError(errors.NewInvalidArgsError("--limit must not be negative"))
+		}
+		if cardSearchConcurrency < 1 {
+			exitWithError(errors.NewInvalidArgsError("--concurrency must be at least 1"))
+		}
 
-				if clientSideColumnFilter != "" && columnID == clientSideColumnFilter {
-					filtered = append(filtered, item)
-				}
+		client := getClient()
+		query, err := buildCardQuery(client, cardFilterFlags{
+			board:     cardSearchBoard,
+			column:    cardSearchColumn,
+			indexedBy: cardSearchIndexedBy,
+			tag:       cardSearchTag,
+			assignee:  cardSearchAssignee,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+
+		params := query.params
+		if !cardSearchLocal {
+			for _, term := range terms {
+				params = append(params, "terms[]="+url.QueryEscape(term))
 			}
+		}
+		limit := cardSearchLimit
+		if cardSearchAll {
+			limit = 0
+		}
 
-			resp.Data = filtered
+		results, more, err := searchCards(client, cardsPath(params), query, terms, cardSearchLocal, limit, cardSearchConcurrency)
+		if err != nil {
+			exitWithError(err)
 		}
 
-		hasNext := resp.LinkNext != ""
-		printSuccessWithPagination(resp.Data, hasNext, resp.LinkNext)
+		// Search results have their own table columns
+		currentResource = "card_search"
+		printSuccessWithMore(results, more, "use --all or a higher --limit")
 	},
 }
 
+// Card show flags
+var cardShowPlain bool
+var cardShowMarkdown bool
+
 var cardShowCmd = &cobra.Command{
 	Use:   "show CARD_NUMBER",
 	Short: "Show a card",
@@ -157,6 +214,9 @@ var cardShowCmd = &cobra.Command{
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
+		if err := setRichTextMode(cardShowPlain, cardShowMarkdown); err != nil {
+			exitWithError(err)
+		}
 
 		client := getClient()
 		resp, err := client.Get("/cards/" + args[0] + ".json")
@@ -175,6 +235,8 @@ var cardCreateDescription string
 var cardCreateDescriptionFile string
 var cardCreateTagIDs string
 var cardCreateImage string
+var cardCreateFormat string
+var cardCreateAttach []string
 var cardCreateCreatedAt string
 
 var cardCreateCmd = &cobra.Command{
@@ -199,21 +261,34 @@ var cardCreateCmd = &cobra.Command{
 		}
 
 		// Handle description
-		if cardCreateDescriptionFile != "" {
-			content, err := os.ReadFile(cardCreateDescriptionFile)
+		description, ok, err := readRichText(cardCreateDescription, cardCreateDescriptionFile, cardCreateFormat)
+		if err != nil {
+			exitWithError(err)
+		}
+		if ok {
+			cardParams["description"] = description
+		}
+		if len(cardCreateAttach) > 0 {
+			attachments, err := uploadAttachments(getClient(), cardCreateAttach)
 			if err != nil {
 				exitWithError(err)
 			}
-			cardParams["description"] = string(content)
-		} else if cardCreateDescription != "" {
-			cardParams["description"] = cardCreateDescription
+			cardParams["description"] = withAttachments(description, attachments)
 		}
 
 		if cardCreateTagIDs != "" {
-			cardParams["tag_ids"] = cardCreateTagIDs
+			tagIDs, err := resolveTags(getClient(), cardCreateTagIDs)
+			if err != nil {
+				exitWithError(err)
+			}
+			cardParams["tag_ids"] = tagIDs
 		}
 		if cardCreateImage != "" {
-			cardParams["image"] = cardCreateImage
+			image, err := resolveImage(getClient(), cardCreateImage)
+			if err != nil {
+				exitWithError(err)
+			}
+			cardParams["image"] = image
 		}
 		if cardCreateCreatedAt != "" {
 			cardParams["created_at"] = cardCreateCreatedAt
@@ -250,6 +325,8 @@ var cardUpdateTitle string
 var cardUpdateDescription string
 var cardUpdateDescriptionFile string
 var cardUpdateImage string
+var cardUpdateFormat string
+var cardUpdateAttach []string
 var cardUpdateCreatedAt string
 
 var cardUpdateCmd = &cobra.Command{
@@ -267,17 +344,36 @@ var cardUpdateCmd = &cobra.Command{
 		if cardUpdateTitle != "" {
 			cardParams["title"] = cardUpdateTitle
 		}
-		if cardUpdateDescriptionFile != "" {
-			content, err := os.ReadFile(cardUpdateDescriptionFile)
+		description, ok, err := readRichText(cardUpdateDescription, cardUpdateDescriptionFile, cardUpdateFormat)
+		if err != nil {
+			exitWithError(err)
+		}
+		if ok {
+			cardParams["description"] = description
+		}
+		if len(cardUpdateAttach) > 0 {
+			if !ok {
+				// Attach to the current description
+				resp, err := getClient().Get("/cards/" + args[0] + ".json")
+				if err != nil {
+					exitWithError(err)
+				}
+				if card, ok := resp.Data.(map[string]interface{}); ok {
+					description = richTextHTML(card["description_html"])
+				}
+			}
+			attachments, err := uploadAttachments(getClient(), cardUpdateAttach)
 			if err != nil {
 				exitWithError(err)
 			}
-			cardParams["description"] = string(content)
-		} else if cardUpdateDescription != "" {
-			cardParams["description"] = cardUpdateDescription
+			cardParams["description"] = withAttachments(description, attachments)
 		}
 		if cardUpdateImage != "" {
-			cardParams["image"] = cardUpdateImage
+			image, err := resolveImage(getClient(), cardUpdateImage)
+			if err != nil {
+				exitWithError(err)
+			}
+			cardParams["image"] = image
 		}
 		if cardUpdateCreatedAt != "" {
 			cardParams["created_at"] = cardUpdateCreatedAt
@@ -297,97 +393,172 @@ var cardUpdateCmd = &cobra.Command{
 	},
 }
 
-var cardDeleteCmd = &cobra.Command{
-	Use:   "delete CARD_NUMBER",
-	Short: "Delete a card",
-	Long:  "Deletes a card.",
-	Args:  cobra.ExactArgs(1),
+var cardEditCmd = &cobra.Command{
+	Use:   "edit CARD_NUMBER",
+	Short: "Edit a card in your editor",
+	Long: `Opens a card's title, tags and description in $VISUAL or $EDITOR.
+
+The description is edited as Markdown below a YAML front matter header. Only
+the fields you change are saved. If the card is changed on the server while
+the editor is open, nothing is saved and the edited file is kept.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		_, err := client.Delete("/cards/" + args[0] + ".json")
+		path := "/cards/" + args[0] + ".json"
+		resp, err := client.Get(path)
+		if err != nil {
+			exitWithError(err)
+		}
+		card, _ := resp.Data.(map[string]interface{})
+		original := editableCardOf(card)
+
+		doc, err := formatCardDocument(original.meta, original.description)
+		if err != nil {
+			exitWithError(err)
+		}
+		edited, file, err := editText("fizzy-card-"+args[0]+"-*.md", doc)
 		if err != nil {
 			exitWithError(err)
 		}
 
-		printSuccess(map[string]interface{}{
-			"deleted": true,
+		meta, description, err := parseCardDocument(edited)
+		if err != nil {
+			exitWithError(keepEdit(err, file))
+		}
+		if meta.Title == "" {
+			exitWithError(keepEdit(errors.NewInvalidArgsError("Title cannot be empty"), file))
+		}
+
+		cardParams := make(map[string]interface{})
+		if meta.Title != original.meta.Title {
+			cardParams["title"] = meta.Title
+		}
+		if description != original.description {
+			html, err := markdownToHTML(description, ".")
+			if err != nil {
+				exitWithError(keepEdit(err, file))
+			}
+			cardParams["description"] = html
+		}
+		tags := toggledTags(original.meta.Tags, meta.Tags)
+		if len(cardParams) == 0 && len(tags) == 0 {
+			os.Remove(file)
+			printSuccess(card)
+			return
+		}
+
+		// Don't overwrite changes made while the editor was open
+		current, err := client.Get(path)
+		if err != nil {
+			exitWithError(keepEdit(err, file))
+		}
+		currentCard, _ := current.Data.(map[string]interface{})
+		if !reflect.DeepEqual(editableCardOf(currentCard), original) {
+			exitWithError(keepEdit(errors.NewConflictError("Card "+args[0]+" was changed while you were editing it"), file))
+		}
+
+		if len(cardParams) > 0 {
+			if _, err := client.Patch(path, map[string]interface{}{"card": cardParams}); err != nil {
+				exitWithError(keepEdit(err, file))
+			}
+		}
+		for _, tag := range tags {
+			if _, err := client.Post("/cards/"+args[0]+"/taggings.json", map[string]interface{}{"tag_title": tag}); err != nil {
+				exitWithError(keepEdit(err, file))
+			}
+		}
+		os.Remove(file)
+
+		resp, err = client.Get(path)
+		if err != nil {
+			exitWithError(err)
+		}
+		printSuccess(resp.Data)
+	},
+}
+
+var cardDeleteCmd = &cobra.Command{
+	Use:   "delete [CARD_NUMBER...]",
+	Short: "Delete a card",
+	Long:  "Deletes a card.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+
+		client := getClient()
+		runDestructiveCardAction(client, args, "delete", func(number string) (interface{}, error) {
+			if _, err := client.Delete("/cards/" + number + ".json"); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"deleted": true,
+			}, nil
 		})
 	},
 }
 
 var cardCloseCmd = &cobra.Command{
-	Use:   "close CARD_NUMBER",
+	Use:   "close [CARD_NUMBER...]",
 	Short: "Close a card",
 	Long:  "Closes a card.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Post("/cards/"+args[0]+"/closure.json", nil)
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Post("/cards/"+number+"/closure.json", nil)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
 var cardReopenCmd = &cobra.Command{
-	Use:   "reopen CARD_NUMBER",
+	Use:   "reopen [CARD_NUMBER...]",
 	Short: "Reopen a card",
 	Long:  "Reopens a closed card.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Delete("/cards/" + args[0] + "/closure.json")
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Delete("/cards/" + number + "/closure.json")
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
 var cardPostponeCmd = &cobra.Command{
-	Use:   "postpone CARD_NUMBER",
+	Use:   "postpone [CARD_NUMBER...]",
 	Short: "Postpone a card",
 	Long:  "Moves a card to 'Not Now'.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Post("/cards/"+args[0]+"/not_now.json", nil)
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Post("/cards/"+number+"/not_now.json", nil)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
@@ -395,10 +566,9 @@ var cardPostponeCmd = &cobra.Command{
 var cardColumnColumn string
 
 var cardColumnCmd = &cobra.Command{
-	Use:   "column CARD_NUMBER",
+	Use:   "column [CARD_NUMBER...]",
 	Short: "Move card to column",
 	Long:  "Moves a card to a specific column.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
@@ -409,84 +579,241 @@ var cardColumnCmd = &cobra.Command{
 		}
 
 		client := getClient()
-		if pseudo, ok := parsePseudoColumnID(cardColumnColumn); ok {
-			switch pseudo.Kind {
-			case "triage":
-				resp, err := client.Delete("/cards/" + args[0] + "/triage.json")
-				if err != nil {
-					exitWithError(err)
-				}
-				if resp != nil && resp.Data != nil {
-					printSuccess(resp.Data)
-				} else {
-					printSuccess(map[string]interface{}{})
+		// Column names are resolved once per board
+		var mu sync.Mutex
+		columnIDs := make(map[string]string)
+		resolve := func(number string) (string, error) {
+			if _, ok := parsePseudoColumnID(cardColumnColumn); ok || looksLikeID(cardColumnColumn) {
+				return cardColumnColumn, nil
+			}
+			boardID, err := cardBoardID(client, number)
+			if err != nil {
+				return "", err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if columnIDs[boardID] == "" {
+				if columnIDs[boardID], err = resolveColumn(client, boardID, cardColumnColumn); err != nil {
+					return "", err
 				}
-				return
+			}
+			return columnIDs[boardID], nil
+		}
+
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			columnID, err := resolve(number)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := moveCardToColumn(client, number, columnID)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
+	},
+}
+
+// cardFilterFlags are the card list filters as given on the command line.
+type cardFilterFlags struct {
+	board     string
+	column    string
+	indexedBy string
+	tag       string
+	assignee  string
+	where     string
+}
+
+// cardQuery is a resolved set of card list filters: query parameters for
+// /cards.json plus the column filters the API can't apply.
+type cardQuery struct {
+	params []string
+	// Only cards awaiting triage (client-side)
+	triage bool
+	// Only cards in this column (client-side)
+	columnID string
+	// Only cards matching this expression (client-side)
+	where *filter.Expr
+}
+
+// buildCardQuery resolves board, column, tag and assignee names and checks
+// that the filters can be combined.
+func buildCardQuery(c client.API, f cardFilterFlags) (cardQuery, error) {
+	var q cardQuery
+	boardID, err := resolveBoard(c, defaultBoard(f.board))
+	if err != nil {
+		return q, err
+	}
+	columnFilter := strings.TrimSpace(f.column)
+	effectiveIndexedBy := strings.TrimSpace(f.indexedBy)
+
+	if boardID != "" {
+		q.params = append(q.params, "board_ids[]="+boardID)
+	}
+
+	if columnFilter != "" {
+		if pseudo, ok := parsePseudoColumnID(columnFilter); ok {
+			switch pseudo.Kind {
 			case "not_now":
-				resp, err := client.Post("/cards/"+args[0]+"/not_now.json", nil)
-				if err != nil {
-					exitWithError(err)
+				if effectiveIndexedBy != "" && effectiveIndexedBy != "not_now" {
+					return q, errors.NewInvalidArgsError("cannot combine --indexed-by with --column maybe")
 				}
-				if resp != nil && resp.Data != nil {
-					printSuccess(resp.Data)
-				} else {
-					printSuccess(map[string]interface{}{})
-				}
-				return
+				effectiveIndexedBy = "not_now"
 			case "closed":
-				resp, err := client.Post("/cards/"+args[0]+"/closure.json", nil)
-				if err != nil {
-					exitWithError(err)
+				if effectiveIndexedBy != "" && effectiveIndexedBy != "closed" {
+					return q, errors.NewInvalidArgsError("cannot combine --indexed-by with --column done")
 				}
-				if resp != nil && resp.Data != nil {
-					printSuccess(resp.Data)
-				} else {
-					printSuccess(map[string]interface{}{})
+				effectiveIndexedBy = "closed"
+			case "triage":
+				if effectiveIndexedBy != "" {
+					return q, errors.NewInvalidArgsError("cannot combine --indexed-by with --column not-yet")
 				}
-				return
+				q.triage = true
+			default:
+				q.columnID = columnFilter
+			}
+		} else {
+			if effectiveIndexedBy != "" {
+				return q, errors.NewInvalidArgsError("cannot combine --indexed-by with --column")
+			}
+			columnID, err := resolveColumn(c, boardID, columnFilter)
+			if err != nil {
+				return q, err
 			}
+			q.columnID = columnID
 		}
+	}
 
-		body := map[string]interface{}{
-			"column_id": cardColumnColumn,
-		}
+	if effectiveIndexedBy != "" {
+		q.params = append(q.params, "indexed_by="+effectiveIndexedBy)
+	}
 
-		resp, err := client.Post("/cards/"+args[0]+"/triage.json", body)
+	if f.tag != "" {
+		tagID, err := resolveTag(c, f.tag)
 		if err != nil {
-			exitWithError(err)
+			return q, err
 		}
+		q.params = append(q.params, "tag_ids[]="+tagID)
+	}
+	if f.assignee != "" {
+		assigneeID, err := resolveUser(c, f.assignee)
+		if err != nil {
+			return q, err
+		}
+		q.params = append(q.params, "assignee_ids[]="+assigneeID)
+	}
+	if f.where != "" {
+		if q.where, err = filter.Parse(f.where); err != nil {
+			return q, errors.NewInvalidArgsError("Invalid --where expression: " + err.Error())
+		}
+	}
+	return q, nil
+}
 
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
+// clientSide reports whether some cards have to be filtered out after
+// they are fetched.
+func (q cardQuery) clientSide() bool {
+	return q.triage || q.columnID != "" || q.where != nil
+}
+
+// keeps reports whether a fetched card passes the client-side filters.
+func (q cardQuery) keeps(item interface{}) bool {
+	if !q.clientSide() {
+		return true
+	}
+	card, ok := decodeCard(item)
+	if !ok {
+		return false
+	}
+	switch {
+	case q.triage && card.ColumnID() != "":
+		return false
+	case q.columnID != "" && card.ColumnID() != q.columnID:
+		return false
+	}
+	return q.where == nil || q.where.Match(item)
+}
+
+// filter applies the client-side filters to a page of cards. Cards are
+// fetched one by one when the --where expression needs their steps.
+func (q cardQuery) filter(c client.API, items []interface{}) ([]interface{}, error) {
+	withSteps := q.where != nil && q.where.Uses("steps")
+	filtered := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if card, ok := item.(map[string]interface{}); ok && withSteps && card["steps"] == nil {
+			resp, err := c.Get("/cards/" + cardNumber(card) + ".json")
+			if err != nil {
+				return nil, err
+			}
+			item = resp.Data
 		}
-	},
+		if q.keeps(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+func cardsPath(params []string) string {
+	if len(params) == 0 {
+		return "/cards.json"
+	}
+	return "/cards.json?" + strings.Join(params, "&")
+}
+
+// decodeCard converts a card from an untyped API response into its typed
+// form. It reports false if the item is not a card.
+func decodeCard(item interface{}) (fizzy.Card, bool) {
+	var card fizzy.Card
+	if _, ok := item.(map[string]interface{}); !ok {
+		return card, false
+	}
+	data, err := json.Marshal(item)
+	if err != nil || json.Unmarshal(data, &card) != nil {
+		return card, false
+	}
+	return card, true
+}
+
+// moveCardToColumn moves a card into a column ID or pseudo column (maybe,
+// not-now, done) using the triage, not_now and closure endpoints.
+func moveCardToColumn(c client.API, cardNumber, column string) (*client.APIResponse, error) {
+	if pseudo, ok := parsePseudoColumnID(column); ok {
+		switch pseudo.Kind {
+		case "triage":
+			return c.Delete("/cards/" + cardNumber + "/triage.json")
+		case "not_now":
+			return c.Post("/cards/"+cardNumber+"/not_now.json", nil)
+		case "closed":
+			return c.Post("/cards/"+cardNumber+"/closure.json", nil)
+		}
+	}
+
+	body := map[string]interface{}{
+		"column_id": column,
+	}
+	return c.Post("/cards/"+cardNumber+"/triage.json", body)
 }
 
 var cardUntriageCmd = &cobra.Command{
-	Use:   "untriage CARD_NUMBER",
+	Use:   "untriage [CARD_NUMBER...]",
 	Short: "Send card back to triage",
 	Long:  "Removes a card from its column and sends it back to triage.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Delete("/cards/" + args[0] + "/triage.json")
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Delete("/cards/" + number + "/triage.json")
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{
 				"untriaged": true,
-			})
-		}
+			}), nil
+		})
 	},
 }
 
@@ -494,10 +821,9 @@ var cardUntriageCmd = &cobra.Command{
 var cardAssignUser string
 
 var cardAssignCmd = &cobra.Command{
-	Use:   "assign CARD_NUMBER",
+	Use:   "assign [CARD_NUMBER...]",
 	Short: "Toggle assignment on a card",
 	Long:  "Toggles a user's assignment on a card.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
@@ -507,21 +833,23 @@ var cardAssignCmd = &cobra.Command{
 			exitWithError(newRequiredFlagError("user"))
 		}
 
-		body := map[string]interface{}{
-			"assignee_id": cardAssignUser,
-		}
-
 		client := getClient()
-		resp, err := client.Post("/cards/"+args[0]+"/assignments.json", body)
+		userID, err := resolveUser(client, cardAssignUser)
 		if err != nil {
 			exitWithError(err)
 		}
 
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
+		body := map[string]interface{}{
+			"assignee_id": userID,
 		}
+
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Post("/cards/"+number+"/assignments.json", body)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
@@ -529,10 +857,9 @@ var cardAssignCmd = &cobra.Command{
 var cardTagTag string
 
 var cardTagCmd = &cobra.Command{
-	Use:   "tag CARD_NUMBER",
+	Use:   "tag [CARD_NUMBER...]",
 	Short: "Toggle tag on a card",
 	Long:  "Toggles a tag on a card. Creates the tag if it doesn't exist.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
@@ -547,64 +874,53 @@ var cardTagCmd = &cobra.Command{
 		}
 
 		client := getClient()
-		resp, err := client.Post("/cards/"+args[0]+"/taggings.json", body)
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Post("/cards/"+number+"/taggings.json", body)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
 var cardWatchCmd = &cobra.Command{
-	Use:   "watch CARD_NUMBER",
+	Use:   "watch [CARD_NUMBER...]",
 	Short: "Watch a card",
 	Long:  "Subscribes to notifications for a card.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Post("/cards/"+args[0]+"/watch.json", nil)
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Post("/cards/"+number+"/watch.json", nil)
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
 var cardUnwatchCmd = &cobra.Command{
-	Use:   "unwatch CARD_NUMBER",
+	Use:   "unwatch [CARD_NUMBER...]",
 	Short: "Unwatch a card",
 	Long:  "Unsubscribes from notifications for a card.",
-	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
-		resp, err := client.Delete("/cards/" + args[0] + "/watch.json")
-		if err != nil {
-			exitWithError(err)
-		}
-
-		if resp.Data != nil {
-			printSuccess(resp.Data)
-		} else {
-			printSuccess(map[string]interface{}{})
-		}
+		runCardAction(client, args, func(number string) (interface{}, error) {
+			resp, err := client.Delete("/cards/" + number + "/watch.json")
+			if err != nil {
+				return nil, err
+			}
+			return actionData(resp, map[string]interface{}{}), nil
+		})
 	},
 }
 
@@ -612,27 +928,46 @@ func init() {
 	rootCmd.AddCommand(cardCmd)
 
 	// List
-	cardListCmd.Flags().StringVar(&cardListBoard, "board", "", "Filter by board ID")
-	cardListCmd.Flags().StringVar(&cardListColumn, "column", "", "Filter by column ID or pseudo column (not-yet, maybe, done)")
-	cardListCmd.Flags().StringVar(&cardListTag, "tag", "", "Filter by tag ID")
+	cardListCmd.Flags().StringVar(&cardListBoard, "board", "", "Filter by board ID or name")
+	cardListCmd.Flags().StringVar(&cardListColumn, "column", "", "Filter by column ID, name, or pseudo column (not-yet, maybe, done)")
+	cardListCmd.Flags().StringVar(&cardListTag, "tag", "", "Filter by tag ID or title")
 	cardListCmd.Flags().StringVar(&cardListIndexedBy, "indexed-by", "", "Filter by lane/index (all, closed, not_now, stalled, postponing_soon, golden)")
 	cardListCmd.Flags().StringVar(&cardListIndexedBy, "status", "", "Alias for --indexed-by")
 	_ = cardListCmd.Flags().MarkDeprecated("status", "use --indexed-by")
-	cardListCmd.Flags().StringVar(&cardListAssignee, "assignee", "", "Filter by assignee ID")
+	cardListCmd.Flags().StringVar(&cardListAssignee, "assignee", "", "Filter by assignee ID, name, email, or 'me'")
+	cardListCmd.Flags().StringVar(&cardListWhere, "where", "", "Filter by an expression, e.g. 'assignees = [] and created_at > -7d'")
+	cardListCmd.Flags().StringVar(&cardListSort, "sort", "", "Sort by fields, e.g. last_active_at:desc")
+	cardListCmd.Flags().IntVar(&cardListLimit, "limit", 0, "Show at most this many cards")
 	cardListCmd.Flags().IntVar(&cardListPage, "page", 0, "Page number")
 	cardListCmd.Flags().BoolVar(&cardListAll, "all", false, "Fetch all pages")
 	cardCmd.AddCommand(cardListCmd)
 
+	// Search
+	cardSearchCmd.Flags().StringVar(&cardSearchBoard, "board", "", "Filter by board ID or name")
+	cardSearchCmd.Flags().StringVar(&cardSearchColumn, "column", "", "Filter by column ID, name, or pseudo column (not-yet, maybe, done)")
+	cardSearchCmd.Flags().StringVar(&cardSearchTag, "tag", "", "Filter by tag ID or title")
+	cardSearchCmd.Flags().StringVar(&cardSearchIndexedBy, "indexed-by", "", "Filter by lane/index (all, closed, not_now, stalled, postponing_soon, golden)")
+	cardSearchCmd.Flags().StringVar(&cardSearchAssignee, "assignee", "", "Filter by assignee ID, name, email, or 'me'")
+	cardSearchCmd.Flags().IntVar(&cardSearchLimit, "limit", 20, "Stop after this many matches (0 for no limit)")
+	cardSearchCmd.Flags().BoolVar(&cardSearchAll, "all", false, "Search every page (same as --limit 0)")
+	cardSearchCmd.Flags().BoolVar(&cardSearchLocal, "local", false, "Match cards in the CLI, including steps and comments, instead of using the API's search")
+	cardSearchCmd.Flags().IntVar(&cardSearchConcurrency, "concurrency", defaultBulkConcurrency, "Number of cards to fetch at once")
+	cardCmd.AddCommand(cardSearchCmd)
+
 	// Show
+	cardShowCmd.Flags().BoolVar(&cardShowPlain, "plain", false, "Show the description as plain text in table output")
+	cardShowCmd.Flags().BoolVar(&cardShowMarkdown, "markdown", false, "Show the description as Markdown (adds description_markdown to JSON)")
 	cardCmd.AddCommand(cardShowCmd)
 
 	// Create
-	cardCreateCmd.Flags().StringVar(&cardCreateBoard, "board", "", "Board ID (required)")
+	cardCreateCmd.Flags().StringVar(&cardCreateBoard, "board", "", "Board ID or name (required)")
 	cardCreateCmd.Flags().StringVar(&cardCreateTitle, "title", "", "Card title (required)")
 	cardCreateCmd.Flags().StringVar(&cardCreateDescription, "description", "", "Card description (HTML)")
 	cardCreateCmd.Flags().StringVar(&cardCreateDescriptionFile, "description_file", "", "Read description from file")
-	cardCreateCmd.Flags().StringVar(&cardCreateTagIDs, "tag-ids", "", "Comma-separated tag IDs")
-	cardCreateCmd.Flags().StringVar(&cardCreateImage, "image", "", "Header image signed ID")
+	cardCreateCmd.Flags().StringVar(&cardCreateFormat, "format", "", "Description format: html or markdown (default: html, or markdown for .md files)")
+	cardCreateCmd.Flags().StringVar(&cardCreateTagIDs, "tag-ids", "", "Comma-separated tag IDs or titles")
+	cardCreateCmd.Flags().StringVar(&cardCreateImage, "image", "", "Header image file or signed ID")
+	cardCreateCmd.Flags().StringArrayVar(&cardCreateAttach, "attach", nil, "File to attach to the description (repeatable)")
 	cardCreateCmd.Flags().StringVar(&cardCreateCreatedAt, "created-at", "", "Custom created_at timestamp")
 	cardCmd.AddCommand(cardCreateCmd)
 
@@ -640,34 +975,50 @@ func init() {
 	cardUpdateCmd.Flags().StringVar(&cardUpdateTitle, "title", "", "Card title")
 	cardUpdateCmd.Flags().StringVar(&cardUpdateDescription, "description", "", "Card description (HTML)")
 	cardUpdateCmd.Flags().StringVar(&cardUpdateDescriptionFile, "description_file", "", "Read description from file")
-	cardUpdateCmd.Flags().StringVar(&cardUpdateImage, "image", "", "Header image signed ID")
+	cardUpdateCmd.Flags().StringVar(&cardUpdateFormat, "format", "", "Description format: html or markdown (default: html, or markdown for .md files)")
+	cardUpdateCmd.Flags().StringVar(&cardUpdateImage, "image", "", "Header image file or signed ID")
+	cardUpdateCmd.Flags().StringArrayVar(&cardUpdateAttach, "attach", nil, "File to attach to the description (repeatable)")
 	cardUpdateCmd.Flags().StringVar(&cardUpdateCreatedAt, "created-at", "", "Custom created_at timestamp")
 	cardCmd.AddCommand(cardUpdateCmd)
 
+	// Edit
+	cardCmd.AddCommand(cardEditCmd)
+
 	// Delete
+	addBulkFlags(cardDeleteCmd)
+	addBulkConfirmFlag(cardDeleteCmd)
 	cardCmd.AddCommand(cardDeleteCmd)
 
 	// Actions
+	addBulkFlags(cardCloseCmd)
 	cardCmd.AddCommand(cardCloseCmd)
+	addBulkFlags(cardReopenCmd)
 	cardCmd.AddCommand(cardReopenCmd)
+	addBulkFlags(cardPostponeCmd)
 	cardCmd.AddCommand(cardPostponeCmd)
 
 	// Column
-	cardColumnCmd.Flags().StringVar(&cardColumnColumn, "column", "", "Column ID (required)")
+	cardColumnCmd.Flags().StringVar(&cardColumnColumn, "column", "", "Column ID or name (required)")
+	addBulkFlags(cardColumnCmd)
 	cardCmd.AddCommand(cardColumnCmd)
 
 	// Untriage
+	addBulkFlags(cardUntriageCmd)
 	cardCmd.AddCommand(cardUntriageCmd)
 
 	// Assign
-	cardAssignCmd.Flags().StringVar(&cardAssignUser, "user", "", "User ID (required)")
+	cardAssignCmd.Flags().StringVar(&cardAssignUser, "user", "", "User ID, name, email, or 'me' (required)")
+	addBulkFlags(cardAssignCmd)
 	cardCmd.AddCommand(cardAssignCmd)
 
 	// Tag
 	cardTagCmd.Flags().StringVar(&cardTagTag, "tag", "", "Tag name (required)")
+	addBulkFlags(cardTagCmd)
 	cardCmd.AddCommand(cardTagCmd)
 
 	// Watch/Unwatch
+	addBulkFlags(cardWatchCmd)
 	cardCmd.AddCommand(cardWatchCmd)
+	addBulkFlags(cardUnwatchCmd)
 	cardCmd.AddCommand(cardUnwatchCmd)
 }
@@ -1,10 +1,16 @@
 package commands
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/internal/client"
 	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/response"
 )
 
 func TestCardList(t *testing.T) {
@@ -195,6 +201,295 @@ func TestCardList(t *testing.T) {
 	})
 }
 
+func TestCardListWhereSortLimit(t *testing.T) {
+	cards := []interface{}{
+		map[string]interface{}{"number": float64(1), "title": "Old", "assignees": []interface{}{}, "last_active_at": "2024-01-01T00:00:00Z"},
+		map[string]interface{}{"number": float64(2), "title": "Taken", "assignees": []interface{}{map[string]interface{}{"name": "Ann"}}, "last_active_at": "2024-03-01T00:00:00Z"},
+		map[string]interface{}{"number": float64(3), "title": "New", "assignees": []interface{}{}, "last_active_at": "2024-02-01T00:00:00Z"},
+		map[string]interface{}{"number": float64(4), "title": "Newer", "assignees": []interface{}{}, "last_active_at": "2024-02-15T00:00:00Z"},
+	}
+	numbers := func(data interface{}) []float64 {
+		var out []float64
+		for _, item := range data.([]interface{}) {
+			out = append(out, item.(map[string]interface{})["number"].(float64))
+		}
+		return out
+	}
+
+	t.Run("filters, sorts and limits the fetched cards", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards)
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardListWhere = "assignees = []"
+		cardListSort = "last_active_at:desc"
+		cardListLimit = 2
+		cardListAll = true
+		RunTestCommand(func() {
+			cardListCmd.Run(cardListCmd, []string{})
+		})
+		cardListWhere = ""
+		cardListSort = ""
+		cardListLimit = 0
+		cardListAll = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if got := numbers(result.Response.Data); !reflect.DeepEqual(got, []float64{4, 3}) {
+			t.Errorf("expected cards [4 3], got %v", got)
+		}
+		if len(mock.GetCalls) != 0 {
+			t.Errorf("expected no card fetches, got %v", mock.GetCalls)
+		}
+	})
+
+	t.Run("fetches cards when filtering on steps", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards[:1]).WithGetData(map[string]interface{}{
+			"number": float64(1),
+			"steps":  []interface{}{map[string]interface{}{"content": "Check", "completed": false}},
+		})
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardListWhere = "steps.completed = false"
+		cardListAll = true
+		RunTestCommand(func() {
+			cardListCmd.Run(cardListCmd, []string{})
+		})
+		cardListWhere = ""
+		cardListAll = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.GetCalls) != 1 || mock.GetCalls[0].Path != "/cards/1.json" {
+			t.Errorf("expected the card to be fetched, got %v", mock.GetCalls)
+		}
+		if got := numbers(result.Response.Data); !reflect.DeepEqual(got, []float64{1}) {
+			t.Errorf("expected card 1, got %v", got)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			where   string
+			sort    string
+			all     bool
+			message string
+		}{
+			{"expression", "title = ", "", true, "Invalid --where expression: expected a value after = but found end of expression at position 9"},
+			{"sort", "", "title:up", true, `Invalid --sort: invalid sort direction "up" (use asc or desc)`},
+			{"where without --all", "golden", "", false, "--where requires --all (or --page) because it is applied client-side"},
+			{"sort without --all", "", "title", false, "--sort requires --all (or --page) because it is applied client-side"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mock := NewMockClient()
+				result := SetTestMode(mock)
+				SetTestConfig("token", "account", "https://api.example.com")
+				defer ResetTestMode()
+
+				cardListWhere = tt.where
+				cardListSort = tt.sort
+				cardListAll = tt.all
+				RunTestCommand(func() {
+					cardListCmd.Run(cardListCmd, []string{})
+				})
+				cardListWhere = ""
+				cardListSort = ""
+				cardListAll = false
+
+				if result.ExitCode != errors.ExitInvalidArgs {
+					t.Fatalf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+				}
+				if msg := result.Response.Error.Message; msg != tt.message {
+					t.Errorf("expected message %q, got %q", tt.message, msg)
+				}
+			})
+		}
+	})
+}
+
+func TestCardSearch(t *testing.T) {
+	cards := []interface{}{
+		map[string]interface{}{"number": float64(1), "title": "Other", "description": "Mentions the login page"},
+		map[string]interface{}{"number": float64(2), "title": "Login page", "description": ""},
+	}
+
+	t.Run("uses the API's search and ranks the results", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards)
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardSearchBoard = "123"
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{"login", "page"})
+		})
+		cardSearchBoard = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		path := mock.GetWithPaginationCalls[0].Path
+		if path != "/cards.json?board_ids[]=123&terms[]=login&terms[]=page" {
+			t.Errorf("unexpected path %q", path)
+		}
+		if len(mock.GetCalls) != 0 {
+			t.Errorf("expected no card fetches, got %v", mock.GetCalls)
+		}
+		data := result.Response.Data.([]interface{})
+		if len(data) != 2 || data[0].(map[string]interface{})["number"] != float64(2) {
+			t.Fatalf("expected card 2 first, got %v", data)
+		}
+		search := data[0].(map[string]interface{})["search"].(map[string]interface{})
+		if search["field"] != "title" || search["snippet"] != "**Login** **page**" {
+			t.Errorf("unexpected search result %v", search)
+		}
+		if result.Response.Resource != "card_search" {
+			t.Errorf("expected card_search resource, got %q", result.Response.Resource)
+		}
+	})
+
+	t.Run("checks API results that don't contain the terms", func(t *testing.T) {
+		listed := append([]interface{}{
+			map[string]interface{}{"number": float64(3), "title": "Sign in", "description": ""},
+			map[string]interface{}{"number": float64(4), "title": "Sign out", "description": ""},
+		}, cards...)
+		mock := NewMockClient().WithListData(listed)
+		mock.GetResponses = map[string]*client.APIResponse{
+			"/cards/3.json": {StatusCode: 200, Data: map[string]interface{}{
+				"steps": []interface{}{map[string]interface{}{"content": "Fix the login page"}},
+			}},
+			"/cards/4.json": {StatusCode: 200, Data: map[string]interface{}{}},
+		}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{"login", "page"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.GetCalls) != 2 {
+			t.Errorf("expected only cards 3 and 4 to be fetched, got %v", mock.GetCalls)
+		}
+		var numbers []interface{}
+		for _, card := range result.Response.Data.([]interface{}) {
+			numbers = append(numbers, card.(map[string]interface{})["number"])
+		}
+		if !reflect.DeepEqual(numbers, []interface{}{float64(2), float64(1), float64(3)}) {
+			t.Errorf("expected cards 2, 1 and 3, got %v", numbers)
+		}
+	})
+
+	t.Run("matches steps locally", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards).WithGetData(map[string]interface{}{
+			"steps": []interface{}{map[string]interface{}{"content": "Check the spinner"}},
+		})
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardSearchLocal = true
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{"login", "spinner"})
+		})
+		cardSearchLocal = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if path := mock.GetWithPaginationCalls[0].Path; path != "/cards.json" {
+			t.Errorf("expected no search terms in %q", path)
+		}
+		if len(mock.GetCalls) != 2 {
+			t.Errorf("expected each card to be fetched, got %v", mock.GetCalls)
+		}
+		data := result.Response.Data.([]interface{})
+		if len(data) != 2 {
+			t.Fatalf("expected 2 cards, got %d", len(data))
+		}
+		matches := data[0].(map[string]interface{})["search"].(map[string]interface{})["matches"].([]map[string]interface{})
+		if len(matches) != 2 || matches[1]["field"] != "step" {
+			t.Errorf("expected a step match, got %v", matches)
+		}
+	})
+
+	t.Run("searches cards that can't be fetched on their title", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards)
+		mock.GetErrors = map[string]error{"/cards/2.json": errors.NewNotFoundError("Card not found")}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardSearchLocal = true
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{"login", "page"})
+		})
+		cardSearchLocal = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		if data := result.Response.Data.([]interface{}); len(data) != 2 {
+			t.Errorf("expected both cards, got %v", data)
+		}
+	})
+
+	t.Run("stops at the limit", func(t *testing.T) {
+		mock := NewMockClient().WithListData(cards)
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardSearchLimit = 1
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{"login"})
+		})
+		cardSearchLimit = 20
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if data := result.Response.Data.([]interface{}); len(data) != 1 {
+			t.Errorf("expected 1 card, got %d", len(data))
+		}
+		if result.Response.Pagination == nil || !result.Response.Pagination.HasNext {
+			t.Error("expected more results to be reported")
+		}
+		var out bytes.Buffer
+		if err := result.Response.Write(&out, response.FormatTable); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(out.String(), "(more results available, use --all or a higher --limit)\n") {
+			t.Errorf("expected a search hint in\n%s", out.String())
+		}
+	})
+
+	t.Run("requires a query", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardSearchCmd.Run(cardSearchCmd, []string{`""`})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
+
 func TestCardShow(t *testing.T) {
 	t.Run("shows card by number", func(t *testing.T) {
 		mock := NewMockClient()
@@ -226,6 +521,57 @@ func TestCardShow(t *testing.T) {
 		}
 	})
 
+	t.Run("shows the description as markdown", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"number":           42,
+				"title":            "Test Card",
+				"description_html": "<div>See <strong>this</strong></div>",
+			},
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		currentResource = "card"
+
+		cardShowMarkdown = true
+		RunTestCommand(func() {
+			cardShowCmd.Run(cardShowCmd, []string{"42"})
+		})
+		cardShowMarkdown = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		var buf bytes.Buffer
+		result.Response.Write(&buf, response.FormatTable)
+		if !strings.HasSuffix(buf.String(), "\n\nSee **this**\n") {
+			t.Errorf("expected the description as markdown, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("rejects --plain with --markdown", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardShowPlain = true
+		cardShowMarkdown = true
+		RunTestCommand(func() {
+			cardShowCmd.Run(cardShowCmd, []string{"42"})
+		})
+		cardShowPlain = false
+		cardShowMarkdown = false
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+
 	t.Run("handles not found", func(t *testing.T) {
 		mock := NewMockClient()
 		mock.GetError = errors.NewNotFoundError("Card not found")
@@ -399,6 +745,132 @@ func TestCardCreate(t *testing.T) {
 	})
 }
 
+func TestCardCreateAttachments(t *testing.T) {
+	t.Run("uploads attachments and image files", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.UploadFileResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"signed_id":       "signed-1",
+				"attachable_sgid": "sgid-1",
+			},
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateDescription = "<p>See attached</p>"
+		cardCreateAttach = []string{"card_test.go"}
+		cardCreateImage = "mock_client_test.go"
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateDescription = ""
+		cardCreateAttach = nil
+		cardCreateImage = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.UploadFileCalls) != 2 {
+			t.Fatalf("expected 2 uploads, got %v", mock.UploadFileCalls)
+		}
+
+		cardParams := mock.PostCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		want := "<p>See attached</p>\n<action-text-attachment sgid=\"sgid-1\"></action-text-attachment>"
+		if cardParams["description"] != want {
+			t.Errorf("expected description %q, got %q", want, cardParams["description"])
+		}
+		if cardParams["image"] != "signed-1" {
+			t.Errorf("expected image 'signed-1', got '%v'", cardParams["image"])
+		}
+	})
+
+	t.Run("passes a signed ID image through", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateImage = "eyJfcmFpbHMiOnt9fQ=="
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateImage = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.UploadFileCalls) != 0 {
+			t.Errorf("expected no uploads, got %v", mock.UploadFileCalls)
+		}
+		cardParams := mock.PostCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		if cardParams["image"] != "eyJfcmFpbHMiOnt9fQ==" {
+			t.Errorf("expected signed ID image, got '%v'", cardParams["image"])
+		}
+	})
+
+	t.Run("fails for a missing image file", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateImage = "./screenshot.pgn"
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateImage = ""
+
+		if result.ExitCode != errors.ExitNotFound {
+			t.Fatalf("expected exit code %d, got %d", errors.ExitNotFound, result.ExitCode)
+		}
+		if !strings.Contains(result.Response.Error.Message, "screenshot.pgn") {
+			t.Errorf("expected the file in the error, got %q", result.Response.Error.Message)
+		}
+		if len(mock.PostCalls) != 0 {
+			t.Errorf("expected no card to be created, got %v", mock.PostCalls)
+		}
+	})
+
+	t.Run("fails for a missing attachment", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateAttach = []string{"/nonexistent/file.png"}
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateAttach = nil
+
+		if result.ExitCode != errors.ExitError {
+			t.Errorf("expected exit code %d, got %d", errors.ExitError, result.ExitCode)
+		}
+		if len(mock.PostCalls) != 0 {
+			t.Error("expected no card to be created")
+		}
+	})
+}
+
 func TestCardUpdate(t *testing.T) {
 	t.Run("updates card title", func(t *testing.T) {
 		mock := NewMockClient()
@@ -429,6 +901,42 @@ func TestCardUpdate(t *testing.T) {
 	})
 }
 
+func TestCardUpdateAttachments(t *testing.T) {
+	mock := NewMockClient()
+	mock.GetResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data: map[string]interface{}{
+			"description_html": `<div class="action-text-content"><p>Existing</p></div>`,
+		},
+	}
+	mock.UploadFileResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data:       map[string]interface{}{"attachable_sgid": "sgid-1"},
+	}
+
+	result := SetTestMode(mock)
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	cardUpdateAttach = []string{"card_test.go"}
+	RunTestCommand(func() {
+		cardUpdateCmd.Run(cardUpdateCmd, []string{"42"})
+	})
+	cardUpdateAttach = nil
+
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+	if mock.GetCalls[0].Path != "/cards/42.json" {
+		t.Errorf("expected current card to be fetched, got '%s'", mock.GetCalls[0].Path)
+	}
+	cardParams := mock.PatchCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+	want := "<p>Existing</p>\n<action-text-attachment sgid=\"sgid-1\"></action-text-attachment>"
+	if cardParams["description"] != want {
+		t.Errorf("expected description %q, got %q", want, cardParams["description"])
+	}
+}
+
 func TestCardDelete(t *testing.T) {
 	t.Run("deletes card", func(t *testing.T) {
 		mock := NewMockClient()
@@ -815,3 +1323,262 @@ func TestCardUnwatch(t *testing.T) {
 		}
 	})
 }
+
+func TestCardCreateMarkdown(t *testing.T) {
+	t.Run("converts a markdown description", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateDescription = "## Steps\n\n- **Open** the app\n- Run `fizzy`"
+		cardCreateFormat = "markdown"
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateDescription = ""
+		cardCreateFormat = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		cardParams := mock.PostCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		want := "<h2>Steps</h2>\n<ul>\n<li><strong>Open</strong> the app</li>\n<li>Run <code>fizzy</code></li>\n</ul>"
+		if cardParams["description"] != want {
+			t.Errorf("expected description %q, got %q", want, cardParams["description"])
+		}
+	})
+
+	t.Run("detects markdown files and uploads local images", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "shot.png"), []byte("png"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(dir, "description.md")
+		content := "See *this*:\n\n![The bug](shot.png)\n\n![Logo](https://example.com/logo.png)"
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		mock := NewMockClient()
+		mock.UploadFileResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data:       map[string]interface{}{"attachable_sgid": "sgid-1"},
+		}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateDescriptionFile = file
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateDescriptionFile = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.UploadFileCalls) != 1 || mock.UploadFileCalls[0] != filepath.Join(dir, "shot.png") {
+			t.Fatalf("expected upload of shot.png, got %v", mock.UploadFileCalls)
+		}
+		cardParams := mock.PostCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		want := "<p>See <em>this</em>:</p>\n" +
+			"<p><action-text-attachment sgid=\"sgid-1\" caption=\"The bug\"></action-text-attachment></p>\n" +
+			"<p><img src=\"https://example.com/logo.png\" alt=\"Logo\"></p>"
+		if cardParams["description"] != want {
+			t.Errorf("expected description %q, got %q", want, cardParams["description"])
+		}
+	})
+
+	t.Run("rejects an unknown format", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		cardCreateBoard = "123"
+		cardCreateTitle = "Test"
+		cardCreateDescription = "text"
+		cardCreateFormat = "rst"
+		RunTestCommand(func() {
+			cardCreateCmd.Run(cardCreateCmd, []string{})
+		})
+		cardCreateBoard = ""
+		cardCreateTitle = ""
+		cardCreateDescription = ""
+		cardCreateFormat = ""
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+		if len(mock.PostCalls) != 0 {
+			t.Errorf("expected no requests, got %d", len(mock.PostCalls))
+		}
+	})
+}
+
+func TestCardEdit(t *testing.T) {
+	card := func(title string) *client.APIResponse {
+		return &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"number":           42,
+				"title":            title,
+				"tags":             []interface{}{"bug"},
+				"description_html": "<div>Hello <strong>world</strong></div>",
+			},
+		}
+	}
+
+	t.Run("saves only the changed fields", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = card("Old title")
+		var opened string
+		stubEditor(t, func(content string) string {
+			opened = content
+			return "---\ntitle: New title\ntags: [bug, ui]\n---\n\nHello **world**\n"
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardEditCmd.Run(cardEditCmd, []string{"42"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		want := "---\ntitle: Old title\ntags: [bug]\n---\n\nHello **world**\n"
+		if opened != want {
+			t.Errorf("expected the editor to open %q, got %q", want, opened)
+		}
+		if len(mock.PatchCalls) != 1 {
+			t.Fatalf("expected 1 patch call, got %d", len(mock.PatchCalls))
+		}
+		cardParams := mock.PatchCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		if !reflect.DeepEqual(cardParams, map[string]interface{}{"title": "New title"}) {
+			t.Errorf("expected only the title to be sent, got %v", cardParams)
+		}
+		if len(mock.PostCalls) != 1 || mock.PostCalls[0].Path != "/cards/42/taggings.json" {
+			t.Fatalf("expected one tagging call, got %v", mock.PostCalls)
+		}
+		if tag := mock.PostCalls[0].Body.(map[string]interface{})["tag_title"]; tag != "ui" {
+			t.Errorf("expected to toggle 'ui', got %v", tag)
+		}
+	})
+
+	t.Run("saves nothing without changes", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = card("Title")
+		stubEditor(t, func(content string) string { return content })
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardEditCmd.Run(cardEditCmd, []string{"42"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.PatchCalls) != 0 || len(mock.PostCalls) != 0 {
+			t.Errorf("expected no changes, got %d patch and %d post calls", len(mock.PatchCalls), len(mock.PostCalls))
+		}
+	})
+
+	t.Run("converts the description from markdown", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = card("Title")
+		stubEditor(t, func(content string) string {
+			return "---\ntitle: Title\ntags: [bug]\n---\n\n# Plan\n\n- one\n"
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardEditCmd.Run(cardEditCmd, []string{"42"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		cardParams := mock.PatchCalls[0].Body.(map[string]interface{})["card"].(map[string]interface{})
+		if cardParams["description"] != "<h1>Plan</h1>\n<ul>\n<li>one</li>\n</ul>" {
+			t.Errorf("unexpected description %q", cardParams["description"])
+		}
+		if _, ok := cardParams["title"]; ok {
+			t.Error("expected the unchanged title not to be sent")
+		}
+	})
+
+	t.Run("refuses to overwrite changes made meanwhile", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = card("Title")
+		stubEditor(t, func(content string) string {
+			// Someone renames the card while the editor is open
+			mock.GetResponse = card("Renamed elsewhere")
+			return strings.Replace(content, "Hello", "Goodbye", 1)
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardEditCmd.Run(cardEditCmd, []string{"42"})
+		})
+
+		if result.Response.Error == nil || result.Response.Error.Code != "CONFLICT" {
+			t.Fatalf("expected a conflict error, got %+v", result.Response.Error)
+		}
+		if len(mock.PatchCalls) != 0 {
+			t.Error("expected nothing to be saved")
+		}
+		file, _ := result.Response.Error.Details.(map[string]interface{})["file"].(string)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("expected the edited file to be kept: %v", err)
+		}
+		os.Remove(file)
+		if !strings.Contains(string(content), "Goodbye") {
+			t.Errorf("expected the kept file to hold the edit, got %q", content)
+		}
+	})
+
+	t.Run("rejects an empty title", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = card("Title")
+		stubEditor(t, func(content string) string {
+			return "---\ntitle: ''\n---\n"
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardEditCmd.Run(cardEditCmd, []string{"42"})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+		if file, ok := result.Response.Error.Details.(map[string]interface{})["file"].(string); ok {
+			os.Remove(file)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"sort"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// Card timeline flags
+var cardTimelinePlain bool
+var cardTimelineMarkdown bool
+
+var cardTimelineCmd = &cobra.Command{
+	Use:   "timeline CARD_NUMBER",
+	Short: "Show a card's activity",
+	Long: `Shows what happened to a card as one time-ordered list of events: its
+creation, comments and reactions, and the moves, closures, reopenings,
+assignments and mentions you were notified about.
+
+The API has no activity feed for cards, so events come from the timestamps
+of the card, its comments and your notifications about it. Things the API
+keeps no time for (the card's column and status, its assignees and tags,
+completed steps, and reactions) are listed with a null "at": reactions
+after their comment, the rest at the end as the card's current state.
+
+Use -o table for a readable timeline with comments shown in full.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+		if err := setRichTextMode(cardTimelinePlain, cardTimelineMarkdown); err != nil {
+			exitWithError(err)
+		}
+
+		events, err := cardTimeline(getClient(), args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+		currentResource = "card_timeline"
+		printSuccess(events)
+	},
+}
+
+// timelineEvent is one entry in a card's timeline.
+type timelineEvent struct {
+	// At is when the event happened, or nil if the API doesn't record it.
+	At             *string     `json:"at"`
+	Type           string      `json:"type"`
+	Actor          string      `json:"actor,omitempty"`
+	Summary        string      `json:"summary"`
+	Body           interface{} `json:"body,omitempty"`
+	CommentID      string      `json:"comment_id,omitempty"`
+	NotificationID string      `json:"notification_id,omitempty"`
+
+	// sortAt orders the event; it's zero for events that go at the end.
+	sortAt time.Time
+}
+
+// notificationEventTypes renames notification kinds for the timeline.
+// Comment and added notifications are left out, since comments are listed
+// directly and the card's creation is its own event.
+var notificationEventTypes = map[string]string{
+	"mention":    "mentioned",
+	"assignment": "assigned",
+	"closed":     "closed",
+	"reopened":   "reopened",
+	"moved":      "moved",
+	"other":      "other",
+}
+
+// cardTimeline collects the events of a card, oldest first.
+func cardTimeline(c client.API, number string) ([]timelineEvent, error) {
+	resp, err := c.Get("/cards/" + number + ".json")
+	if err != nil {
+		return nil, err
+	}
+	card, _ := resp.Data.(map[string]interface{})
+
+	events := []timelineEvent{}
+	createdAt, _ := card["created_at"].(string)
+	if createdAt != "" {
+		title, _ := card["title"].(string)
+		events = append(events, newTimelineEvent(createdAt, "created", userName(card["creator"]), "Created #"+number+" "+title))
+	}
+
+	comments, err := listAll(c, "/cards/"+number+"/comments.json")
+	if err != nil {
+		return nil, err
+	}
+	var commentIDs []string
+	var commentTimes []time.Time
+	for _, item := range comments {
+		comment, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		createdAt, _ := comment["created_at"].(string)
+		id, _ := comment["id"].(string)
+		event := newTimelineEvent(createdAt, "comment", userName(comment["creator"]), "Commented")
+		event.Body = comment["body"]
+		event.CommentID = id
+		events = append(events, event)
+		commentIDs = append(commentIDs, id)
+		commentTimes = append(commentTimes, event.sortAt)
+	}
+
+	if len(commentIDs) > 0 {
+		results, err := runBulk(commentIDs, defaultBulkConcurrency, func(id string) (interface{}, error) {
+			resp, err := c.Get("/cards/" + number + "/comments/" + id + "/reactions.json")
+			if err != nil {
+				return nil, err
+			}
+			return resp.Data, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range results {
+			reactions, _ := result.(map[string]interface{})["data"].([]interface{})
+			for _, item := range reactions {
+				reaction, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				content, _ := reaction["content"].(string)
+				events = append(events, timelineEvent{
+					Type:      "reaction",
+					Actor:     userName(reaction["reacter"]),
+					Summary:   "Reacted " + content,
+					CommentID: commentIDs[i],
+					sortAt:    commentTimes[i],
+				})
+			}
+		}
+	}
+
+	// Notifications come newest first, so none older than the card are read
+	since, _ := time.Parse(time.RFC3339, createdAt)
+	notifications, err := listNotificationsSince(c, since)
+	if err != nil {
+		return nil, err
+	}
+	f := &notificationFilter{card: number}
+	if notifications, err = f.apply(c, notifications); err != nil {
+		return nil, err
+	}
+	for _, item := range notifications {
+		n := item.(map[string]interface{})
+		eventType, ok := notificationEventTypes[notificationKind(n)]
+		if !ok {
+			continue
+		}
+		createdAt, _ := n["created_at"].(string)
+		body, _ := n["body"].(string)
+		event := newTimelineEvent(createdAt, eventType, userName(n["creator"]), body)
+		event.NotificationID, _ = n["id"].(string)
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].sortAt, events[j].sortAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return append(events, cardStateEvents(card)...), nil
+}
+
+// cardStateEvents describes the parts of a card's current state that the
+// API keeps no time for.
+func cardStateEvents(card map[string]interface{}) []timelineEvent {
+	var events []timelineEvent
+	state := func(eventType, summary string) {
+		events = append(events, timelineEvent{Type: eventType, Summary: summary})
+	}
+
+	if column, ok := card["column"].(map[string]interface{}); ok {
+		name, _ := column["name"].(string)
+		state("column", "In column "+name)
+	}
+	if closed, _ := card["closed"].(bool); closed {
+		state("closed", "Closed")
+	} else if postponed, _ := card["postponed"].(bool); postponed {
+		state("postponed", "Postponed to Not Now")
+	}
+	assignees, _ := card["assignees"].([]interface{})
+	for _, user := range assignees {
+		state("assigned", "Assigned to "+userName(user))
+	}
+	tags, _ := card["tags"].([]interface{})
+	for _, tag := range tags {
+		if title, ok := tag.(string); ok {
+			state("tagged", "Tagged "+title)
+		}
+	}
+	steps, _ := card["steps"].([]interface{})
+	for _, item := range steps {
+		step, _ := item.(map[string]interface{})
+		if completed, _ := step["completed"].(bool); completed {
+			content, _ := step["content"].(string)
+			state("step_completed", "Completed step: "+content)
+		}
+	}
+	return events
+}
+
+// newTimelineEvent returns an event at an API timestamp.
+func newTimelineEvent(at, eventType, actor, summary string) timelineEvent {
+	event := timelineEvent{Type: eventType, Actor: actor, Summary: summary}
+	if t, err := time.Parse(time.RFC3339, at); err == nil {
+		event.At = &at
+		event.sortAt = t
+	}
+	return event
+}
+
+// userName returns the name of an embedded user.
+func userName(v interface{}) string {
+	user, _ := v.(map[string]interface{})
+	name, _ := user["name"].(string)
+	return name
+}
+
+func init() {
+	cardTimelineCmd.Flags().BoolVar(&cardTimelinePlain, "plain", false, "Show comments as plain text in table output")
+	cardTimelineCmd.Flags().BoolVar(&cardTimelineMarkdown, "markdown", false, "Show comments as Markdown (adds body.markdown to JSON)")
+	cardCmd.AddCommand(cardTimelineCmd)
+}
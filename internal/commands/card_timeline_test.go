@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/response"
+)
+
+func TestCardTimeline(t *testing.T) {
+	user := func(name string) map[string]interface{} {
+		return map[string]interface{}{"name": name}
+	}
+	newMock := func() *MockClient {
+		mock := NewMockClient()
+		mock.GetResponses = map[string]*client.APIResponse{
+			"/cards/7.json": {StatusCode: 200, Data: map[string]interface{}{
+				"number":     float64(7),
+				"title":      "Fix login",
+				"created_at": "2024-01-01T00:00:00Z",
+				"creator":    user("Ann"),
+				"closed":     true,
+				"column":     map[string]interface{}{"name": "Doing"},
+				"assignees":  []interface{}{user("Bob")},
+				"tags":       []interface{}{"bug"},
+				"steps": []interface{}{
+					map[string]interface{}{"content": "Reproduce", "completed": true},
+					map[string]interface{}{"content": "Fix", "completed": false},
+				},
+			}},
+			"/cards/7/comments/c1/reactions.json": {StatusCode: 200, Data: []interface{}{
+				map[string]interface{}{"content": "👍", "reacter": user("Ann")},
+			}},
+			"/cards/7/comments/c2/reactions.json": {StatusCode: 200, Data: []interface{}{}},
+			"/notifications.json?page=2": {StatusCode: 200, LinkNext: "/notifications.json?page=3", Data: []interface{}{
+				map[string]interface{}{"id": "n-1", "created_at": "2024-01-01T00:00:00Z", "creator": user("Ann"), "body": "Ann added Fix login", "card": map[string]interface{}{"number": float64(7)}},
+				map[string]interface{}{"id": "n-2", "created_at": "2023-12-31T00:00:00Z", "creator": user("Ann"), "body": "Ann added Older", "card": map[string]interface{}{"number": float64(6)}},
+			}},
+		}
+		mock.GetWithPaginationResponses = map[string]*client.APIResponse{
+			"/cards/7/comments.json": {StatusCode: 200, Data: []interface{}{
+				map[string]interface{}{"id": "c2", "created_at": "2024-01-05T00:00:00Z", "creator": user("Bob"), "body": map[string]interface{}{"html": "<p>Done now</p>"}},
+				map[string]interface{}{"id": "c1", "created_at": "2024-01-02T00:00:00Z", "creator": user("Bob"), "body": map[string]interface{}{"html": "<p>On it</p>"}},
+			}},
+			"/notifications.json": {StatusCode: 200, LinkNext: "/notifications.json?page=2", Data: []interface{}{
+				map[string]interface{}{"id": "n3", "created_at": "2024-01-06T00:00:00Z", "creator": user("Bob"), "body": "Bob closed Fix login", "card": map[string]interface{}{"number": float64(7)}},
+				map[string]interface{}{"id": "n2", "created_at": "2024-01-05T00:00:00Z", "creator": user("Bob"), "body": "Bob commented on Fix login", "card": map[string]interface{}{"number": float64(7)}},
+				map[string]interface{}{"id": "n1", "created_at": "2024-01-03T00:00:00Z", "creator": user("Ann"), "body": "Ann moved Fix login to Doing", "card": map[string]interface{}{"number": float64(7)}},
+				map[string]interface{}{"id": "n0", "created_at": "2024-01-04T00:00:00Z", "creator": user("Ann"), "body": "Ann moved Other to Doing", "card": map[string]interface{}{"number": float64(8)}},
+			}},
+		}
+		return mock
+	}
+
+	t.Run("merges events in time order", func(t *testing.T) {
+		mock := newMock()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardTimelineCmd.Run(cardTimelineCmd, []string{"7"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		var got []string
+		for _, event := range result.Response.Data.([]timelineEvent) {
+			at := "-"
+			if event.At != nil {
+				at = (*event.At)[:10]
+			}
+			got = append(got, at+" "+event.Type+" "+event.Summary)
+		}
+		want := []string{
+			"2024-01-01 created Created #7 Fix login",
+			"2024-01-02 comment Commented",
+			"- reaction Reacted 👍",
+			"2024-01-03 moved Ann moved Fix login to Doing",
+			"2024-01-05 comment Commented",
+			"2024-01-06 closed Bob closed Fix login",
+			"- column In column Doing",
+			"- closed Closed",
+			"- assigned Assigned to Bob",
+			"- tagged Tagged bug",
+			"- step_completed Completed step: Reproduce",
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+		for _, call := range mock.GetCalls {
+			if call.Path == "/notifications.json?page=3" {
+				t.Error("expected to stop at notifications older than the card")
+			}
+		}
+	})
+
+	t.Run("renders a readable timeline", func(t *testing.T) {
+		result := SetTestMode(newMock())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			cardTimelineCmd.Run(cardTimelineCmd, []string{"7"})
+		})
+
+		var out bytes.Buffer
+		if err := result.Response.Write(&out, response.FormatTable); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"2024-01-02T00:00:00Z  Bob  Commented\n  On it", "Ann  Reacted 👍", "Completed step: Reproduce"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in\n%s", want, out.String())
+			}
+		}
+	})
+}
@@ -53,7 +53,7 @@ var columnListCmd = &cobra.Command{
 var columnShowBoard string
 
 var columnShowCmd = &cobra.Command{
-	Use:   "show COLUMN_ID",
+	Use:   "show COLUMN",
 	Short: "Show a column",
 	Long:  "Shows details of a specific column.",
 	Args:  cobra.ExactArgs(1),
@@ -73,7 +73,12 @@ var columnShowCmd = &cobra.Command{
 		}
 
 		client := getClient()
-		resp, err := client.Get("/boards/" + boardID + "/columns/" + args[0] + ".json")
+		columnID, err := resolveColumn(client, boardID, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		resp, err := client.Get("/boards/" + boardID + "/columns/" + columnID + ".json")
 		if err != nil {
 			exitWithError(err)
 		}
@@ -142,7 +147,7 @@ var columnUpdateName string
 var columnUpdateColor string
 
 var columnUpdateCmd = &cobra.Command{
-	Use:   "update COLUMN_ID",
+	Use:   "update COLUMN",
 	Short: "Update a column",
 	Long:  "Updates an existing column.",
 	Args:  cobra.ExactArgs(1),
@@ -173,7 +178,12 @@ var columnUpdateCmd = &cobra.Command{
 		}
 
 		client := getClient()
-		resp, err := client.Patch("/boards/"+boardID+"/columns/"+args[0]+".json", body)
+		columnID, err := resolveColumn(client, boardID, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		resp, err := client.Patch("/boards/"+boardID+"/columns/"+columnID+".json", body)
 		if err != nil {
 			exitWithError(err)
 		}
@@ -186,7 +196,7 @@ var columnUpdateCmd = &cobra.Command{
 var columnDeleteBoard string
 
 var columnDeleteCmd = &cobra.Command{
-	Use:   "delete COLUMN_ID",
+	Use:   "delete COLUMN",
 	Short: "Delete a column",
 	Long:  "Deletes a column from a board.",
 	Args:  cobra.ExactArgs(1),
@@ -205,7 +215,12 @@ var columnDeleteCmd = &cobra.Command{
 		}
 
 		client := getClient()
-		_, err = client.Delete("/boards/" + boardID + "/columns/" + args[0] + ".json")
+		columnID, err := resolveColumn(client, boardID, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		_, err = client.Delete("/boards/" + boardID + "/columns/" + columnID + ".json")
 		if err != nil {
 			exitWithError(err)
 		}
@@ -220,26 +235,26 @@ func init() {
 	rootCmd.AddCommand(columnCmd)
 
 	// List
-	columnListCmd.Flags().StringVar(&columnListBoard, "board", "", "Board ID (required)")
+	columnListCmd.Flags().StringVar(&columnListBoard, "board", "", "Board ID or name (required)")
 	columnCmd.AddCommand(columnListCmd)
 
 	// Show
-	columnShowCmd.Flags().StringVar(&columnShowBoard, "board", "", "Board ID (required)")
+	columnShowCmd.Flags().StringVar(&columnShowBoard, "board", "", "Board ID or name (required)")
 	columnCmd.AddCommand(columnShowCmd)
 
 	// Create
-	columnCreateCmd.Flags().StringVar(&columnCreateBoard, "board", "", "Board ID (required)")
+	columnCreateCmd.Flags().StringVar(&columnCreateBoard, "board", "", "Board ID or name (required)")
 	columnCreateCmd.Flags().StringVar(&columnCreateName, "name", "", "Column name (required)")
 	columnCreateCmd.Flags().StringVar(&columnCreateColor, "color", "", "Column color")
 	columnCmd.AddCommand(columnCreateCmd)
 
 	// Update
-	columnUpdateCmd.Flags().StringVar(&columnUpdateBoard, "board", "", "Board ID (required)")
+	columnUpdateCmd.Flags().StringVar(&columnUpdateBoard, "board", "", "Board ID or name (required)")
 	columnUpdateCmd.Flags().StringVar(&columnUpdateName, "name", "", "Column name")
 	columnUpdateCmd.Flags().StringVar(&columnUpdateColor, "color", "", "Column color")
 	columnCmd.AddCommand(columnUpdateCmd)
 
 	// Delete
-	columnDeleteCmd.Flags().StringVar(&columnDeleteBoard, "board", "", "Board ID (required)")
+	columnDeleteCmd.Flags().StringVar(&columnDeleteBoard, "board", "", "Board ID or name (required)")
 	columnCmd.AddCommand(columnDeleteCmd)
 }
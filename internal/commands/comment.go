@@ -2,7 +2,10 @@ package commands
 
 import (
 	"os"
+	"strings"
 
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/richtext"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +19,8 @@ var commentCmd = &cobra.Command{
 var commentListCard string
 var commentListPage int
 var commentListAll bool
+var commentListPlain bool
+var commentListMarkdown bool
 
 var commentListCmd = &cobra.Command{
 	Use:   "list",
@@ -29,6 +34,9 @@ var commentListCmd = &cobra.Command{
 		if commentListCard == "" {
 			exitWithError(newRequiredFlagError("card"))
 		}
+		if err := setRichTextMode(commentListPlain, commentListMarkdown); err != nil {
+			exitWithError(err)
+		}
 
 		client := getClient()
 		path := "/cards/" + commentListCard + "/comments.json"
@@ -48,6 +56,8 @@ var commentListCmd = &cobra.Command{
 
 // Comment show flags
 var commentShowCard string
+var commentShowPlain bool
+var commentShowMarkdown bool
 
 var commentShowCmd = &cobra.Command{
 	Use:   "show COMMENT_ID",
@@ -62,6 +72,9 @@ var commentShowCmd = &cobra.Command{
 		if commentShowCard == "" {
 			exitWithError(newRequiredFlagError("card"))
 		}
+		if err := setRichTextMode(commentShowPlain, commentShowMarkdown); err != nil {
+			exitWithError(err)
+		}
 
 		client := getClient()
 		resp, err := client.Get("/cards/" + commentShowCard + "/comments/" + args[0] + ".json")
@@ -77,6 +90,8 @@ var commentShowCmd = &cobra.Command{
 var commentCreateCard string
 var commentCreateBody string
 var commentCreateBodyFile string
+var commentCreateFormat string
+var commentCreateAttach []string
 var commentCreateCreatedAt string
 
 var commentCreateCmd = &cobra.Command{
@@ -93,17 +108,19 @@ var commentCreateCmd = &cobra.Command{
 		}
 
 		// Determine body content
-		var body string
-		if commentCreateBodyFile != "" {
-			content, err := os.ReadFile(commentCreateBodyFile)
+		body, ok, err := readRichText(commentCreateBody, commentCreateBodyFile, commentCreateFormat)
+		if err != nil {
+			exitWithError(err)
+		}
+		if !ok && len(commentCreateAttach) == 0 {
+			exitWithError(newRequiredFlagError("body or body_file"))
+		}
+		if len(commentCreateAttach) > 0 {
+			attachments, err := uploadAttachments(getClient(), commentCreateAttach)
 			if err != nil {
 				exitWithError(err)
 			}
-			body = string(content)
-		} else if commentCreateBody != "" {
-			body = commentCreateBody
-		} else {
-			exitWithError(newRequiredFlagError("body or body_file"))
+			body = withAttachments(body, attachments)
 		}
 
 		commentParams := map[string]interface{}{
@@ -142,6 +159,8 @@ var commentCreateCmd = &cobra.Command{
 var commentUpdateCard string
 var commentUpdateBody string
 var commentUpdateBodyFile string
+var commentUpdateFormat string
+var commentUpdateAttach []string
 
 var commentUpdateCmd = &cobra.Command{
 	Use:   "update COMMENT_ID",
@@ -159,14 +178,29 @@ var commentUpdateCmd = &cobra.Command{
 
 		commentParams := make(map[string]interface{})
 
-		if commentUpdateBodyFile != "" {
-			content, err := os.ReadFile(commentUpdateBodyFile)
+		body, ok, err := readRichText(commentUpdateBody, commentUpdateBodyFile, commentUpdateFormat)
+		if err != nil {
+			exitWithError(err)
+		}
+		if ok {
+			commentParams["body"] = body
+		}
+		if len(commentUpdateAttach) > 0 {
+			if !ok {
+				// Attach to the current body
+				resp, err := getClient().Get("/cards/" + commentUpdateCard + "/comments/" + args[0] + ".json")
+				if err != nil {
+					exitWithError(err)
+				}
+				if comment, ok := resp.Data.(map[string]interface{}); ok {
+					body = richTextHTML(comment["body"])
+				}
+			}
+			attachments, err := uploadAttachments(getClient(), commentUpdateAttach)
 			if err != nil {
 				exitWithError(err)
 			}
-			commentParams["body"] = string(content)
-		} else if commentUpdateBody != "" {
-			commentParams["body"] = commentUpdateBody
+			commentParams["body"] = withAttachments(body, attachments)
 		}
 
 		reqBody := map[string]interface{}{
@@ -183,6 +217,75 @@ var commentUpdateCmd = &cobra.Command{
 	},
 }
 
+// Comment edit flags
+var commentEditCard string
+
+var commentEditCmd = &cobra.Command{
+	Use:   "edit COMMENT_ID",
+	Short: "Edit a comment in your editor",
+	Long: `Opens a comment's body as Markdown in $VISUAL or $EDITOR and saves it
+when the editor closes. If the comment is changed on the server while the
+editor is open, nothing is saved and the edited file is kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+
+		if commentEditCard == "" {
+			exitWithError(newRequiredFlagError("card"))
+		}
+
+		client := getClient()
+		path := "/cards/" + commentEditCard + "/comments/" + args[0] + ".json"
+		resp, err := client.Get(path)
+		if err != nil {
+			exitWithError(err)
+		}
+		comment, _ := resp.Data.(map[string]interface{})
+		original := richtext.ToMarkdown(richTextHTML(comment["body"]))
+
+		content := original
+		if content != "" {
+			content += "\n"
+		}
+		edited, file, err := editText("fizzy-comment-"+args[0]+"-*.md", content)
+		if err != nil {
+			exitWithError(err)
+		}
+		body := strings.TrimSpace(edited)
+		if body == original {
+			os.Remove(file)
+			printSuccess(comment)
+			return
+		}
+
+		// Don't overwrite changes made while the editor was open
+		current, err := client.Get(path)
+		if err != nil {
+			exitWithError(keepEdit(err, file))
+		}
+		currentComment, _ := current.Data.(map[string]interface{})
+		if richtext.ToMarkdown(richTextHTML(currentComment["body"])) != original {
+			exitWithError(keepEdit(errors.NewConflictError("Comment "+args[0]+" was changed while you were editing it"), file))
+		}
+
+		html, err := markdownToHTML(body, ".")
+		if err != nil {
+			exitWithError(keepEdit(err, file))
+		}
+		resp, err = client.Patch(path, map[string]interface{}{
+			"comment": map[string]interface{}{"body": html},
+		})
+		if err != nil {
+			exitWithError(keepEdit(err, file))
+		}
+		os.Remove(file)
+
+		printSuccess(resp.Data)
+	},
+}
+
 // Comment delete flags
 var commentDeleteCard string
 
@@ -219,16 +322,22 @@ func init() {
 	commentListCmd.Flags().StringVar(&commentListCard, "card", "", "Card number (required)")
 	commentListCmd.Flags().IntVar(&commentListPage, "page", 0, "Page number")
 	commentListCmd.Flags().BoolVar(&commentListAll, "all", false, "Fetch all pages")
+	commentListCmd.Flags().BoolVar(&commentListPlain, "plain", false, "Show bodies as plain text in table output")
+	commentListCmd.Flags().BoolVar(&commentListMarkdown, "markdown", false, "Show bodies as Markdown (adds body.markdown to JSON)")
 	commentCmd.AddCommand(commentListCmd)
 
 	// Show
 	commentShowCmd.Flags().StringVar(&commentShowCard, "card", "", "Card number (required)")
+	commentShowCmd.Flags().BoolVar(&commentShowPlain, "plain", false, "Show the body as plain text in table output")
+	commentShowCmd.Flags().BoolVar(&commentShowMarkdown, "markdown", false, "Show the body as Markdown (adds body.markdown to JSON)")
 	commentCmd.AddCommand(commentShowCmd)
 
 	// Create
 	commentCreateCmd.Flags().StringVar(&commentCreateCard, "card", "", "Card number (required)")
 	commentCreateCmd.Flags().StringVar(&commentCreateBody, "body", "", "Comment body (HTML)")
 	commentCreateCmd.Flags().StringVar(&commentCreateBodyFile, "body_file", "", "Read body from file")
+	commentCreateCmd.Flags().StringVar(&commentCreateFormat, "format", "", "Body format: html or markdown (default: html, or markdown for .md files)")
+	commentCreateCmd.Flags().StringArrayVar(&commentCreateAttach, "attach", nil, "File to attach to the body (repeatable)")
 	commentCreateCmd.Flags().StringVar(&commentCreateCreatedAt, "created-at", "", "Custom created_at timestamp")
 	commentCmd.AddCommand(commentCreateCmd)
 
@@ -236,8 +345,14 @@ func init() {
 	commentUpdateCmd.Flags().StringVar(&commentUpdateCard, "card", "", "Card number (required)")
 	commentUpdateCmd.Flags().StringVar(&commentUpdateBody, "body", "", "Comment body (HTML)")
 	commentUpdateCmd.Flags().StringVar(&commentUpdateBodyFile, "body_file", "", "Read body from file")
+	commentUpdateCmd.Flags().StringVar(&commentUpdateFormat, "format", "", "Body format: html or markdown (default: html, or markdown for .md files)")
+	commentUpdateCmd.Flags().StringArrayVar(&commentUpdateAttach, "attach", nil, "File to attach to the body (repeatable)")
 	commentCmd.AddCommand(commentUpdateCmd)
 
+	// Edit
+	commentEditCmd.Flags().StringVar(&commentEditCard, "card", "", "Card number (required)")
+	commentCmd.AddCommand(commentEditCmd)
+
 	// Delete
 	commentDeleteCmd.Flags().StringVar(&commentDeleteCard, "card", "", "Card number (required)")
 	commentCmd.AddCommand(commentDeleteCmd)
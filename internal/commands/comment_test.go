@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"os"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/internal/client"
@@ -214,6 +215,78 @@ func TestCommentCreate(t *testing.T) {
 	})
 }
 
+func TestCommentAttachments(t *testing.T) {
+	t.Run("creates a comment from attachments alone", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.UploadFileResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data:       map[string]interface{}{"attachable_sgid": "sgid-1"},
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		commentCreateCard = "42"
+		commentCreateAttach = []string{"comment_test.go"}
+		RunTestCommand(func() {
+			commentCreateCmd.Run(commentCreateCmd, []string{})
+		})
+		commentCreateCard = ""
+		commentCreateAttach = nil
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		params := mock.PostCalls[0].Body.(map[string]interface{})["comment"].(map[string]interface{})
+		want := `<action-text-attachment sgid="sgid-1"></action-text-attachment>`
+		if params["body"] != want {
+			t.Errorf("expected body %q, got %q", want, params["body"])
+		}
+	})
+
+	t.Run("appends attachments to the current body on update", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"body": map[string]interface{}{
+					"plain_text": "Existing",
+					"html":       `<div class="action-text-content">Existing</div>`,
+				},
+			},
+		}
+		mock.UploadFileResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data:       map[string]interface{}{"attachable_sgid": "sgid-1"},
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		commentUpdateCard = "42"
+		commentUpdateAttach = []string{"comment_test.go"}
+		RunTestCommand(func() {
+			commentUpdateCmd.Run(commentUpdateCmd, []string{"comment-1"})
+		})
+		commentUpdateCard = ""
+		commentUpdateAttach = nil
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if mock.GetCalls[0].Path != "/cards/42/comments/comment-1.json" {
+			t.Errorf("expected current comment to be fetched, got '%s'", mock.GetCalls[0].Path)
+		}
+		params := mock.PatchCalls[0].Body.(map[string]interface{})["comment"].(map[string]interface{})
+		want := "Existing\n<action-text-attachment sgid=\"sgid-1\"></action-text-attachment>"
+		if params["body"] != want {
+			t.Errorf("expected body %q, got %q", want, params["body"])
+		}
+	})
+}
+
 func TestCommentUpdate(t *testing.T) {
 	t.Run("updates comment body", func(t *testing.T) {
 		mock := NewMockClient()
@@ -304,3 +377,127 @@ func TestCommentDelete(t *testing.T) {
 		}
 	})
 }
+
+func TestCommentMarkdown(t *testing.T) {
+	mock := NewMockClient()
+	result := SetTestMode(mock)
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	commentCreateCard = "42"
+	commentCreateBody = "> Quoted\n\nSee [the docs](https://fizzy.do/docs)"
+	commentCreateFormat = "markdown"
+	RunTestCommand(func() {
+		commentCreateCmd.Run(commentCreateCmd, []string{})
+	})
+	commentCreateCard = ""
+	commentCreateBody = ""
+	commentCreateFormat = ""
+
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+	params := mock.PostCalls[0].Body.(map[string]interface{})["comment"].(map[string]interface{})
+	want := "<blockquote>\n<p>Quoted</p>\n</blockquote>\n<p>See <a href=\"https://fizzy.do/docs\">the docs</a></p>"
+	if params["body"] != want {
+		t.Errorf("expected body %q, got %q", want, params["body"])
+	}
+}
+
+func TestCommentEdit(t *testing.T) {
+	comment := func(html string) *client.APIResponse {
+		return &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"id":   "comment-1",
+				"body": map[string]interface{}{"html": `<div class="action-text-content">` + html + `</div>`},
+			},
+		}
+	}
+
+	t.Run("saves the edited body", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = comment("<div>Looks <em>good</em></div>")
+		var opened string
+		stubEditor(t, func(content string) string {
+			opened = content
+			return "Looks *great*\n"
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		commentEditCard = "42"
+		RunTestCommand(func() {
+			commentEditCmd.Run(commentEditCmd, []string{"comment-1"})
+		})
+		commentEditCard = ""
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if opened != "Looks *good*\n" {
+			t.Errorf("expected the body as markdown, got %q", opened)
+		}
+		if mock.PatchCalls[0].Path != "/cards/42/comments/comment-1.json" {
+			t.Errorf("unexpected path %s", mock.PatchCalls[0].Path)
+		}
+		params := mock.PatchCalls[0].Body.(map[string]interface{})["comment"].(map[string]interface{})
+		if params["body"] != "<p>Looks <em>great</em></p>" {
+			t.Errorf("unexpected body %q", params["body"])
+		}
+	})
+
+	t.Run("refuses to overwrite changes made meanwhile", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = comment("<div>Original</div>")
+		stubEditor(t, func(content string) string {
+			mock.GetResponse = comment("<div>Changed elsewhere</div>")
+			return "Mine"
+		})
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		commentEditCard = "42"
+		RunTestCommand(func() {
+			commentEditCmd.Run(commentEditCmd, []string{"comment-1"})
+		})
+		commentEditCard = ""
+
+		if result.Response.Error == nil || result.Response.Error.Code != "CONFLICT" {
+			t.Fatalf("expected a conflict error, got %+v", result.Response.Error)
+		}
+		if len(mock.PatchCalls) != 0 {
+			t.Error("expected nothing to be saved")
+		}
+		if file, ok := result.Response.Error.Details.(map[string]interface{})["file"].(string); ok {
+			os.Remove(file)
+		}
+	})
+
+	t.Run("aborts on an empty file", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = comment("<div>Original</div>")
+		stubEditor(t, func(content string) string { return "\n" })
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		commentEditCard = "42"
+		RunTestCommand(func() {
+			commentEditCmd.Run(commentEditCmd, []string{"comment-1"})
+		})
+		commentEditCard = ""
+
+		if result.ExitCode != errors.ExitError {
+			t.Errorf("expected exit code %d, got %d", errors.ExitError, result.ExitCode)
+		}
+		if len(mock.PatchCalls) != 0 {
+			t.Error("expected nothing to be saved")
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/richtext"
+	"gopkg.in/yaml.v3"
+)
+
+// editFile opens a file in the user's editor and waits for it to close
+// (can be overridden for testing).
+var editFile = runEditor
+
+// editorCommand returns the editor command line from $VISUAL or $EDITOR,
+// falling back to vi.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+func runEditor(path string) error {
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.NewError("Editor " + args[0] + " failed: " + err.Error())
+	}
+	return nil
+}
+
+// editText writes content to a temporary file named after pattern (see
+// os.CreateTemp), opens it in the editor and returns the edited text and
+// the file's path. The caller removes the file once the edit is saved.
+func editText(pattern, content string) (string, string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", "", err
+	}
+	path := file.Name()
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", "", err
+	}
+
+	if err := editFile(path); err != nil {
+		os.Remove(path)
+		return "", "", err
+	}
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		os.Remove(path)
+		return "", "", err
+	}
+	if strings.TrimSpace(string(edited)) == "" {
+		os.Remove(path)
+		return "", "", errors.NewError("Edit aborted: the file is empty")
+	}
+	return string(edited), path, nil
+}
+
+// cardFrontMatter is the YAML header of a card opened in the editor.
+type cardFrontMatter struct {
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags,flow"`
+}
+
+// formatCardDocument lays out a card for editing: a YAML front matter
+// header followed by the description in Markdown.
+func formatCardDocument(meta cardFrontMatter, description string) (string, error) {
+	if meta.Tags == nil {
+		meta.Tags = []string{}
+	}
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	doc := "---\n" + string(header) + "---\n\n" + description
+	if description != "" {
+		doc += "\n"
+	}
+	return doc, nil
+}
+
+// parseCardDocument splits an edited card into its front matter and
+// description.
+func parseCardDocument(doc string) (cardFrontMatter, string, error) {
+	var meta cardFrontMatter
+	doc = strings.ReplaceAll(doc, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(doc, "---\n")
+	if !ok {
+		return meta, "", errors.NewInvalidArgsError("Missing front matter: the file must start with a --- line")
+	}
+	header, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		if header, ok = strings.CutSuffix(rest, "\n---"); !ok {
+			return meta, "", errors.NewInvalidArgsError("Unterminated front matter: add a --- line after it")
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(header)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&meta); err != nil && strings.TrimSpace(header) != "" {
+		return meta, "", errors.NewInvalidArgsError("Invalid front matter: " + err.Error())
+	}
+	meta.Title = strings.TrimSpace(meta.Title)
+	return meta, strings.TrimSpace(body), nil
+}
+
+// editableCard is the part of a card that `card edit` changes.
+type editableCard struct {
+	meta        cardFrontMatter
+	description string
+}
+
+func editableCardOf(card map[string]interface{}) editableCard {
+	title, _ := card["title"].(string)
+	return editableCard{
+		meta: cardFrontMatter{
+			Title: title,
+			Tags:  stringList(card["tags"]),
+		},
+		description: richtext.ToMarkdown(richTextHTML(card["description_html"])),
+	}
+}
+
+// stringList converts a JSON array of strings.
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// toggledTags returns the tags to toggle to get from one list to another.
+func toggledTags(from, to []string) []string {
+	had := make(map[string]bool, len(from))
+	for _, tag := range from {
+		had[tag] = true
+	}
+	want := make(map[string]bool, len(to))
+	var toggled []string
+	for _, tag := range to {
+		tag = strings.TrimSpace(strings.TrimPrefix(tag, "#"))
+		if tag == "" || want[tag] {
+			continue
+		}
+		want[tag] = true
+		if !had[tag] {
+			toggled = append(toggled, tag)
+		}
+	}
+	for _, tag := range from {
+		if !want[tag] {
+			toggled = append(toggled, tag)
+		}
+	}
+	sort.Strings(toggled)
+	return toggled
+}
+
+// keepEdit points an error at the edited file, which is left in place so
+// the changes aren't lost.
+func keepEdit(err error, path string) error {
+	cliErr, ok := err.(*errors.CLIError)
+	if !ok {
+		cliErr = errors.NewError(err.Error())
+	}
+	cliErr.Message += "; your changes are saved in " + path
+	if cliErr.Details == nil {
+		cliErr.Details = map[string]interface{}{"file": path}
+	}
+	return cliErr
+}
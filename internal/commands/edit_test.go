@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// stubEditor replaces the editor with edit, which receives the file's
+// contents and returns the new ones.
+func stubEditor(t *testing.T, edit func(content string) string) {
+	t.Helper()
+	editFile = func(path string) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(edit(string(content))), 0o600)
+	}
+	t.Cleanup(func() { editFile = runEditor })
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(); !reflect.DeepEqual(got, []string{"vi"}) {
+		t.Errorf("expected vi by default, got %v", got)
+	}
+
+	t.Setenv("EDITOR", "nano")
+	if got := editorCommand(); !reflect.DeepEqual(got, []string{"nano"}) {
+		t.Errorf("expected $EDITOR, got %v", got)
+	}
+
+	t.Setenv("VISUAL", "code --wait")
+	if got := editorCommand(); !reflect.DeepEqual(got, []string{"code", "--wait"}) {
+		t.Errorf("expected $VISUAL with its arguments, got %v", got)
+	}
+}
+
+func TestCardDocument(t *testing.T) {
+	doc, err := formatCardDocument(cardFrontMatter{Title: "Fix: login", Tags: []string{"bug", "auth"}}, "Steps:\n\n- one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "---\ntitle: 'Fix: login'\ntags: [bug, auth]\n---\n\nSteps:\n\n- one\n"
+	if doc != expected {
+		t.Errorf("got: %q\nwant: %q", doc, expected)
+	}
+
+	meta, description, err := parseCardDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Fix: login" || !reflect.DeepEqual(meta.Tags, []string{"bug", "auth"}) {
+		t.Errorf("unexpected front matter %+v", meta)
+	}
+	if description != "Steps:\n\n- one" {
+		t.Errorf("unexpected description %q", description)
+	}
+}
+
+func TestParseCardDocument_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"no front matter", "title: x\n\nBody"},
+		{"unterminated", "---\ntitle: x\n\nBody"},
+		{"unknown field", "---\ntitle: x\nstatus: done\n---\n"},
+		{"bad yaml", "---\ntitle: [x\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := parseCardDocument(tt.doc); err == nil {
+				t.Errorf("expected an error for %q", tt.doc)
+			}
+		})
+	}
+}
+
+func TestToggledTags(t *testing.T) {
+	got := toggledTags([]string{"bug", "auth"}, []string{"#bug", "ui", "ui", " "})
+	if expected := []string{"auth", "ui"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export data",
+	Long:  "Commands for exporting Fizzy data to local files.",
+}
+
+// Export account flags
+var exportAccountOut string
+var exportAccountConcurrency int
+
+var exportAccountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Export the account to an archive",
+	Long: `Exports the account to a gzipped tar archive for backup or for moving to
+another account with "fizzy import account".
+
+The archive holds the users, tags, boards with their columns, and every card
+(including closed and not-now cards) with its steps, comments and their
+reactions, as JSON in the shape the API returns, plus the files attached to
+descriptions and comments and card images. A manifest records the archive
+format version.
+
+Cards are fetched concurrently. Attachments that can no longer be downloaded
+are left out and listed in the result.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+		if exportAccountOut == "" {
+			exitWithError(newRequiredFlagError("out"))
+		}
+		if exportAccountConcurrency < 1 {
+			exitWithError(errors.NewInvalidArgsError("--concurrency must be at least 1"))
+		}
+
+		result, err := exportAccount(getClient(), cfg.Account, exportAccountOut, exportAccountConcurrency)
+		if err != nil {
+			exitWithError(err)
+		}
+		printSuccess(result)
+	},
+}
+
+// exportAccount writes an archive of the account to out and returns a
+// summary of what it holds.
+func exportAccount(c client.API, account, out string, concurrency int) (map[string]interface{}, error) {
+	archive := &accountArchive{Manifest: archiveManifest{
+		Format:     archiveFormat,
+		Version:    archiveVersion,
+		Account:    account,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}}
+
+	var err error
+	if archive.Users, err = listAll(c, "/users.json"); err != nil {
+		return nil, err
+	}
+	if archive.Tags, err = listAll(c, "/tags.json"); err != nil {
+		return nil, err
+	}
+	if archive.Boards, err = listAll(c, "/boards.json"); err != nil {
+		return nil, err
+	}
+	for _, item := range archive.Boards {
+		board, _ := item.(map[string]interface{})
+		id, _ := board["id"].(string)
+		resp, err := c.Get("/boards/" + id + "/columns.json")
+		if err != nil {
+			return nil, err
+		}
+		columns, _ := resp.Data.([]interface{})
+		if columns == nil {
+			columns = []interface{}{}
+		}
+		board["columns"] = columns
+	}
+
+	numbers, err := exportCardNumbers(c)
+	if err != nil {
+		return nil, err
+	}
+	archive.Cards = []interface{}{}
+	if len(numbers) > 0 {
+		results, err := runBulk(numbers, concurrency, func(number string) (interface{}, error) {
+			return exportCard(c, number)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			archive.Cards = append(archive.Cards, result.(map[string]interface{})["data"])
+		}
+	}
+
+	blobDir, err := os.MkdirTemp("", "fizzy-export-*")
+	if err != nil {
+		return nil, errors.NewError("Failed to create a temporary directory: " + err.Error())
+	}
+	defer os.RemoveAll(blobDir)
+
+	missing, err := exportAttachments(c, archive, blobDir)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Manifest.Counts = archiveCounts(archive)
+	if err := writeArchive(out, archive, blobDir); err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"file":        out,
+		"format":      archiveFormat,
+		"version":     archiveVersion,
+		"exported_at": archive.Manifest.ExportedAt,
+		"counts":      archive.Manifest.Counts,
+	}
+	if len(missing) > 0 {
+		result["missing_attachments"] = missing
+	}
+	return result, nil
+}
+
+// listAll fetches every page of a list.
+func listAll(c client.API, path string) ([]interface{}, error) {
+	resp, err := c.GetWithPagination(path, true)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return []interface{}{}, nil
+	}
+	items, ok := resp.Data.([]interface{})
+	if !ok {
+		return nil, errors.NewError("Unexpected response from " + path)
+	}
+	return items, nil
+}
+
+// exportCardNumbers returns the numbers of every card in ascending order.
+// Closed and not-now cards are listed separately from the others.
+func exportCardNumbers(c client.API) ([]string, error) {
+	seen := map[string]bool{}
+	var numbers []string
+	for _, path := range []string{"/cards.json", "/cards.json?indexed_by=closed", "/cards.json?indexed_by=not_now"} {
+		items, err := listAll(c, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			card, _ := item.(map[string]interface{})
+			if number := cardNumber(card); number != "" && !seen[number] {
+				seen[number] = true
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool {
+		a, _ := strconv.Atoi(numbers[i])
+		b, _ := strconv.Atoi(numbers[j])
+		return a < b
+	})
+	return numbers, nil
+}
+
+// exportCard fetches a card with its steps, and its comments with their
+// reactions.
+func exportCard(c client.API, number string) (interface{}, error) {
+	resp, err := c.Get("/cards/" + number + ".json")
+	if err != nil {
+		return nil, err
+	}
+	card, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		return nil, errors.NewError("Unexpected response for card #" + number)
+	}
+
+	comments, err := listAll(c, "/cards/"+number+"/comments.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range comments {
+		comment, _ := item.(map[string]interface{})
+		id, _ := comment["id"].(string)
+		resp, err := c.Get("/cards/" + number + "/comments/" + id + "/reactions.json")
+		if err != nil {
+			return nil, err
+		}
+		reactions, _ := resp.Data.([]interface{})
+		if reactions == nil {
+			reactions = []interface{}{}
+		}
+		comment["reactions"] = reactions
+	}
+	card["comments"] = comments
+	return card, nil
+}
+
+// exportAttachments downloads the files attached to the archived cards and
+// comments into blobDir and lists them in the archive. Files that can't be
+// downloaded are returned with their errors instead.
+func exportAttachments(c client.API, archive *accountArchive, blobDir string) ([]interface{}, error) {
+	archive.Attachments = []archiveAttachment{}
+	missing := []interface{}{}
+	seen := map[string]bool{}
+
+	add := func(found richTextAttachment) error {
+		if seen[found.URL] {
+			return nil
+		}
+		seen[found.URL] = true
+
+		file := fmt.Sprintf("blobs/%04d/%s", len(archive.Attachments)+1, safeFilename(found.Filename))
+		contentType, err := downloadFile(c, found.URL, filepath.Join(blobDir, filepath.FromSlash(file)))
+		if err != nil {
+			if cliErr, ok := err.(*errors.CLIError); ok && cliErr.Code == "CANCELLED" {
+				return err
+			}
+			missing = append(missing, map[string]interface{}{"url": found.URL, "error": err.Error()})
+			return nil
+		}
+		if found.ContentType != "" {
+			contentType = found.ContentType
+		}
+		archive.Attachments = append(archive.Attachments, archiveAttachment{
+			URL:         found.URL,
+			File:        file,
+			Filename:    found.Filename,
+			ContentType: contentType,
+		})
+		return nil
+	}
+
+	for _, item := range archive.Cards {
+		card, _ := item.(map[string]interface{})
+		var found []richTextAttachment
+		if image, _ := card["image_url"].(string); image != "" {
+			found = append(found, richTextAttachment{URL: image, Filename: urlFilename(image)})
+		}
+		description, _ := card["description_html"].(string)
+		found = append(found, findAttachments(description)...)
+		comments, _ := card["comments"].([]interface{})
+		for _, item := range comments {
+			comment, _ := item.(map[string]interface{})
+			found = append(found, findAttachments(richTextHTML(comment["body"]))...)
+		}
+		for _, attachment := range found {
+			if err := add(attachment); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return missing, nil
+}
+
+// downloadFile downloads url to dst and returns its content type.
+func downloadFile(c client.API, url, dst string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", errors.NewError("Failed to create " + filepath.Dir(dst) + ": " + err.Error())
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return "", errors.NewError("Failed to create " + dst + ": " + err.Error())
+	}
+	contentType, err := c.Download(url, f)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = errors.NewError("Failed to write " + dst + ": " + closeErr.Error())
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+	return contentType, nil
+}
+
+// archiveCounts counts the records in an archive.
+func archiveCounts(archive *accountArchive) map[string]int {
+	counts := map[string]int{
+		"users":       len(archive.Users),
+		"tags":        len(archive.Tags),
+		"boards":      len(archive.Boards),
+		"cards":       len(archive.Cards),
+		"attachments": len(archive.Attachments),
+	}
+	for _, item := range archive.Boards {
+		board, _ := item.(map[string]interface{})
+		columns, _ := board["columns"].([]interface{})
+		counts["columns"] += len(columns)
+	}
+	for _, item := range archive.Cards {
+		card, _ := item.(map[string]interface{})
+		steps, _ := card["steps"].([]interface{})
+		counts["steps"] += len(steps)
+		comments, _ := card["comments"].([]interface{})
+		counts["comments"] += len(comments)
+		for _, item := range comments {
+			comment, _ := item.(map[string]interface{})
+			reactions, _ := comment["reactions"].([]interface{})
+			counts["reactions"] += len(reactions)
+		}
+	}
+	return counts
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	// Account
+	exportAccountCmd.Flags().StringVar(&exportAccountOut, "out", "", "Archive file to write, e.g. backup.tar.gz (required)")
+	exportAccountCmd.Flags().IntVar(&exportAccountConcurrency, "concurrency", defaultBulkConcurrency, "Number of cards to fetch at once")
+	exportCmd.AddCommand(exportAccountCmd)
+}
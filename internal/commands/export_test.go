@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// newAccountMock returns a mock account with a board, a column and two
+// cards, one of them closed, with a step, an attachment and a comment with a
+// reaction.
+func newAccountMock() *MockClient {
+	list := func(items ...interface{}) *client.APIResponse {
+		return &client.APIResponse{StatusCode: 200, Data: items}
+	}
+	get := func(data interface{}) *client.APIResponse {
+		return &client.APIResponse{StatusCode: 200, Data: data}
+	}
+	board := map[string]interface{}{"id": "b1", "name": "Roadmap", "all_access": true}
+	column := map[string]interface{}{"id": "c1", "name": "Doing", "color": map[string]interface{}{"name": "Lime", "value": "var(--color-card-4)"}}
+	open := map[string]interface{}{"id": "k1", "number": float64(7), "title": "Open"}
+	closed := map[string]interface{}{"id": "k2", "number": float64(3), "title": "Closed", "closed": true}
+
+	mock := NewMockClient()
+	mock.GetWithPaginationResponses = map[string]*client.APIResponse{
+		"/users.json":                    list(map[string]interface{}{"id": "u1", "name": "Ann", "email_address": "ann@example.com"}),
+		"/tags.json":                     list(map[string]interface{}{"id": "t1", "title": "bug"}),
+		"/boards.json":                   list(board),
+		"/cards.json":                    list(open),
+		"/cards.json?indexed_by=closed":  list(closed),
+		"/cards.json?indexed_by=not_now": list(),
+		"/cards/7/comments.json":         list(map[string]interface{}{"id": "m1", "created_at": "2024-01-02T00:00:00Z", "body": map[string]interface{}{"html": `<div class="action-text-content">Nice</div>`}}),
+		"/cards/3/comments.json":         list(),
+	}
+	mock.GetResponses = map[string]*client.APIResponse{
+		"/boards/b1/columns.json": get([]interface{}{column}),
+		"/cards/7.json": get(map[string]interface{}{
+			"id": "k1", "number": float64(7), "title": "Open", "created_at": "2024-01-01T00:00:00Z",
+			"board":            board,
+			"column":           column,
+			"tags":             []interface{}{"bug"},
+			"assignees":        []interface{}{map[string]interface{}{"id": "u1"}},
+			"description_html": `<div class="action-text-content">See <action-text-attachment sgid="s1" url="https://files.example.com/a.png" filename="a.png"></action-text-attachment></div>`,
+			"steps":            []interface{}{map[string]interface{}{"id": "s1", "content": "Check", "completed": true}},
+		}),
+		"/cards/3.json": get(map[string]interface{}{
+			"id": "k2", "number": float64(3), "title": "Closed", "closed": true, "board": board,
+		}),
+		"/cards/7/comments/m1/reactions.json": get([]interface{}{map[string]interface{}{"id": "r1", "content": "👍"}}),
+	}
+	mock.Downloads = map[string]string{"https://files.example.com/a.png": "png data"}
+	return mock
+}
+
+func TestExportAccount(t *testing.T) {
+	t.Run("writes an archive of the account", func(t *testing.T) {
+		mock := newAccountMock()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		out := filepath.Join(t.TempDir(), "backup.tar.gz")
+		exportAccountOut = out
+		defer func() { exportAccountOut = "" }()
+
+		RunTestCommand(func() {
+			exportAccountCmd.Run(exportAccountCmd, []string{})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		counts := result.Response.Data.(map[string]interface{})["counts"].(map[string]int)
+		want := map[string]int{"users": 1, "tags": 1, "boards": 1, "columns": 1, "cards": 2, "steps": 1, "comments": 1, "reactions": 1, "attachments": 1}
+		for kind, n := range want {
+			if counts[kind] != n {
+				t.Errorf("expected %d %s, got %d", n, kind, counts[kind])
+			}
+		}
+
+		archive, err := extractArchive(out, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if archive.Manifest.Account != "account" || archive.Manifest.Version != archiveVersion {
+			t.Errorf("unexpected manifest %+v", archive.Manifest)
+		}
+		// Cards are in number order, with their comments and reactions
+		first := archive.Cards[0].(map[string]interface{})
+		second := archive.Cards[1].(map[string]interface{})
+		if first["number"] != float64(3) || second["number"] != float64(7) {
+			t.Errorf("expected cards 3 and 7, got %v and %v", first["number"], second["number"])
+		}
+		comment := second["comments"].([]interface{})[0].(map[string]interface{})
+		if len(comment["reactions"].([]interface{})) != 1 {
+			t.Errorf("expected the comment's reaction, got %v", comment["reactions"])
+		}
+		if archive.Attachments[0].File != "blobs/0001/a.png" {
+			t.Errorf("unexpected attachment %+v", archive.Attachments[0])
+		}
+	})
+
+	t.Run("lists attachments that can't be downloaded", func(t *testing.T) {
+		mock := newAccountMock()
+		mock.Downloads = nil
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		exportAccountOut = filepath.Join(t.TempDir(), "backup.tar.gz")
+		defer func() { exportAccountOut = "" }()
+
+		RunTestCommand(func() {
+			exportAccountCmd.Run(exportAccountCmd, []string{})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		missing := result.Response.Data.(map[string]interface{})["missing_attachments"].([]interface{})
+		if len(missing) != 1 || missing[0].(map[string]interface{})["url"] != "https://files.example.com/a.png" {
+			t.Errorf("expected the attachment to be listed as missing, got %v", missing)
+		}
+	})
+
+	t.Run("requires --out", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			exportAccountCmd.Run(exportAccountCmd, []string{})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
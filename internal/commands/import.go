@@ -0,0 +1,609 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data",
+	Long:  "Commands for importing data into Fizzy.",
+}
+
+// Import account flags
+var importAccountState string
+
+var importAccountCmd = &cobra.Command{
+	Use:   "account ARCHIVE",
+	Short: "Import an account archive",
+	Long: `Recreates the contents of an archive written by "fizzy export account" in
+the current account: boards and their columns, then each card with its
+original creation time, tags, assignees, steps, comments (also with their
+original times) and reactions, and finally its column, closed or not-now
+state. Attached files and card images are uploaded again.
+
+Users can't be created, so they are matched to the account's users by email
+address; assignments to users without a match are skipped and the users are
+listed in the result. Cards, comments and reactions are created as you.
+
+Everything created is recorded in a state file (ARCHIVE.import.json unless
+--state is given), saved after each step. If the import stops, run the same
+command again to carry on where it left off. The result maps the IDs in the
+archive (card numbers for cards) to the new ones.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+
+		dir, err := os.MkdirTemp("", "fizzy-import-*")
+		if err != nil {
+			exitWithError(errors.NewError("Failed to create a temporary directory: " + err.Error()))
+		}
+		defer os.RemoveAll(dir)
+
+		archive, err := extractArchive(args[0], dir)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		statePath := importAccountState
+		if statePath == "" {
+			statePath = args[0] + ".import.json"
+		}
+		source := archive.Manifest.Account + "@" + archive.Manifest.ExportedAt
+		ledger, resumed, err := loadImportLedger(statePath, source, cfg.Account)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		im := &accountImport{c: getClient(), dir: dir, archive: archive, ledger: ledger}
+		if err := im.run(); err != nil {
+			exitWithError(err)
+		}
+
+		printSuccess(map[string]interface{}{
+			"state_file":      statePath,
+			"resumed":         resumed,
+			"ids":             ledger.mapping(),
+			"unmatched_users": im.unmatchedUsers,
+		})
+	},
+}
+
+// importLedger records what an import has created, mapping IDs in the
+// source to IDs in the account, so that running the import again carries on
+// where it stopped instead of creating duplicates. It is saved after every
+// change.
+type importLedger struct {
+	path string
+
+	Source  string                       `json:"source"`
+	Account string                       `json:"account"`
+	IDs     map[string]map[string]string `json:"ids"`
+	Done    map[string]bool              `json:"done"`
+}
+
+// loadImportLedger reads the ledger at path, or starts a new one if there
+// is none, and reports whether it was read. A ledger for a different source
+// or account is refused.
+func loadImportLedger(path, source, account string) (*importLedger, bool, error) {
+	ledger := &importLedger{path: path, Source: source, Account: account}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		ledger.IDs = map[string]map[string]string{}
+		ledger.Done = map[string]bool{}
+		return ledger, false, nil
+	case err != nil:
+		return nil, false, errors.NewError("Failed to read state file: " + err.Error())
+	}
+
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, false, errors.NewError("State file " + path + " is damaged: " + err.Error())
+	}
+	if ledger.Source != source || ledger.Account != account {
+		return nil, false, errors.NewInvalidArgsError("State file " + path + " belongs to an import of " + ledger.Source + " into " + ledger.Account + "; use --state to choose another")
+	}
+	if ledger.IDs == nil {
+		ledger.IDs = map[string]map[string]string{}
+	}
+	if ledger.Done == nil {
+		ledger.Done = map[string]bool{}
+	}
+	return ledger, true, nil
+}
+
+// lookup returns the new ID recorded for a source ID of a kind of record.
+func (l *importLedger) lookup(kind, id string) (string, bool) {
+	newID, ok := l.IDs[kind][id]
+	return newID, ok
+}
+
+// record records the new ID for a source ID and saves the ledger.
+func (l *importLedger) record(kind, id, newID string) error {
+	if l.IDs[kind] == nil {
+		l.IDs[kind] = map[string]string{}
+	}
+	l.IDs[kind][id] = newID
+	return l.save()
+}
+
+// mapping returns the recorded IDs of each kind of record, leaving out the
+// uploads.
+func (l *importLedger) mapping() map[string]map[string]string {
+	mapping := map[string]map[string]string{}
+	for _, kind := range []string{"users", "tags", "boards", "columns", "cards", "steps", "comments", "reactions"} {
+		ids := l.IDs[kind]
+		if ids == nil {
+			ids = map[string]string{}
+		}
+		mapping[kind] = ids
+	}
+	return mapping
+}
+
+// finish marks a step as done and saves the ledger.
+func (l *importLedger) finish(step string) error {
+	l.Done[step] = true
+	return l.save()
+}
+
+// save writes the ledger, replacing the file in one step so an interrupted
+// write doesn't lose it.
+func (l *importLedger) save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errors.NewError("Failed to encode state: " + err.Error())
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return errors.NewError("Failed to write state file: " + err.Error())
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return errors.NewError("Failed to write state file: " + err.Error())
+	}
+	return nil
+}
+
+// accountImport recreates an account archive in the current account.
+type accountImport struct {
+	c       client.API
+	dir     string
+	archive *accountArchive
+	ledger  *importLedger
+
+	files          map[string]archiveAttachment
+	unmatchedUsers []interface{}
+}
+
+func (im *accountImport) run() error {
+	if err := im.matchUsers(); err != nil {
+		return err
+	}
+	for _, item := range im.archive.Boards {
+		board, _ := item.(map[string]interface{})
+		if err := im.importBoard(board); err != nil {
+			return err
+		}
+	}
+	for _, item := range im.archive.Cards {
+		card, _ := item.(map[string]interface{})
+		if err := im.importCard(card); err != nil {
+			return err
+		}
+	}
+	return im.matchTags()
+}
+
+// matchUsers maps the archived users to the account's users by email
+// address.
+func (im *accountImport) matchUsers() error {
+	users, err := listAll(im.c, "/users.json")
+	if err != nil {
+		return err
+	}
+	byEmail := map[string]string{}
+	for _, item := range users {
+		user, _ := item.(map[string]interface{})
+		email, _ := user["email_address"].(string)
+		id, _ := user["id"].(string)
+		if email != "" {
+			byEmail[strings.ToLower(email)] = id
+		}
+	}
+
+	im.unmatchedUsers = []interface{}{}
+	for _, item := range im.archive.Users {
+		user, _ := item.(map[string]interface{})
+		id, _ := user["id"].(string)
+		email, _ := user["email_address"].(string)
+		newID, ok := byEmail[strings.ToLower(email)]
+		if email == "" || !ok {
+			im.unmatchedUsers = append(im.unmatchedUsers, map[string]interface{}{
+				"id":            id,
+				"name":          user["name"],
+				"email_address": user["email_address"],
+			})
+			continue
+		}
+		if existing, ok := im.ledger.lookup("users", id); ok && existing == newID {
+			continue
+		}
+		if err := im.ledger.record("users", id, newID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchTags maps the archived tags to the account's tags by title. Tags are
+// created by tagging cards, so tags that weren't on any card have no match.
+func (im *accountImport) matchTags() error {
+	tags, err := listAll(im.c, "/tags.json")
+	if err != nil {
+		return err
+	}
+	byTitle := map[string]string{}
+	for _, item := range tags {
+		tag, _ := item.(map[string]interface{})
+		title, _ := tag["title"].(string)
+		id, _ := tag["id"].(string)
+		byTitle[strings.ToLower(title)] = id
+	}
+	for _, item := range im.archive.Tags {
+		tag, _ := item.(map[string]interface{})
+		title, _ := tag["title"].(string)
+		id, _ := tag["id"].(string)
+		newID, ok := byTitle[strings.ToLower(title)]
+		if existing, recorded := im.ledger.lookup("tags", id); ok && (!recorded || existing != newID) {
+			if err := im.ledger.record("tags", id, newID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importBoard creates a board and its columns.
+func (im *accountImport) importBoard(board map[string]interface{}) error {
+	id, _ := board["id"].(string)
+	boardID, ok := im.ledger.lookup("boards", id)
+	if !ok {
+		params := map[string]interface{}{"name": board["name"]}
+		if allAccess, ok := board["all_access"].(bool); ok {
+			params["all_access"] = allAccess
+		}
+		if period, ok := board["auto_postpone_period"].(float64); ok && period > 0 {
+			params["auto_postpone_period"] = int(period)
+		}
+		resp, err := im.c.Post("/boards.json", map[string]interface{}{"board": params})
+		if err != nil {
+			return err
+		}
+		if boardID, err = createdID(resp, "board"); err != nil {
+			return err
+		}
+		if err := im.ledger.record("boards", id, boardID); err != nil {
+			return err
+		}
+	}
+
+	columns, _ := board["columns"].([]interface{})
+	for _, item := range columns {
+		column, _ := item.(map[string]interface{})
+		id, _ := column["id"].(string)
+		if _, ok := im.ledger.lookup("columns", id); ok {
+			continue
+		}
+		params := map[string]interface{}{"name": column["name"]}
+		if color, ok := column["color"].(map[string]interface{}); ok && color["value"] != nil {
+			params["color"] = color["value"]
+		} else if color, ok := column["color"].(string); ok && color != "" {
+			params["color"] = color
+		}
+		resp, err := im.c.Post("/boards/"+boardID+"/columns.json", map[string]interface{}{"column": params})
+		if err != nil {
+			return err
+		}
+		columnID, err := createdID(resp, "column")
+		if err != nil {
+			return err
+		}
+		if err := im.ledger.record("columns", id, columnID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importCard creates a card, then fills in everything else about it. A card
+// is finished once it is in its column or lane.
+func (im *accountImport) importCard(card map[string]interface{}) error {
+	oldNumber := cardNumber(card)
+	if im.ledger.Done["card:"+oldNumber] {
+		return nil
+	}
+	typed, _ := decodeCard(card)
+
+	number, ok := im.ledger.lookup("cards", oldNumber)
+	if !ok {
+		var boardID string
+		if typed.Board != nil {
+			boardID, ok = im.ledger.lookup("boards", typed.Board.ID)
+		}
+		if !ok {
+			return errors.NewError("Card #" + oldNumber + " is on a board that is not in the archive")
+		}
+
+		params := map[string]interface{}{"title": typed.Title}
+		description, err := im.richText(richTextHTML(card["description_html"]))
+		if err != nil {
+			return err
+		}
+		if description != "" {
+			params["description"] = description
+		}
+		if createdAt, _ := card["created_at"].(string); createdAt != "" {
+			params["created_at"] = createdAt
+		}
+		if typed.ImageURL != "" {
+			if signedID, ok, err := im.upload(typed.ImageURL); err != nil {
+				return err
+			} else if ok {
+				params["image"] = signedID
+			}
+		}
+
+		resp, err := im.c.Post("/cards.json", map[string]interface{}{"board_id": boardID, "card": params})
+		if err != nil {
+			return err
+		}
+		if number, err = createdID(resp, "card"); err != nil {
+			return err
+		}
+		if err := im.ledger.record("cards", oldNumber, number); err != nil {
+			return err
+		}
+	}
+
+	// Tags and assignees toggle, so compare with the card as it is now in
+	// case an earlier run got part of the way.
+	resp, err := im.c.Get("/cards/" + number + ".json")
+	if err != nil {
+		return err
+	}
+	current, _ := decodeCard(resp.Data)
+	for _, tag := range toggledTags(current.Tags, typed.Tags) {
+		if _, err := im.c.Post("/cards/"+number+"/taggings.json", map[string]interface{}{"tag_title": tag}); err != nil {
+			return err
+		}
+	}
+	assigned := map[string]bool{}
+	for _, user := range current.Assignees {
+		assigned[user.ID] = true
+	}
+	for _, user := range typed.Assignees {
+		userID, ok := im.ledger.lookup("users", user.ID)
+		if !ok || assigned[userID] {
+			continue
+		}
+		if _, err := im.c.Post("/cards/"+number+"/assignments.json", map[string]interface{}{"assignee_id": userID}); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range typed.Steps {
+		if _, ok := im.ledger.lookup("steps", step.ID); ok {
+			continue
+		}
+		params := map[string]interface{}{"content": step.Content, "completed": step.Completed}
+		resp, err := im.c.Post("/cards/"+number+"/steps.json", map[string]interface{}{"step": params})
+		if err != nil {
+			return err
+		}
+		stepID, err := createdID(resp, "step")
+		if err != nil {
+			return err
+		}
+		if err := im.ledger.record("steps", step.ID, stepID); err != nil {
+			return err
+		}
+	}
+
+	comments, _ := card["comments"].([]interface{})
+	for _, item := range comments {
+		comment, _ := item.(map[string]interface{})
+		if err := im.importComment(number, comment); err != nil {
+			return err
+		}
+	}
+
+	if columnID := typed.ColumnID(); columnID != "" {
+		if newColumnID, ok := im.ledger.lookup("columns", columnID); ok {
+			if _, err := moveCardToColumn(im.c, number, newColumnID); err != nil {
+				return err
+			}
+		}
+	}
+	if typed.Postponed && !typed.Closed {
+		if _, err := im.c.Post("/cards/"+number+"/not_now.json", nil); err != nil {
+			return err
+		}
+	}
+	if typed.Closed {
+		if _, err := im.c.Post("/cards/"+number+"/closure.json", nil); err != nil {
+			return err
+		}
+	}
+	return im.ledger.finish("card:" + oldNumber)
+}
+
+// importComment creates a comment on a card and its reactions.
+func (im *accountImport) importComment(number string, comment map[string]interface{}) error {
+	id, _ := comment["id"].(string)
+	commentID, ok := im.ledger.lookup("comments", id)
+	if !ok {
+		body, err := im.richText(richTextHTML(comment["body"]))
+		if err != nil {
+			return err
+		}
+		if body == "" {
+			return nil
+		}
+		params := map[string]interface{}{"body": body}
+		if createdAt, _ := comment["created_at"].(string); createdAt != "" {
+			params["created_at"] = createdAt
+		}
+		resp, err := im.c.Post("/cards/"+number+"/comments.json", map[string]interface{}{"comment": params})
+		if err != nil {
+			return err
+		}
+		if commentID, err = createdID(resp, "comment"); err != nil {
+			return err
+		}
+		if err := im.ledger.record("comments", id, commentID); err != nil {
+			return err
+		}
+	}
+
+	reactions, _ := comment["reactions"].([]interface{})
+	path := "/cards/" + number + "/comments/" + commentID + "/reactions.json"
+	for _, item := range reactions {
+		reaction, _ := item.(map[string]interface{})
+		id, _ := reaction["id"].(string)
+		if _, ok := im.ledger.lookup("reactions", id); ok {
+			continue
+		}
+		content, _ := reaction["content"].(string)
+		if _, err := im.c.Post(path, map[string]interface{}{"content": content}); err != nil {
+			return err
+		}
+		// Creating a reaction doesn't return it, so find it in the list
+		reactionID, err := im.newReactionID(path, content)
+		if err != nil {
+			return err
+		}
+		if err := im.ledger.record("reactions", id, reactionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newReactionID returns the ID of a reaction with content that isn't yet
+// in the ledger, failing if there's none.
+func (im *accountImport) newReactionID(path, content string) (string, error) {
+	resp, err := im.c.Get(path)
+	if err != nil {
+		return "", err
+	}
+	known := map[string]bool{}
+	for _, id := range im.ledger.IDs["reactions"] {
+		known[id] = true
+	}
+	items, _ := resp.Data.([]interface{})
+	for i := len(items) - 1; i >= 0; i-- {
+		reaction, _ := items[i].(map[string]interface{})
+		id, _ := reaction["id"].(string)
+		if id != "" && reaction["content"] == content && !known[id] {
+			return id, nil
+		}
+	}
+	return "", errors.NewError("Could not find the new " + content + " reaction at " + path)
+}
+
+// richText uploads the archived files attached to rich text and points the
+// attachments at the new uploads.
+func (im *accountImport) richText(content string) (string, error) {
+	sgids := map[string]string{}
+	for _, attachment := range findAttachments(content) {
+		if _, ok := im.archivedFile(attachment.URL); !ok {
+			continue
+		}
+		if _, _, err := im.upload(attachment.URL); err != nil {
+			return "", err
+		}
+		sgids[attachment.URL], _ = im.ledger.lookup("attachments", attachment.URL)
+	}
+	return replaceAttachments(content, sgids), nil
+}
+
+// upload uploads the archived file for a URL once, recording its attachable
+// SGID under "attachments" and its signed ID under "images". It reports
+// false if the file isn't in the archive.
+func (im *accountImport) upload(url string) (string, bool, error) {
+	if signedID, ok := im.ledger.lookup("images", url); ok {
+		return signedID, true, nil
+	}
+	attachment, ok := im.archivedFile(url)
+	if !ok {
+		return "", false, nil
+	}
+
+	// Files are archived under their original names
+	local, ok := archivePath(attachment.File)
+	if !ok {
+		return "", false, errors.NewError("Archive contains an invalid path " + strconv.Quote(attachment.File))
+	}
+
+	resp, err := im.c.UploadFile(filepath.Join(im.dir, local), attachment.ContentType)
+	if err != nil {
+		return "", false, err
+	}
+	data, _ := resp.Data.(map[string]interface{})
+	signedID, _ := data["signed_id"].(string)
+	sgid, _ := data["attachable_sgid"].(string)
+	if signedID == "" {
+		return "", false, errors.NewError("Upload of " + attachment.Filename + " returned no signed_id")
+	}
+	if err := im.ledger.record("attachments", url, sgid); err != nil {
+		return "", false, err
+	}
+	if err := im.ledger.record("images", url, signedID); err != nil {
+		return "", false, err
+	}
+	return signedID, true, nil
+}
+
+// archivedFile returns the archived file for a URL.
+func (im *accountImport) archivedFile(url string) (archiveAttachment, bool) {
+	if im.files == nil {
+		im.files = make(map[string]archiveAttachment, len(im.archive.Attachments))
+		for _, attachment := range im.archive.Attachments {
+			im.files[attachment.URL] = attachment
+		}
+	}
+	attachment, ok := im.files[url]
+	return attachment, ok
+}
+
+// createdID returns the ID (or number, for cards) at the end of the
+// Location of a created record.
+func createdID(resp *client.APIResponse, kind string) (string, error) {
+	if resp == nil || resp.Location == "" {
+		return "", errors.NewError("Creating a " + kind + " returned no location")
+	}
+	location := resp.Location
+	if i := strings.IndexAny(location, "?#"); i >= 0 {
+		location = location[:i]
+	}
+	return strings.TrimSuffix(path.Base(location), ".json"), nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	// Account
+	importAccountCmd.Flags().StringVar(&importAccountState, "state", "", "State file recording the import's progress (default ARCHIVE.import.json)")
+	importCmd.AddCommand(importAccountCmd)
+}
@@ -0,0 +1,824 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// Import cards flags
+var importCardsFrom string
+var importCardsBoard string
+var importCardsState string
+var importCardsDryRun bool
+
+var importCardsCmd = &cobra.Command{
+	Use:   "cards FILE",
+	Short: "Import cards from another tool",
+	Long: `Creates cards on a board from a file exported by another tool:
+
+  csv                 A header row naming the fields: title (required),
+                      description, column, tags (comma separated), steps (one
+                      per line, "[x] " for completed ones), created_at, closed
+                      (true/yes/1) and id
+  trello-json         A Trello board export (Menu > Print, export and share)
+  github-issues-json  The output of "gh issue list --json
+                      number,title,body,state,labels,createdAt,comments", or
+                      of the issues REST API
+  markdown            Checklists: headings name columns, top-level list items
+                      are cards ("- [x]" for closed ones, trailing #tags are
+                      tags) and items nested under them are steps, or
+                      description lines if they aren't checkboxes
+
+Source columns and lists go to the board's column of the same name, which is
+created if there isn't one. Labels become tags, checklists (and task lists in
+issue bodies) become steps, and comments are added with their original times.
+Cards keep their original creation time, and closed ones are closed.
+Descriptions and comments are read as Markdown.
+
+Everything created is recorded in a state file (FILE.import.json unless
+--state is given), so running the same import again skips the cards already
+imported and finishes any that were interrupted. Use --dry-run to see what
+would be imported without changing anything.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+		if importCardsFrom == "" {
+			exitWithError(newRequiredFlagError("from"))
+		}
+		parse, ok := cardSources[importCardsFrom]
+		if !ok {
+			exitWithError(errors.NewInvalidArgsError("Invalid source " + importCardsFrom + " (use csv, trello-json, github-issues-json or markdown)"))
+		}
+		boardID, err := requireBoard(importCardsBoard)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			exitWithError(errors.NewError("Failed to read " + args[0] + ": " + err.Error()))
+		}
+		cards, err := parse(data)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		statePath := importCardsState
+		if statePath == "" {
+			statePath = args[0] + ".import.json"
+		}
+		source := importCardsFrom + ":" + filepath.Base(args[0]) + " to board " + boardID
+		ledger, resumed, err := loadImportLedger(statePath, source, cfg.Account)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		im := &cardImport{c: getClient(), boardID: boardID, baseDir: filepath.Dir(args[0]), ledger: ledger}
+		if err := im.loadColumns(); err != nil {
+			exitWithError(err)
+		}
+
+		if importCardsDryRun {
+			printSuccess(map[string]interface{}{
+				"dry_run":     true,
+				"board_id":    boardID,
+				"new_columns": im.missingColumns(cards),
+				"cards":       im.plan(cards),
+			})
+			return
+		}
+
+		results := make([]interface{}, 0, len(cards))
+		for _, card := range cards {
+			result, err := im.importCard(card)
+			if err != nil {
+				exitWithError(err)
+			}
+			results = append(results, result)
+		}
+		printSuccess(map[string]interface{}{
+			"state_file":  statePath,
+			"resumed":     resumed,
+			"board_id":    boardID,
+			"new_columns": im.newColumns,
+			"cards":       results,
+		})
+	},
+}
+
+// sourceCard is a card read from another tool's export.
+type sourceCard struct {
+	// Key identifies the card in the source, for the state file
+	Key         string
+	Title       string
+	Description string // Markdown
+	Column      string // Column name, or "" to leave the card in triage
+	Tags        []string
+	Steps       []sourceStep
+	Comments    []sourceComment
+	CreatedAt   string
+	Closed      bool
+}
+
+type sourceStep struct {
+	Content   string
+	Completed bool
+}
+
+type sourceComment struct {
+	Key       string
+	Body      string // Markdown
+	CreatedAt string
+}
+
+// cardSources parses each --from format.
+var cardSources = map[string]func(data []byte) ([]sourceCard, error){
+	"csv":                parseCSVCards,
+	"trello-json":        parseTrelloCards,
+	"github-issues-json": parseGitHubIssues,
+	"markdown":           parseMarkdownCards,
+}
+
+// titleKeys gives cards that have no ID in the source a key made from their
+// title, numbering repeated titles, so the same file always gives the same
+// keys.
+type titleKeys map[string]int
+
+func (k titleKeys) key(title string) string {
+	k[title]++
+	if n := k[title]; n > 1 {
+		return fmt.Sprintf("%s (%d)", title, n)
+	}
+	return title
+}
+
+// csvFields maps the header names accepted in CSV files to fields.
+var csvFields = map[string]string{
+	"id":          "id",
+	"title":       "title",
+	"name":        "title",
+	"description": "description",
+	"body":        "description",
+	"column":      "column",
+	"list":        "column",
+	"status":      "column",
+	"tags":        "tags",
+	"labels":      "tags",
+	"steps":       "steps",
+	"checklist":   "steps",
+	"created_at":  "created_at",
+	"created":     "created_at",
+	"closed":      "closed",
+}
+
+func parseCSVCards(data []byte) ([]sourceCard, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.NewInvalidArgsError("Invalid CSV: " + err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if field, ok := csvFields[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.NewInvalidArgsError("CSV has no title column")
+	}
+
+	keys := titleKeys{}
+	var cards []sourceCard
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewInvalidArgsError("Invalid CSV: " + err.Error())
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		title := field("title")
+		if title == "" {
+			return nil, errors.NewInvalidArgsError(fmt.Sprintf("Row %d has no title", line))
+		}
+		card := sourceCard{
+			Key:         field("id"),
+			Title:       title,
+			Description: field("description"),
+			Column:      field("column"),
+			CreatedAt:   field("created_at"),
+			Tags:        splitList(field("tags")),
+		}
+		if card.Key == "" {
+			card.Key = keys.key(title)
+		}
+		switch strings.ToLower(field("closed")) {
+		case "true", "yes", "1", "x", "closed":
+			card.Closed = true
+		}
+		for _, line := range strings.Split(field("steps"), "\n") {
+			if step, ok := parseStepLine(line); ok {
+				card.Steps = append(card.Steps, step)
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// splitList splits a comma or semicolon separated list.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var stepLineRE = regexp.MustCompile(`^(?:[-*+]\s+)?\[([ xX])\]\s+(.*)$`)
+
+// parseStepLine reads a step from a line, completed if it starts with
+// "[x]".
+func parseStepLine(line string) (sourceStep, bool) {
+	line = strings.TrimSpace(line)
+	if m := stepLineRE.FindStringSubmatch(line); m != nil {
+		return sourceStep{Content: strings.TrimSpace(m[2]), Completed: m[1] != " "}, m[2] != ""
+	}
+	return sourceStep{Content: line}, line != ""
+}
+
+type trelloExport struct {
+	Lists []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+	} `json:"lists"`
+	Cards []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Desc   string  `json:"desc"`
+		IDList string  `json:"idList"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+		Labels []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Actions []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Date string `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+	} `json:"actions"`
+}
+
+func parseTrelloCards(data []byte) ([]sourceCard, error) {
+	var export trelloExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, errors.NewInvalidArgsError("Invalid Trello export: " + err.Error())
+	}
+
+	type list struct {
+		name   string
+		closed bool
+		order  int
+	}
+	sort.SliceStable(export.Lists, func(i, j int) bool { return export.Lists[i].Pos < export.Lists[j].Pos })
+	lists := map[string]list{}
+	for i, l := range export.Lists {
+		lists[l.ID] = list{name: l.Name, closed: l.Closed, order: i}
+	}
+
+	// Checklists and items are in position order
+	sort.SliceStable(export.Checklists, func(i, j int) bool { return export.Checklists[i].Pos < export.Checklists[j].Pos })
+	steps := map[string][]sourceStep{}
+	for _, checklist := range export.Checklists {
+		items := checklist.CheckItems
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+		for _, item := range items {
+			steps[checklist.IDCard] = append(steps[checklist.IDCard], sourceStep{Content: item.Name, Completed: item.State == "complete"})
+		}
+	}
+
+	// Actions are newest first
+	created := map[string]string{}
+	comments := map[string][]sourceComment{}
+	for i := len(export.Actions) - 1; i >= 0; i-- {
+		action := export.Actions[i]
+		switch action.Type {
+		case "createCard":
+			created[action.Data.Card.ID] = action.Date
+		case "commentCard":
+			comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], sourceComment{Key: action.ID, Body: action.Data.Text, CreatedAt: action.Date})
+		}
+	}
+
+	cards := export.Cards
+	sort.SliceStable(cards, func(i, j int) bool {
+		li, lj := lists[cards[i].IDList], lists[cards[j].IDList]
+		if li.order != lj.order {
+			return li.order < lj.order
+		}
+		return cards[i].Pos < cards[j].Pos
+	})
+	result := make([]sourceCard, 0, len(cards))
+	for _, c := range cards {
+		l := lists[c.IDList]
+		card := sourceCard{
+			Key:         c.ID,
+			Title:       c.Name,
+			Description: c.Desc,
+			Column:      l.name,
+			Steps:       steps[c.ID],
+			Comments:    comments[c.ID],
+			CreatedAt:   created[c.ID],
+			Closed:      c.Closed || l.closed,
+		}
+		if card.CreatedAt == "" {
+			card.CreatedAt = trelloIDTime(c.ID)
+		}
+		for _, label := range c.Labels {
+			if label.Name != "" {
+				card.Tags = append(card.Tags, label.Name)
+			} else if label.Color != "" {
+				card.Tags = append(card.Tags, label.Color)
+			}
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+// trelloIDTime returns the creation time held in the first four bytes of a
+// Trello ID.
+func trelloIDTime(id string) string {
+	if len(id) < 8 {
+		return ""
+	}
+	seconds, err := strconv.ParseInt(id[:8], 16, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+// githubIssue is an issue from "gh issue list --json" (camelCase) or the
+// REST API (snake_case, with comments as a count).
+type githubIssue struct {
+	Number       int             `json:"number"`
+	Title        string          `json:"title"`
+	Body         string          `json:"body"`
+	State        string          `json:"state"`
+	CreatedAt    string          `json:"createdAt"`
+	CreatedAtAPI string          `json:"created_at"`
+	Labels       []interface{}   `json:"labels"`
+	Comments     json.RawMessage `json:"comments"`
+	PullRequest  json.RawMessage `json:"pull_request"`
+}
+
+type githubComment struct {
+	ID           string `json:"id"`
+	Body         string `json:"body"`
+	CreatedAt    string `json:"createdAt"`
+	CreatedAtAPI string `json:"created_at"`
+}
+
+func parseGitHubIssues(data []byte) ([]sourceCard, error) {
+	var issues []githubIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, errors.NewInvalidArgsError("Invalid GitHub issues JSON (expected a list of issues): " + err.Error())
+	}
+
+	// Issues are listed newest first
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	var cards []sourceCard
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+		description, steps := splitTaskList(issue.Body)
+		card := sourceCard{
+			Key:         "#" + strconv.Itoa(issue.Number),
+			Title:       issue.Title,
+			Description: description,
+			Steps:       steps,
+			CreatedAt:   firstNonEmpty(issue.CreatedAt, issue.CreatedAtAPI),
+			Closed:      strings.EqualFold(issue.State, "closed"),
+		}
+		for _, label := range issue.Labels {
+			switch l := label.(type) {
+			case string:
+				card.Tags = append(card.Tags, l)
+			case map[string]interface{}:
+				if name, _ := l["name"].(string); name != "" {
+					card.Tags = append(card.Tags, name)
+				}
+			}
+		}
+
+		// The REST API gives only a count of comments
+		var comments []githubComment
+		if err := json.Unmarshal(issue.Comments, &comments); err == nil {
+			for i, comment := range comments {
+				key := comment.ID
+				if key == "" {
+					key = strconv.Itoa(i + 1)
+				}
+				card.Comments = append(card.Comments, sourceComment{
+					Key:       key,
+					Body:      comment.Body,
+					CreatedAt: firstNonEmpty(comment.CreatedAt, comment.CreatedAtAPI),
+				})
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+var taskListRE = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.+)$`)
+
+// splitTaskList takes the task list items out of Markdown and returns them
+// as steps.
+func splitTaskList(text string) (string, []sourceStep) {
+	var kept []string
+	var steps []sourceStep
+	for _, line := range strings.Split(text, "\n") {
+		if m := taskListRE.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			steps = append(steps, sourceStep{Content: strings.TrimSpace(m[2]), Completed: m[1] != " "})
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), steps
+}
+
+var (
+	markdownHeadingRE = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	markdownItemRE    = regexp.MustCompile(`^[-*+]\s+(?:\[([ xX])\]\s+)?(.*)$`)
+	trailingTagRE     = regexp.MustCompile(`\s+#([\p{L}\p{N}_-]+)$`)
+)
+
+func parseMarkdownCards(data []byte) ([]sourceCard, error) {
+	keys := titleKeys{}
+	var cards []sourceCard
+	var column string
+	var description []string
+	inCard := false
+
+	finish := func() {
+		if inCard {
+			cards[len(cards)-1].Description = strings.TrimSpace(strings.Join(description, "\n"))
+		}
+		description = nil
+		inCard = false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(expandIndent(line), " \r")
+		indented := strings.HasPrefix(line, " ")
+		switch {
+		case line == "":
+			if inCard {
+				description = append(description, "")
+			}
+		case indented:
+			if !inCard {
+				continue
+			}
+			card := &cards[len(cards)-1]
+			if m := taskListRE.FindStringSubmatch(line); m != nil {
+				card.Steps = append(card.Steps, sourceStep{Content: strings.TrimSpace(m[2]), Completed: m[1] != " "})
+			} else {
+				description = append(description, strings.TrimPrefix(line, "  "))
+			}
+		case markdownHeadingRE.MatchString(line):
+			finish()
+			column = markdownHeadingRE.FindStringSubmatch(line)[1]
+		case markdownItemRE.MatchString(line):
+			finish()
+			m := markdownItemRE.FindStringSubmatch(line)
+			title := strings.TrimSpace(m[2])
+			var tags []string
+			for {
+				tag := trailingTagRE.FindStringSubmatchIndex(title)
+				if tag == nil {
+					break
+				}
+				tags = append([]string{title[tag[2]:tag[3]]}, tags...)
+				title = strings.TrimSpace(title[:tag[0]])
+			}
+			if title == "" {
+				continue
+			}
+			cards = append(cards, sourceCard{
+				Key:    keys.key(title),
+				Title:  title,
+				Column: column,
+				Tags:   tags,
+				Closed: m[1] != "" && m[1] != " ",
+			})
+			inCard = true
+		default:
+			// Other text between cards isn't part of any card
+			finish()
+		}
+	}
+	finish()
+	return cards, nil
+}
+
+// expandIndent replaces leading tabs with four spaces.
+func expandIndent(line string) string {
+	trimmed := strings.TrimLeft(line, "\t")
+	return strings.Repeat("    ", len(line)-len(trimmed)) + trimmed
+}
+
+// cardImport creates cards read from another tool on a board.
+type cardImport struct {
+	c       client.API
+	boardID string
+	baseDir string
+	ledger  *importLedger
+
+	columns    map[string]string
+	newColumns []string
+}
+
+// loadColumns reads the board's columns by name.
+func (im *cardImport) loadColumns() error {
+	resp, err := im.c.Get("/boards/" + im.boardID + "/columns.json")
+	if err != nil {
+		return err
+	}
+	im.columns = map[string]string{}
+	im.newColumns = []string{}
+	items, _ := resp.Data.([]interface{})
+	for _, item := range items {
+		column, _ := item.(map[string]interface{})
+		name, _ := column["name"].(string)
+		id, _ := column["id"].(string)
+		if _, ok := im.columns[strings.ToLower(name)]; !ok && id != "" {
+			im.columns[strings.ToLower(name)] = id
+		}
+	}
+	return nil
+}
+
+// missingColumns returns the columns the cards need that the board doesn't
+// have, in the order they are first needed.
+func (im *cardImport) missingColumns(cards []sourceCard) []string {
+	missing := []string{}
+	seen := map[string]bool{}
+	for _, card := range cards {
+		name := strings.ToLower(card.Column)
+		if card.Column == "" || seen[name] || im.columns[name] != "" {
+			continue
+		}
+		seen[name] = true
+		missing = append(missing, card.Column)
+	}
+	return missing
+}
+
+// plan describes what importing each card would do.
+func (im *cardImport) plan(cards []sourceCard) []interface{} {
+	plan := make([]interface{}, 0, len(cards))
+	for _, card := range cards {
+		action := "create"
+		number, imported := im.ledger.lookup("cards", card.Key)
+		if im.ledger.Done["card:"+card.Key] {
+			action = "skip"
+		} else if imported {
+			action = "finish"
+		}
+		entry := map[string]interface{}{
+			"key":      card.Key,
+			"title":    card.Title,
+			"action":   action,
+			"column":   card.Column,
+			"tags":     nonNilStrings(card.Tags),
+			"steps":    len(card.Steps),
+			"comments": len(card.Comments),
+			"closed":   card.Closed,
+		}
+		if card.CreatedAt != "" {
+			entry["created_at"] = card.CreatedAt
+		}
+		if imported {
+			entry["number"] = number
+		}
+		plan = append(plan, entry)
+	}
+	return plan
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// importCard creates a card and fills in everything else about it, carrying
+// on from the state file if it was started before. It returns what was
+// done.
+func (im *cardImport) importCard(card sourceCard) (map[string]interface{}, error) {
+	result := map[string]interface{}{"key": card.Key, "title": card.Title}
+	number, imported := im.ledger.lookup("cards", card.Key)
+	if im.ledger.Done["card:"+card.Key] {
+		result["number"] = number
+		result["action"] = "skipped"
+		return result, nil
+	}
+
+	var columnID string
+	if card.Column != "" {
+		var err error
+		if columnID, err = im.column(card.Column); err != nil {
+			return nil, err
+		}
+	}
+
+	result["action"] = "finished"
+	if !imported {
+		params := map[string]interface{}{"title": card.Title}
+		if card.Description != "" {
+			description, err := markdownToHTML(card.Description, im.baseDir)
+			if err != nil {
+				return nil, err
+			}
+			params["description"] = description
+		}
+		if card.CreatedAt != "" {
+			params["created_at"] = card.CreatedAt
+		}
+		resp, err := im.c.Post("/cards.json", map[string]interface{}{"board_id": im.boardID, "card": params})
+		if err != nil {
+			return nil, err
+		}
+		if number, err = createdID(resp, "card"); err != nil {
+			return nil, err
+		}
+		if err := im.ledger.record("cards", card.Key, number); err != nil {
+			return nil, err
+		}
+		result["action"] = "created"
+	}
+	result["number"] = number
+
+	if len(card.Tags) > 0 {
+		// Tags toggle, so compare with the card as it is now in case an
+		// earlier run got part of the way.
+		resp, err := im.c.Get("/cards/" + number + ".json")
+		if err != nil {
+			return nil, err
+		}
+		current, _ := decodeCard(resp.Data)
+		for _, tag := range toggledTags(current.Tags, card.Tags) {
+			if _, err := im.c.Post("/cards/"+number+"/taggings.json", map[string]interface{}{"tag_title": tag}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, step := range card.Steps {
+		key := card.Key + "/" + strconv.Itoa(i+1)
+		if _, ok := im.ledger.lookup("steps", key); ok {
+			continue
+		}
+		params := map[string]interface{}{"content": step.Content, "completed": step.Completed}
+		resp, err := im.c.Post("/cards/"+number+"/steps.json", map[string]interface{}{"step": params})
+		if err != nil {
+			return nil, err
+		}
+		stepID, err := createdID(resp, "step")
+		if err != nil {
+			return nil, err
+		}
+		if err := im.ledger.record("steps", key, stepID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, comment := range card.Comments {
+		key := card.Key + "/" + comment.Key
+		if _, ok := im.ledger.lookup("comments", key); ok || strings.TrimSpace(comment.Body) == "" {
+			continue
+		}
+		body, err := markdownToHTML(comment.Body, im.baseDir)
+		if err != nil {
+			return nil, err
+		}
+		params := map[string]interface{}{"body": body}
+		if comment.CreatedAt != "" {
+			params["created_at"] = comment.CreatedAt
+		}
+		resp, err := im.c.Post("/cards/"+number+"/comments.json", map[string]interface{}{"comment": params})
+		if err != nil {
+			return nil, err
+		}
+		commentID, err := createdID(resp, "comment")
+		if err != nil {
+			return nil, err
+		}
+		if err := im.ledger.record("comments", key, commentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if columnID != "" {
+		if _, err := moveCardToColumn(im.c, number, columnID); err != nil {
+			return nil, err
+		}
+	}
+	if card.Closed {
+		if _, err := im.c.Post("/cards/"+number+"/closure.json", nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, im.ledger.finish("card:" + card.Key)
+}
+
+// column returns the ID of the board's column with a name, creating it if
+// there isn't one.
+func (im *cardImport) column(name string) (string, error) {
+	if id, ok := im.columns[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	resp, err := im.c.Post("/boards/"+im.boardID+"/columns.json", map[string]interface{}{"column": map[string]interface{}{"name": name}})
+	if err != nil {
+		return "", err
+	}
+	id, err := createdID(resp, "column")
+	if err != nil {
+		return "", err
+	}
+	im.columns[strings.ToLower(name)] = id
+	im.newColumns = append(im.newColumns, name)
+	return id, nil
+}
+
+func init() {
+	// Cards
+	importCardsCmd.Flags().StringVar(&importCardsFrom, "from", "", "Format of FILE: csv, trello-json, github-issues-json or markdown (required)")
+	importCardsCmd.Flags().StringVar(&importCardsBoard, "board", "", "Board to import into (ID or name)")
+	importCardsCmd.Flags().StringVar(&importCardsState, "state", "", "State file recording the import's progress (default FILE.import.json)")
+	importCardsCmd.Flags().BoolVar(&importCardsDryRun, "dry-run", false, "Show what would be imported without changing anything")
+	importCmd.AddCommand(importCardsCmd)
+}
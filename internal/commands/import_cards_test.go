@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func TestParseCSVCards(t *testing.T) {
+	data := "\ufeffTitle,Description,Status,Labels,Checklist,Created,Closed\n" +
+		"Fix login,It **fails**,Doing,\"bug, urgent\",\"[x] Reproduce\n[ ] Fix\",2024-01-02T03:04:05Z,\n" +
+		"Fix login,,,,,,yes\n"
+	cards, err := parseCSVCards([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("expected 2 cards, got %d", len(cards))
+	}
+	want := sourceCard{
+		Key:         "Fix login",
+		Title:       "Fix login",
+		Description: "It **fails**",
+		Column:      "Doing",
+		Tags:        []string{"bug", "urgent"},
+		Steps:       []sourceStep{{"Reproduce", true}, {"Fix", false}},
+		CreatedAt:   "2024-01-02T03:04:05Z",
+	}
+	if !reflect.DeepEqual(cards[0], want) {
+		t.Errorf("got %+v\nwant %+v", cards[0], want)
+	}
+	if cards[1].Key != "Fix login (2)" || !cards[1].Closed {
+		t.Errorf("expected a closed card with a numbered key, got %+v", cards[1])
+	}
+
+	if _, err := parseCSVCards([]byte("name2,description\nx,y\n")); err == nil || !strings.Contains(err.Error(), "no title column") {
+		t.Errorf("expected a missing title error, got %v", err)
+	}
+}
+
+func TestParseTrelloCards(t *testing.T) {
+	data := `{
+		"lists": [{"id": "l2", "name": "Done", "pos": 2}, {"id": "l1", "name": "To Do", "pos": 1}, {"id": "l3", "name": "Old", "pos": 3, "closed": true}],
+		"cards": [
+			{"id": "5f0000000000000000000002", "name": "Ship", "idList": "l2", "pos": 1, "labels": [{"name": "", "color": "green"}]},
+			{"id": "5f0000000000000000000001", "name": "Plan", "desc": "Notes", "idList": "l1", "pos": 1, "labels": [{"name": "Feature", "color": "blue"}]},
+			{"id": "5f0000000000000000000003", "name": "Gone", "idList": "l3", "pos": 1}
+		],
+		"checklists": [{"idCard": "5f0000000000000000000001", "pos": 1, "checkItems": [
+			{"name": "Second", "state": "incomplete", "pos": 2}, {"name": "First", "state": "complete", "pos": 1}
+		]}],
+		"actions": [
+			{"id": "a2", "type": "commentCard", "date": "2024-02-02T00:00:00.000Z", "data": {"text": "Later", "card": {"id": "5f0000000000000000000001"}}},
+			{"id": "a1", "type": "commentCard", "date": "2024-02-01T00:00:00.000Z", "data": {"text": "Sooner", "card": {"id": "5f0000000000000000000001"}}},
+			{"id": "a0", "type": "createCard", "date": "2024-01-31T00:00:00.000Z", "data": {"card": {"id": "5f0000000000000000000001"}}}
+		]
+	}`
+	cards, err := parseTrelloCards([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 || cards[0].Title != "Plan" || cards[1].Title != "Ship" || cards[2].Title != "Gone" {
+		t.Fatalf("expected cards in list order, got %+v", cards)
+	}
+	plan := cards[0]
+	if plan.Column != "To Do" || plan.Description != "Notes" || plan.CreatedAt != "2024-01-31T00:00:00.000Z" {
+		t.Errorf("unexpected card %+v", plan)
+	}
+	if !reflect.DeepEqual(plan.Steps, []sourceStep{{"First", true}, {"Second", false}}) {
+		t.Errorf("unexpected steps %+v", plan.Steps)
+	}
+	if len(plan.Comments) != 2 || plan.Comments[0].Body != "Sooner" || plan.Comments[0].Key != "a1" {
+		t.Errorf("expected comments oldest first, got %+v", plan.Comments)
+	}
+	if !reflect.DeepEqual(plan.Tags, []string{"Feature"}) || !reflect.DeepEqual(cards[1].Tags, []string{"green"}) {
+		t.Errorf("unexpected tags %v and %v", plan.Tags, cards[1].Tags)
+	}
+	// Without a createCard action the time comes from the ID
+	if cards[1].CreatedAt != "2020-07-04T04:05:20Z" {
+		t.Errorf("expected the time from the ID, got %q", cards[1].CreatedAt)
+	}
+	if !cards[2].Closed {
+		t.Error("expected a card in an archived list to be closed")
+	}
+}
+
+func TestParseGitHubIssues(t *testing.T) {
+	t.Run("gh issue list", func(t *testing.T) {
+		data := `[
+			{"number": 12, "title": "Later", "body": "", "state": "OPEN", "labels": [], "createdAt": "2024-03-02T00:00:00Z", "comments": []},
+			{"number": 3, "title": "Crash", "body": "Steps:\n- [x] Open app\n- [ ] Tap\n\nThen it crashes", "state": "CLOSED",
+			 "labels": [{"name": "bug"}], "createdAt": "2024-03-01T00:00:00Z",
+			 "comments": [{"id": "IC_1", "author": {"login": "ann"}, "body": "Same here", "createdAt": "2024-03-01T01:00:00Z"}]}
+		]`
+		cards, err := parseGitHubIssues([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) != 2 || cards[0].Key != "#3" {
+			t.Fatalf("expected issues in number order, got %+v", cards)
+		}
+		want := sourceCard{
+			Key:         "#3",
+			Title:       "Crash",
+			Description: "Steps:\n\nThen it crashes",
+			Tags:        []string{"bug"},
+			Steps:       []sourceStep{{"Open app", true}, {"Tap", false}},
+			Comments:    []sourceComment{{Key: "IC_1", Body: "Same here", CreatedAt: "2024-03-01T01:00:00Z"}},
+			CreatedAt:   "2024-03-01T00:00:00Z",
+			Closed:      true,
+		}
+		if !reflect.DeepEqual(cards[0], want) {
+			t.Errorf("got %+v\nwant %+v", cards[0], want)
+		}
+	})
+
+	t.Run("REST API", func(t *testing.T) {
+		data := `[
+			{"number": 5, "title": "Issue", "state": "open", "labels": [{"name": "docs"}], "created_at": "2024-01-01T00:00:00Z", "comments": 4},
+			{"number": 6, "title": "PR", "state": "open", "pull_request": {"url": "x"}}
+		]`
+		cards, err := parseGitHubIssues([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) != 1 || cards[0].CreatedAt != "2024-01-01T00:00:00Z" || len(cards[0].Comments) != 0 || cards[0].Tags[0] != "docs" {
+			t.Errorf("expected the issue without pull requests, got %+v", cards)
+		}
+	})
+}
+
+func TestParseMarkdownCards(t *testing.T) {
+	data := `# Launch
+
+Some intro text.
+
+## To Do
+
+- [ ] Write docs #docs #launch
+  Cover the install steps.
+  - [x] Outline
+  - [ ] Draft
+- Plain item
+
+## Done
+
+- [x] Pick a name
+`
+	cards, err := parseMarkdownCards([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sourceCard{
+		{
+			Key:         "Write docs",
+			Title:       "Write docs",
+			Description: "Cover the install steps.",
+			Column:      "To Do",
+			Tags:        []string{"docs", "launch"},
+			Steps:       []sourceStep{{"Outline", true}, {"Draft", false}},
+		},
+		{Key: "Plain item", Title: "Plain item", Column: "To Do"},
+		{Key: "Pick a name", Title: "Pick a name", Column: "Done", Closed: true},
+	}
+	if !reflect.DeepEqual(cards, want) {
+		t.Errorf("got %+v\nwant %+v", cards, want)
+	}
+}
+
+func TestImportCards(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cards.csv")
+	csv := "id,title,description,column,tags,steps,created_at,closed\n" +
+		"A-1,First,Hello,Doing,bug,Check,2024-01-01T00:00:00Z,\n" +
+		"A-2,Second,,Review,,,,yes\n"
+	if err := os.WriteFile(file, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newMock := func() *MockClient {
+		mock := NewMockClient()
+		mock.GetResponses = map[string]*client.APIResponse{
+			"/boards/board-1/columns.json": {StatusCode: 200, Data: []interface{}{map[string]interface{}{"id": "col-1", "name": "doing"}}},
+		}
+		return mock
+	}
+	run := func(mock *MockClient, state string) *CommandResult {
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		importCardsFrom = "csv"
+		importCardsBoard = "board-1"
+		importCardsState = state
+		RunTestCommand(func() {
+			importCardsCmd.Run(importCardsCmd, []string{file})
+		})
+		ResetTestMode()
+		importCardsFrom, importCardsBoard, importCardsState = "", "", ""
+		return result
+	}
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		mock := newMock()
+		importCardsDryRun = true
+		result := run(mock, state)
+		importCardsDryRun = false
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		if len(mock.PostCalls) != 0 {
+			t.Errorf("expected no changes, got %v", mock.PostPaths())
+		}
+		if _, err := os.Stat(state); !os.IsNotExist(err) {
+			t.Error("expected no state file")
+		}
+		data := result.Response.Data.(map[string]interface{})
+		if columns := data["new_columns"].([]string); !reflect.DeepEqual(columns, []string{"Review"}) {
+			t.Errorf("expected Review to be created, got %v", columns)
+		}
+		plan := data["cards"].([]interface{})[0].(map[string]interface{})
+		if plan["action"] != "create" || plan["steps"] != 1 || plan["column"] != "Doing" {
+			t.Errorf("unexpected plan %v", plan)
+		}
+	})
+
+	t.Run("imports and resumes", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+
+		// Fail on the second card's column
+		mock := newMock()
+		mock.PostErrors = map[string]error{"/boards/board-1/columns.json": errors.NewError("Server error")}
+		result := run(mock, state)
+		if result.ExitCode != errors.ExitError {
+			t.Fatalf("expected the first run to fail, got %d", result.ExitCode)
+		}
+		want := []string{
+			"/cards.json",
+			"/cards/123/taggings.json",
+			"/cards/123/steps.json",
+			"/cards/123/triage.json",
+			"/boards/board-1/columns.json",
+		}
+		if got := mock.PostPaths(); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("posted to\n%v\nwant\n%v", got, want)
+		}
+		card := mock.PostCalls[0].Body.(map[string]interface{})
+		params := card["card"].(map[string]interface{})
+		if card["board_id"] != "board-1" || params["created_at"] != "2024-01-01T00:00:00Z" || params["description"] != "<p>Hello</p>" {
+			t.Errorf("unexpected card %v", card)
+		}
+		if column := mock.PostCalls[3].Body.(map[string]interface{}); column["column_id"] != "col-1" {
+			t.Errorf("expected the card to move to the existing column, got %v", column)
+		}
+
+		mock = newMock()
+		result = run(mock, state)
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		want = []string{
+			"/boards/board-1/columns.json",
+			"/cards.json",
+			"/cards/123/triage.json",
+			"/cards/123/closure.json",
+		}
+		if got := mock.PostPaths(); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("posted to\n%v\nwant\n%v", got, want)
+		}
+		data := result.Response.Data.(map[string]interface{})
+		cards := data["cards"].([]interface{})
+		if cards[0].(map[string]interface{})["action"] != "skipped" || cards[1].(map[string]interface{})["action"] != "created" {
+			t.Errorf("unexpected results %v", cards)
+		}
+		if data["resumed"] != true || !reflect.DeepEqual(data["new_columns"], []string{"Review"}) {
+			t.Errorf("unexpected result %v", data)
+		}
+	})
+
+	t.Run("rejects an unknown source", func(t *testing.T) {
+		result := SetTestMode(newMock())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		importCardsFrom = "jira"
+		defer func() { importCardsFrom = "" }()
+
+		RunTestCommand(func() {
+			importCardsCmd.Run(importCardsCmd, []string{file})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// writeAccountArchive exports newAccountMock to an archive.
+func writeAccountArchive(t *testing.T) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := exportAccount(newAccountMock(), "source", out, 1); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// newImportMock returns a mock target account with a user matching the
+// archived one.
+func newImportMock() *MockClient {
+	mock := NewMockClient()
+	mock.GetWithPaginationResponses = map[string]*client.APIResponse{
+		"/users.json": {StatusCode: 200, Data: []interface{}{map[string]interface{}{"id": "u9", "email_address": "ANN@example.com"}}},
+		"/tags.json":  {StatusCode: 200, Data: []interface{}{map[string]interface{}{"id": "t9", "title": "bug"}}},
+	}
+	mock.GetResponses = map[string]*client.APIResponse{
+		"/cards/123/comments/123/reactions.json": {StatusCode: 200, Data: []interface{}{map[string]interface{}{"id": "r9", "content": "👍"}}},
+	}
+	mock.UploadFileResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data:       map[string]interface{}{"signed_id": "signed-new", "attachable_sgid": "sgid-new"},
+	}
+	return mock
+}
+
+func TestImportAccount(t *testing.T) {
+	archive := writeAccountArchive(t)
+
+	t.Run("recreates the archive and maps IDs", func(t *testing.T) {
+		mock := newImportMock()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		importAccountState = filepath.Join(t.TempDir(), "state.json")
+		defer func() { importAccountState = "" }()
+
+		RunTestCommand(func() {
+			importAccountCmd.Run(importAccountCmd, []string{archive})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+
+		want := []string{
+			"/boards.json",
+			"/boards/123/columns.json",
+			"/cards.json", // card 3
+			"/cards/123/closure.json",
+			"/cards.json", // card 7
+			"/cards/123/taggings.json",
+			"/cards/123/assignments.json",
+			"/cards/123/steps.json",
+			"/cards/123/comments.json",
+			"/cards/123/comments/123/reactions.json",
+			"/cards/123/triage.json",
+		}
+		if got := mock.PostPaths(); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("posted to\n%v\nwant\n%v", got, want)
+		}
+
+		card := mock.PostCalls[4].Body.(map[string]interface{})["card"].(map[string]interface{})
+		if card["created_at"] != "2024-01-01T00:00:00Z" {
+			t.Errorf("expected created_at to be kept, got %v", card["created_at"])
+		}
+		if card["description"] != `See <action-text-attachment sgid="sgid-new"></action-text-attachment>` {
+			t.Errorf("expected the attachment to point at the new upload, got %v", card["description"])
+		}
+		if len(mock.UploadFileCalls) != 1 || filepath.Base(mock.UploadFileCalls[0]) != "a.png" {
+			t.Errorf("expected a.png to be uploaded once, got %v", mock.UploadFileCalls)
+		}
+		if body := mock.PostCalls[6].Body.(map[string]interface{}); body["assignee_id"] != "u9" {
+			t.Errorf("expected the assignee to be matched by email, got %v", body)
+		}
+		column := mock.PostCalls[10].Body.(map[string]interface{})
+		if column["column_id"] != "123" {
+			t.Errorf("expected the card to move to the new column, got %v", column)
+		}
+
+		data := result.Response.Data.(map[string]interface{})
+		ids := data["ids"].(map[string]map[string]string)
+		if ids["cards"]["7"] != "123" || ids["users"]["u1"] != "u9" || ids["tags"]["t1"] != "t9" || ids["reactions"]["r1"] != "r9" {
+			t.Errorf("unexpected mapping %v", ids)
+		}
+		if data["resumed"] != false {
+			t.Error("expected a fresh import")
+		}
+	})
+
+	t.Run("resumes from the state file", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		importAccountState = state
+		defer func() { importAccountState = "" }()
+
+		// Fail partway through the second card
+		mock := newImportMock()
+		mock.PostErrors = map[string]error{"/cards/123/comments.json": errors.NewError("Server error")}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		RunTestCommand(func() {
+			importAccountCmd.Run(importAccountCmd, []string{archive})
+		})
+		ResetTestMode()
+		if result.ExitCode != errors.ExitError {
+			t.Fatalf("expected the first run to fail, got %d", result.ExitCode)
+		}
+
+		// The card already has the tag and assignee from the first run
+		mock = newImportMock()
+		mock.GetResponses["/cards/123.json"] = &client.APIResponse{StatusCode: 200, Data: map[string]interface{}{
+			"tags":      []interface{}{"bug"},
+			"assignees": []interface{}{map[string]interface{}{"id": "u9"}},
+		}}
+		result = SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		RunTestCommand(func() {
+			importAccountCmd.Run(importAccountCmd, []string{archive})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		want := []string{
+			"/cards/123/comments.json",
+			"/cards/123/comments/123/reactions.json",
+			"/cards/123/triage.json",
+		}
+		if got := mock.PostPaths(); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("posted to\n%v\nwant\n%v", got, want)
+		}
+		if result.Response.Data.(map[string]interface{})["resumed"] != true {
+			t.Error("expected the import to be resumed")
+		}
+	})
+
+	t.Run("fails when a new reaction can't be found", func(t *testing.T) {
+		mock := newImportMock()
+		mock.GetResponses["/cards/123/comments/123/reactions.json"] = &client.APIResponse{StatusCode: 200, Data: []interface{}{}}
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		importAccountState = filepath.Join(t.TempDir(), "state.json")
+		defer func() { importAccountState = "" }()
+
+		RunTestCommand(func() {
+			importAccountCmd.Run(importAccountCmd, []string{archive})
+		})
+
+		if result.ExitCode == 0 {
+			t.Fatal("expected the import to fail")
+		}
+		if !strings.Contains(result.Response.Error.Message, "Could not find the new 👍 reaction") {
+			t.Errorf("unexpected error %q", result.Response.Error.Message)
+		}
+	})
+
+	t.Run("refuses a state file from another import", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(state, []byte(`{"source":"other@2024-01-01T00:00:00Z","account":"account"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		importAccountState = state
+		defer func() { importAccountState = "" }()
+
+		result := SetTestMode(newImportMock())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		RunTestCommand(func() {
+			importAccountCmd.Run(importAccountCmd, []string{archive})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+		if !strings.Contains(result.Response.Error.Message, "belongs to an import of other@") {
+			t.Errorf("unexpected error %q", result.Response.Error.Message)
+		}
+	})
+}
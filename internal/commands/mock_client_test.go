@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"context"
+	"io"
+	"sync"
+
 	"github.com/robzolkos/fizzy-cli/internal/client"
 	"github.com/robzolkos/fizzy-cli/internal/errors"
 )
@@ -27,6 +31,25 @@ type MockClient struct {
 	FollowLocationError    error
 	UploadFileError        error
 
+	// UploadFileErrors fails uploads of specific paths.
+	UploadFileErrors map[string]error
+
+	// GetResponses overrides GetResponse for specific paths.
+	GetResponses map[string]*client.APIResponse
+
+	// GetErrors fails gets of specific paths.
+	GetErrors map[string]error
+
+	// GetWithPaginationResponses overrides GetWithPaginationResponse for
+	// specific paths.
+	GetWithPaginationResponses map[string]*client.APIResponse
+
+	// PostErrors fails posts to specific paths.
+	PostErrors map[string]error
+
+	// Downloads holds the content served for each URL by Download.
+	Downloads map[string]string
+
 	// Captured calls for verification
 	GetCalls               []MockCall
 	PostCalls              []MockCall
@@ -36,6 +59,11 @@ type MockClient struct {
 	GetWithPaginationCalls []MockCall
 	FollowLocationCalls    []string
 	UploadFileCalls        []string
+	UploadFileContentTypes []string
+	DownloadCalls          []string
+
+	// mu guards calls made concurrently, such as uploads and bulk actions.
+	mu sync.Mutex
 }
 
 // MockCall represents a captured API call.
@@ -84,15 +112,28 @@ func NewMockClient() *MockClient {
 }
 
 func (m *MockClient) Get(path string) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetCalls = append(m.GetCalls, MockCall{Path: path})
+	if err := m.GetErrors[path]; err != nil {
+		return nil, err
+	}
 	if m.GetError != nil {
 		return nil, m.GetError
 	}
+	if resp, ok := m.GetResponses[path]; ok {
+		return resp, nil
+	}
 	return m.GetResponse, nil
 }
 
 func (m *MockClient) Post(path string, body interface{}) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.PostCalls = append(m.PostCalls, MockCall{Path: path, Body: body})
+	if err := m.PostErrors[path]; err != nil {
+		return nil, err
+	}
 	if m.PostError != nil {
 		return nil, m.PostError
 	}
@@ -100,6 +141,8 @@ func (m *MockClient) Post(path string, body interface{}) (*client.APIResponse, e
 }
 
 func (m *MockClient) Patch(path string, body interface{}) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.PatchCalls = append(m.PatchCalls, MockCall{Path: path, Body: body})
 	if m.PatchError != nil {
 		return nil, m.PatchError
@@ -108,6 +151,8 @@ func (m *MockClient) Patch(path string, body interface{}) (*client.APIResponse,
 }
 
 func (m *MockClient) Put(path string, body interface{}) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.PutCalls = append(m.PutCalls, MockCall{Path: path, Body: body})
 	if m.PutError != nil {
 		return nil, m.PutError
@@ -116,6 +161,8 @@ func (m *MockClient) Put(path string, body interface{}) (*client.APIResponse, er
 }
 
 func (m *MockClient) Delete(path string) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.DeleteCalls = append(m.DeleteCalls, MockCall{Path: path})
 	if m.DeleteError != nil {
 		return nil, m.DeleteError
@@ -124,14 +171,21 @@ func (m *MockClient) Delete(path string) (*client.APIResponse, error) {
 }
 
 func (m *MockClient) GetWithPagination(path string, fetchAll bool) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetWithPaginationCalls = append(m.GetWithPaginationCalls, MockCall{Path: path, Body: fetchAll})
 	if m.GetWithPaginationError != nil {
 		return nil, m.GetWithPaginationError
 	}
+	if resp, ok := m.GetWithPaginationResponses[path]; ok {
+		return resp, nil
+	}
 	return m.GetWithPaginationResponse, nil
 }
 
 func (m *MockClient) FollowLocation(location string) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.FollowLocationCalls = append(m.FollowLocationCalls, location)
 	if m.FollowLocationError != nil {
 		return nil, m.FollowLocationError
@@ -139,14 +193,58 @@ func (m *MockClient) FollowLocation(location string) (*client.APIResponse, error
 	return m.FollowLocationResponse, nil
 }
 
-func (m *MockClient) UploadFile(filePath string) (*client.APIResponse, error) {
+func (m *MockClient) UploadFile(filePath, contentType string) (*client.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.UploadFileCalls = append(m.UploadFileCalls, filePath)
+	m.UploadFileContentTypes = append(m.UploadFileContentTypes, contentType)
+	if err := m.UploadFileErrors[filePath]; err != nil {
+		return nil, err
+	}
 	if m.UploadFileError != nil {
 		return nil, m.UploadFileError
 	}
 	return m.UploadFileResponse, nil
 }
 
+func (m *MockClient) Download(url string, w io.Writer) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DownloadCalls = append(m.DownloadCalls, url)
+	content, ok := m.Downloads[url]
+	if !ok {
+		return "", errors.NewNotFoundError("Not found")
+	}
+	_, err := io.WriteString(w, content)
+	return "application/octet-stream", err
+}
+
+// The context-aware variants record calls alongside the plain methods.
+
+func (m *MockClient) GetContext(ctx context.Context, path string) (*client.APIResponse, error) {
+	return m.Get(path)
+}
+
+func (m *MockClient) PostContext(ctx context.Context, path string, body interface{}) (*client.APIResponse, error) {
+	return m.Post(path, body)
+}
+
+func (m *MockClient) PatchContext(ctx context.Context, path string, body interface{}) (*client.APIResponse, error) {
+	return m.Patch(path, body)
+}
+
+func (m *MockClient) PutContext(ctx context.Context, path string, body interface{}) (*client.APIResponse, error) {
+	return m.Put(path, body)
+}
+
+func (m *MockClient) DeleteContext(ctx context.Context, path string) (*client.APIResponse, error) {
+	return m.Delete(path)
+}
+
+func (m *MockClient) GetWithPaginationContext(ctx context.Context, path string, fetchAll bool) (*client.APIResponse, error) {
+	return m.GetWithPagination(path, fetchAll)
+}
+
 // Helper functions for creating common responses
 
 // WithGetData sets the data returned by Get calls.
@@ -197,5 +295,14 @@ func (m *MockClient) WithValidationError(message string) *MockClient {
 	return m
 }
 
+// PostPaths returns the paths of the Post calls in the order they were made.
+func (m *MockClient) PostPaths() []string {
+	var paths []string
+	for _, call := range m.PostCalls {
+		paths = append(paths, call.Path)
+	}
+	return paths
+}
+
 // Ensure MockClient implements client.API
 var _ client.API = (*MockClient)(nil)
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"strconv"
+	"time"
 
+	"github.com/robzolkos/fizzy-cli/internal/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -15,43 +17,147 @@ var notificationCmd = &cobra.Command{
 // Notification list flags
 var notificationListPage int
 var notificationListAll bool
+var notificationListUnread bool
+var notificationListBoard string
+var notificationListCard int
+var notificationListSince string
+var notificationListKind string
+var notificationListGroupBy string
 
 var notificationListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List notifications",
-	Long:  "Lists your notifications.",
+	Long: `Lists your notifications, newest first. Each notification includes a
+"kind" inferred from the wording of its body: mention, assignment, comment,
+closed, reopened, moved, added or other.
+
+--unread, --board, --card, --since and --kind select notifications, and
+--group-by card collapses the notifications about each card into one entry
+with counts. --since takes a date (2024-05-01), a time, or an age such as 7d
+or 12h. --board fetches each card to find its board. These are applied to
+the fetched notifications, so they fetch every page unless --page is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
 
 		client := getClient()
+		f := &notificationFilter{unread: notificationListUnread, kind: notificationListKind}
+		if notificationListCard > 0 {
+			f.card = strconv.Itoa(notificationListCard)
+		}
+		if notificationListSince != "" {
+			since, err := parseSince(notificationListSince, time.Now())
+			if err != nil {
+				exitWithError(err)
+			}
+			f.since = since
+		}
+		if f.kind != "" && !validNotificationKind(f.kind) {
+			exitWithError(errors.NewInvalidArgsError("Invalid kind " + f.kind + " (use mention, assignment, comment, closed, reopened, moved, added or other)"))
+		}
+		if notificationListGroupBy != "" && notificationListGroupBy != "card" {
+			exitWithError(errors.NewInvalidArgsError("Invalid group-by " + notificationListGroupBy + " (use card)"))
+		}
+		// Filters and grouping are applied client-side, so they need every
+		// page unless one was asked for
+		fetchAll := notificationListAll
+		if notificationListPage == 0 && (f.active() || notificationListBoard != "" || notificationListGroupBy != "") {
+			fetchAll = true
+		}
+		if notificationListBoard != "" {
+			boardID, err := resolveBoard(client, notificationListBoard)
+			if err != nil {
+				exitWithError(err)
+			}
+			f.boardID = boardID
+		}
+
 		path := "/notifications.json"
 		if notificationListPage > 0 {
 			path += "?page=" + strconv.Itoa(notificationListPage)
 		}
 
-		resp, err := client.GetWithPagination(path, notificationListAll)
-		if err != nil {
-			exitWithError(err)
+		var data interface{}
+		var linkNext string
+		if fetchAll && !f.since.IsZero() {
+			// Newest come first, so pages older than --since aren't needed
+			items, err := listNotificationsSince(client, f.since)
+			if err != nil {
+				exitWithError(err)
+			}
+			data = items
+		} else {
+			resp, err := client.GetWithPagination(path, fetchAll)
+			if err != nil {
+				exitWithError(err)
+			}
+			data, linkNext = resp.Data, resp.LinkNext
 		}
 
-		hasNext := resp.LinkNext != ""
-		printSuccessWithPagination(resp.Data, hasNext, resp.LinkNext)
+		if items, ok := data.([]interface{}); ok {
+			if f.active() {
+				var err error
+				if items, err = f.apply(client, items); err != nil {
+					exitWithError(err)
+				}
+			}
+			if notificationListGroupBy == "card" {
+				data = groupNotificationsByCard(items)
+			} else {
+				data = items
+			}
+		}
+
+		printSuccessWithPagination(data, linkNext != "", linkNext)
 	},
 }
 
+// Notification read flags
+var notificationReadCard int
+
 var notificationReadCmd = &cobra.Command{
-	Use:   "read NOTIFICATION_ID",
+	Use:   "read [NOTIFICATION_ID]",
 	Short: "Mark notification as read",
-	Long:  "Marks a notification as read.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Marks a notification as read.
+
+With --card, marks every unread notification about that card as read
+instead.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
+		switch {
+		case len(args) == 1 && notificationReadCard > 0:
+			exitWithError(errors.NewInvalidArgsError("Give a NOTIFICATION_ID or --card, not both"))
+		case len(args) == 0 && notificationReadCard <= 0:
+			exitWithError(errors.NewInvalidArgsError("Give a NOTIFICATION_ID or --card"))
+		}
 
 		client := getClient()
+		if notificationReadCard > 0 {
+			resp, err := client.GetWithPagination("/notifications.json", true)
+			if err != nil {
+				exitWithError(err)
+			}
+			items, _ := resp.Data.([]interface{})
+			f := &notificationFilter{unread: true, card: strconv.Itoa(notificationReadCard)}
+			if items, err = f.apply(client, items); err != nil {
+				exitWithError(err)
+			}
+			ids := []string{}
+			for _, item := range items {
+				id, _ := item.(map[string]interface{})["id"].(string)
+				if _, err := client.Post("/notifications/"+id+"/read.json", nil); err != nil {
+					exitWithError(err)
+				}
+				ids = append(ids, id)
+			}
+			printSuccess(map[string]interface{}{"card": notificationReadCard, "read": ids})
+			return
+		}
+
 		resp, err := client.Post("/notifications/"+args[0]+"/read.json", nil)
 		if err != nil {
 			exitWithError(err)
@@ -106,9 +212,16 @@ func init() {
 	// List
 	notificationListCmd.Flags().IntVar(&notificationListPage, "page", 0, "Page number")
 	notificationListCmd.Flags().BoolVar(&notificationListAll, "all", false, "Fetch all pages")
+	notificationListCmd.Flags().BoolVar(&notificationListUnread, "unread", false, "Only unread notifications")
+	notificationListCmd.Flags().StringVar(&notificationListBoard, "board", "", "Only notifications about cards on this board (ID or name)")
+	notificationListCmd.Flags().IntVar(&notificationListCard, "card", 0, "Only notifications about this card number")
+	notificationListCmd.Flags().StringVar(&notificationListSince, "since", "", "Only notifications since a date, time or age (e.g. 7d)")
+	notificationListCmd.Flags().StringVar(&notificationListKind, "kind", "", "Only notifications of this kind (mention, assignment, comment, closed, reopened, moved, added, other)")
+	notificationListCmd.Flags().StringVar(&notificationListGroupBy, "group-by", "", "Group notifications (card)")
 	notificationCmd.AddCommand(notificationListCmd)
 
 	// Read/Unread
+	notificationReadCmd.Flags().IntVar(&notificationReadCard, "card", 0, "Mark every unread notification about this card number as read")
 	notificationCmd.AddCommand(notificationReadCmd)
 	notificationCmd.AddCommand(notificationUnreadCmd)
 	notificationCmd.AddCommand(notificationReadAllCmd)
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// notificationKinds are the kinds --kind accepts, in the order they're
+// tried. The API doesn't say what a notification is about, so its kind is
+// inferred from the wording of its body; anything unrecognised is "other".
+var notificationKinds = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"mention", regexp.MustCompile(`\bmentioned you\b`)},
+	{"assignment", regexp.MustCompile(`\bassigned\b`)},
+	{"comment", regexp.MustCompile(`\b(commented|replied)\b`)},
+	{"closed", regexp.MustCompile(`\bclosed\b`)},
+	{"reopened", regexp.MustCompile(`\breopened\b`)},
+	{"moved", regexp.MustCompile(`\bmoved\b`)},
+	{"added", regexp.MustCompile(`\b(added|created|published)\b`)},
+}
+
+// notificationKind infers the kind of a notification from its body. The
+// title is the card's, so its words say nothing about the notification.
+func notificationKind(n map[string]interface{}) string {
+	body, _ := n["body"].(string)
+	body = strings.ToLower(body)
+	for _, kind := range notificationKinds {
+		if kind.re.MatchString(body) {
+			return kind.name
+		}
+	}
+	return "other"
+}
+
+// validNotificationKind reports whether --kind names a known kind.
+func validNotificationKind(name string) bool {
+	if name == "other" {
+		return true
+	}
+	for _, kind := range notificationKinds {
+		if kind.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var sinceAgeRE = regexp.MustCompile(`^(\d+)([mhdw])$`)
+
+// parseSince parses --since: a time, a date, or an age such as 7d or 12h.
+func parseSince(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if m := sinceAgeRE.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, text, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.NewInvalidArgsError("Invalid since " + text + " (use a date like 2024-05-01, a time, or an age like 7d or 12h)")
+}
+
+// listNotificationsSince fetches pages of notifications, newest first,
+// until a page reaches one created before since, so older pages aren't
+// read. A zero since fetches every page.
+func listNotificationsSince(c client.API, since time.Time) ([]interface{}, error) {
+	if since.IsZero() {
+		return listAll(c, "/notifications.json")
+	}
+	var items []interface{}
+	resp, err := c.GetWithPagination("/notifications.json", false)
+	for {
+		if err != nil {
+			return nil, err
+		}
+		page, _ := resp.Data.([]interface{})
+		items = append(items, page...)
+		if resp.LinkNext == "" || reachesTime(page, since) {
+			return items, nil
+		}
+		resp, err = c.Get(resp.LinkNext)
+	}
+}
+
+// reachesTime reports whether a page has a notification created before t.
+func reachesTime(page []interface{}, t time.Time) bool {
+	for _, item := range page {
+		n, _ := item.(map[string]interface{})
+		createdAt, _ := n["created_at"].(string)
+		if at, err := time.Parse(time.RFC3339, createdAt); err == nil && at.Before(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationFilter selects notifications for notification list.
+type notificationFilter struct {
+	unread  bool
+	card    string
+	since   time.Time
+	kind    string
+	boardID string
+
+	// cardBoards caches the board of each card, for the board filter.
+	cardBoards map[string]string
+}
+
+// active reports whether the filter selects anything.
+func (f *notificationFilter) active() bool {
+	return f.unread || f.card != "" || !f.since.IsZero() || f.kind != "" || f.boardID != ""
+}
+
+// apply returns the notifications that match the filter. Matching a board
+// fetches each card the notifications are about.
+func (f *notificationFilter) apply(c client.API, items []interface{}) ([]interface{}, error) {
+	matched := []interface{}{}
+	for _, item := range items {
+		n, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ok, err := f.match(c, n)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, n)
+		}
+	}
+	return matched, nil
+}
+
+func (f *notificationFilter) match(c client.API, n map[string]interface{}) (bool, error) {
+	if read, _ := n["read"].(bool); f.unread && read {
+		return false, nil
+	}
+	card, _ := n["card"].(map[string]interface{})
+	number := ""
+	if card != nil {
+		number = cardNumber(card)
+	}
+	if f.card != "" && number != f.card {
+		return false, nil
+	}
+	if !f.since.IsZero() {
+		createdAt, _ := n["created_at"].(string)
+		t, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil || t.Before(f.since) {
+			return false, nil
+		}
+	}
+	if f.kind != "" && notificationKind(n) != f.kind {
+		return false, nil
+	}
+	if f.boardID != "" {
+		if number == "" {
+			return false, nil
+		}
+		boardID, err := f.cardBoard(c, number)
+		if err != nil {
+			return false, err
+		}
+		if boardID != f.boardID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// cardBoard returns the ID of a card's board, or "" if the card is gone.
+func (f *notificationFilter) cardBoard(c client.API, number string) (string, error) {
+	if id, ok := f.cardBoards[number]; ok {
+		return id, nil
+	}
+	id, err := cardBoardID(c, number)
+	if err != nil {
+		if cliErr, ok := err.(*errors.CLIError); !ok || cliErr.Code != "NOT_FOUND" {
+			return "", err
+		}
+	}
+	if f.cardBoards == nil {
+		f.cardBoards = map[string]string{}
+	}
+	f.cardBoards[number] = id
+	return id, nil
+}
+
+// groupNotificationsByCard collapses notifications about the same card into
+// one entry, in the order each card first appears.
+func groupNotificationsByCard(items []interface{}) []interface{} {
+	groups := []interface{}{}
+	byCard := map[string]map[string]interface{}{}
+	for _, item := range items {
+		n, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		card, _ := n["card"].(map[string]interface{})
+		key := ""
+		if card != nil {
+			key = cardNumber(card)
+		}
+		group, ok := byCard[key]
+		if !ok || key == "" {
+			group = map[string]interface{}{
+				"card":             n["card"],
+				"count":            0,
+				"unread":           0,
+				"kinds":            map[string]int{},
+				"latest_at":        n["created_at"],
+				"notification_ids": []string{},
+			}
+			byCard[key] = group
+			groups = append(groups, group)
+		}
+		group["count"] = group["count"].(int) + 1
+		if read, _ := n["read"].(bool); !read {
+			group["unread"] = group["unread"].(int) + 1
+		}
+		group["kinds"].(map[string]int)[notificationKind(n)]++
+		if createdAt, _ := n["created_at"].(string); laterTime(createdAt, group["latest_at"]) {
+			group["latest_at"] = createdAt
+		}
+		id, _ := n["id"].(string)
+		group["notification_ids"] = append(group["notification_ids"].([]string), id)
+	}
+	return groups
+}
+
+// laterTime reports whether timestamp a is after timestamp b.
+func laterTime(a string, b interface{}) bool {
+	bs, _ := b.(string)
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	tb, err := time.Parse(time.RFC3339, bs)
+	return err != nil || ta.After(tb)
+}
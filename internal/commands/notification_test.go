@@ -1,9 +1,16 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
 )
 
 func TestNotificationList(t *testing.T) {
@@ -33,6 +40,202 @@ func TestNotificationList(t *testing.T) {
 	})
 }
 
+func TestNotificationListFilters(t *testing.T) {
+	notification := func(id string, card float64, body, createdAt string, read bool) map[string]interface{} {
+		return map[string]interface{}{"id": id, "body": body, "created_at": createdAt, "read": read, "card": map[string]interface{}{"number": card}}
+	}
+	newMock := func() *MockClient {
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			notification("n4", 2, "Ann mentioned you", "2024-01-04T00:00:00Z", false),
+			notification("n3", 1, "Bob commented on Fix login", "2024-01-03T00:00:00Z", false),
+			notification("n2", 1, "Ann assigned you", "2024-01-02T00:00:00Z", true),
+			notification("n1", 1, "Bob commented on Fix login", "2024-01-01T00:00:00Z", false),
+		}}
+		mock.GetResponses = map[string]*client.APIResponse{
+			"/cards/1.json": {StatusCode: 200, Data: map[string]interface{}{"number": float64(1), "board": map[string]interface{}{"id": "board-1"}}},
+			"/cards/2.json": {StatusCode: 200, Data: map[string]interface{}{"number": float64(2), "board": map[string]interface{}{"id": "board-2"}}},
+		}
+		return mock
+	}
+	ids := func(data interface{}) string {
+		var ids []string
+		for _, item := range data.([]interface{}) {
+			ids = append(ids, item.(map[string]interface{})["id"].(string))
+		}
+		return strings.Join(ids, " ")
+	}
+	reset := func() {
+		notificationListAll, notificationListUnread, notificationListBoard, notificationListCard = false, false, "", 0
+		notificationListSince, notificationListKind, notificationListGroupBy = "", "", ""
+	}
+
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	t.Run("leaves kinds out of plain lists", func(t *testing.T) {
+		result := SetTestMode(newMock())
+		RunTestCommand(func() {
+			notificationListCmd.Run(notificationListCmd, []string{})
+		})
+		for _, item := range result.Response.Data.([]interface{}) {
+			if kind, ok := item.(map[string]interface{})["kind"]; ok {
+				t.Errorf("expected no kind, got %v", kind)
+			}
+		}
+	})
+
+	t.Run("infers kinds from the body only", func(t *testing.T) {
+		n := map[string]interface{}{"title": "Closed tickets get reopened", "body": "Ann did something new"}
+		if kind := notificationKind(n); kind != "other" {
+			t.Errorf("expected other, got %q", kind)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		defer reset()
+		for _, tc := range []struct {
+			name string
+			set  func()
+			want string
+		}{
+			{"unread", func() { notificationListUnread = true }, "n4 n3 n1"},
+			{"card", func() { notificationListCard = 1 }, "n3 n2 n1"},
+			{"since", func() { notificationListSince = "2024-01-02T00:00:00Z" }, "n4 n3 n2"},
+			{"kind", func() { notificationListKind = "comment" }, "n3 n1"},
+			{"board", func() { notificationListBoard = "board-2" }, "n4"},
+			{"combined", func() { notificationListUnread, notificationListKind, notificationListCard = true, "comment", 1 }, "n3 n1"},
+		} {
+			reset()
+			notificationListAll = true
+			tc.set()
+			result := SetTestMode(newMock())
+			RunTestCommand(func() {
+				notificationListCmd.Run(notificationListCmd, []string{})
+			})
+			if result.ExitCode != 0 {
+				t.Fatalf("%s: expected exit code 0, got %d (%v)", tc.name, result.ExitCode, result.Response.Error)
+			}
+			if got := ids(result.Response.Data); got != tc.want {
+				t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("groups by card", func(t *testing.T) {
+		defer reset()
+		notificationListAll, notificationListGroupBy = true, "card"
+		result := SetTestMode(newMock())
+		RunTestCommand(func() {
+			notificationListCmd.Run(notificationListCmd, []string{})
+		})
+
+		groups := result.Response.Data.([]interface{})
+		if len(groups) != 2 {
+			t.Fatalf("expected 2 groups, got %d", len(groups))
+		}
+		group := groups[1].(map[string]interface{})
+		if group["count"] != 3 || group["unread"] != 2 || group["latest_at"] != "2024-01-03T00:00:00Z" {
+			t.Errorf("unexpected group %v", group)
+		}
+		if kinds := group["kinds"].(map[string]int); kinds["comment"] != 2 || kinds["assignment"] != 1 {
+			t.Errorf("unexpected kinds %v", kinds)
+		}
+		if got := strings.Join(group["notification_ids"].([]string), " "); got != "n3 n2 n1" {
+			t.Errorf("unexpected notification IDs %q", got)
+		}
+	})
+
+	t.Run("filters and grouping fetch every page", func(t *testing.T) {
+		for _, set := range []func(){
+			func() { notificationListUnread = true },
+			func() { notificationListBoard = "board-2" },
+			func() { notificationListGroupBy = "card" },
+		} {
+			reset()
+			set()
+			mock := newMock()
+			result := SetTestMode(mock)
+			RunTestCommand(func() {
+				notificationListCmd.Run(notificationListCmd, []string{})
+			})
+			if result.ExitCode != 0 {
+				t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+			}
+			if call := mock.GetWithPaginationCalls[0]; call.Path != "/notifications.json" || call.Body != true {
+				t.Errorf("expected every page to be fetched, got %v", call)
+			}
+		}
+		reset()
+	})
+
+	t.Run("stops paging at --since", func(t *testing.T) {
+		defer reset()
+		notificationListSince = "2024-01-03T00:00:00Z"
+		mock := newMock()
+		page := mock.GetWithPaginationResponse.Data.([]interface{})
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, LinkNext: "/notifications.json?page=2", Data: page[:2]}
+		mock.GetResponses["/notifications.json?page=2"] = &client.APIResponse{StatusCode: 200, LinkNext: "/notifications.json?page=3", Data: page[2:]}
+		result := SetTestMode(mock)
+		RunTestCommand(func() {
+			notificationListCmd.Run(notificationListCmd, []string{})
+		})
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		if got := ids(result.Response.Data); got != "n4 n3" {
+			t.Errorf("expected n4 and n3, got %q", got)
+		}
+		if len(mock.GetCalls) != 1 || mock.GetCalls[0].Path != "/notifications.json?page=2" {
+			t.Errorf("expected to stop after page 2, got %v", mock.GetCalls)
+		}
+	})
+
+	t.Run("filters a single page with --page", func(t *testing.T) {
+		defer func() { notificationListPage = 0 }()
+		defer reset()
+		notificationListUnread, notificationListPage = true, 2
+		mock := newMock()
+		SetTestMode(mock)
+		RunTestCommand(func() {
+			notificationListCmd.Run(notificationListCmd, []string{})
+		})
+		if call := mock.GetWithPaginationCalls[0]; call.Path != "/notifications.json?page=2" || call.Body != false {
+			t.Errorf("expected only page 2 to be fetched, got %v", call)
+		}
+	})
+
+	t.Run("rejects an unknown kind", func(t *testing.T) {
+		defer reset()
+		notificationListAll, notificationListKind = true, "party"
+		result := SetTestMode(newMock())
+		RunTestCommand(func() {
+			notificationListCmd.Run(notificationListCmd, []string{})
+		})
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for text, want := range map[string]time.Time{
+		"7d":                   time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC),
+		"12h":                  time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+		"2024-05-01":           time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-01T08:00:00Z": time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+	} {
+		got, err := parseSince(text, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", text, got, err, want)
+		}
+	}
+	if _, err := parseSince("last week", now); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
 func TestNotificationRead(t *testing.T) {
 	t.Run("marks notification as read", func(t *testing.T) {
 		mock := NewMockClient()
@@ -56,6 +259,49 @@ func TestNotificationRead(t *testing.T) {
 			t.Errorf("expected path '/notifications/notif-1/read.json', got '%s'", mock.PostCalls[0].Path)
 		}
 	})
+	t.Run("marks the unread notifications about a card as read", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			map[string]interface{}{"id": "n3", "read": false, "card": map[string]interface{}{"number": float64(1)}},
+			map[string]interface{}{"id": "n2", "read": false, "card": map[string]interface{}{"number": float64(2)}},
+			map[string]interface{}{"id": "n1", "read": true, "card": map[string]interface{}{"number": float64(1)}},
+		}}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		notificationReadCard = 1
+		defer func() { notificationReadCard = 0 }()
+
+		RunTestCommand(func() {
+			notificationReadCmd.Run(notificationReadCmd, []string{})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d (%v)", result.ExitCode, result.Response.Error)
+		}
+		if got := mock.PostPaths(); strings.Join(got, " ") != "/notifications/n3/read.json" {
+			t.Errorf("expected only n3 to be marked read, got %v", got)
+		}
+		data := result.Response.Data.(map[string]interface{})
+		if data["card"] != 1 || len(data["read"].([]string)) != 1 {
+			t.Errorf("unexpected result %v", data)
+		}
+	})
+
+	t.Run("requires an ID or --card", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			notificationReadCmd.Run(notificationReadCmd, []string{})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
 }
 
 func TestNotificationUnread(t *testing.T) {
@@ -107,3 +353,275 @@ func TestNotificationReadAll(t *testing.T) {
 		}
 	})
 }
+
+func TestNotificationWatch(t *testing.T) {
+	notification := func(id, createdAt string, read bool) map[string]interface{} {
+		return map[string]interface{}{"id": id, "created_at": createdAt, "read": read, "card": map[string]interface{}{"number": float64(7)}}
+	}
+	// lines returns the IDs of the notifications printed as NDJSON.
+	lines := func(t *testing.T, out *bytes.Buffer) []string {
+		t.Helper()
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var n map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &n); err != nil {
+				t.Fatalf("expected a JSON line, got %q", line)
+			}
+			ids = append(ids, n["id"].(string))
+		}
+		out.Reset()
+		return ids
+	}
+
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	t.Run("prints new unread notifications oldest first", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			notification("n3", "2024-01-03T00:00:00.000Z", false),
+			notification("n2", "2024-01-02T00:00:00Z", true),
+			notification("n1", "2024-01-01T00:00:00Z", false),
+		}}
+		var out bytes.Buffer
+		w, err := newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n1 n3" {
+			t.Errorf("expected n1 and n3, got %v", ids)
+		}
+
+		// Polling again prints only what's new, as does a new watch
+		mock.GetWithPaginationResponse.Data = append([]interface{}{notification("n4", "2024-01-03T00:00:00Z", false)}, mock.GetWithPaginationResponse.Data.([]interface{})...)
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n4" {
+			t.Errorf("expected n4, got %v", ids)
+		}
+		w, err = newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); len(ids) != 0 {
+			t.Errorf("expected nothing new after restarting, got %v", ids)
+		}
+		if len(mock.PostCalls) != 0 {
+			t.Errorf("expected nothing to be marked read, got %v", mock.PostPaths())
+		}
+	})
+
+	t.Run("runs the command and marks notifications read", func(t *testing.T) {
+		var inputs []string
+		var envs [][]string
+		defer func(run func(string, []byte, []string) error) { runNotificationCommand = run }(runNotificationCommand)
+		runNotificationCommand = func(command string, input []byte, env []string) error {
+			inputs = append(inputs, string(input))
+			envs = append(envs, env)
+			if strings.Contains(string(input), `"id":"n2"`) {
+				return errors.NewError("exit status 1")
+			}
+			return nil
+		}
+
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			notification("n2", "2024-01-02T00:00:00Z", false),
+			notification("n1", "2024-01-01T00:00:00Z", false),
+		}}
+		var out bytes.Buffer
+		w, err := newNotificationWatch(mock, filepath.Join(t.TempDir(), "state.json"), &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.exec = "notify-send fizzy"
+		w.markRead = true
+		err = w.poll()
+		if cliErr, ok := err.(*errors.CLIError); !ok || cliErr.Code != "COMMAND_FAILED" || !transientError(err) {
+			t.Fatalf("expected the failed command to stop the poll, got %v", err)
+		}
+
+		if len(inputs) != 2 || !strings.Contains(inputs[0], `"id":"n1"`) {
+			t.Errorf("expected the notifications on stdin, got %v", inputs)
+		}
+		if strings.Join(envs[0], " ") != "FIZZY_NOTIFICATION_ID=n1 FIZZY_CARD_NUMBER=7" {
+			t.Errorf("unexpected environment %v", envs[0])
+		}
+		// The failed command leaves n2 unread and unprinted
+		if paths := mock.PostPaths(); strings.Join(paths, " ") != "/notifications/n1/read.json" {
+			t.Errorf("expected only n1 to be marked read, got %v", paths)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n1" {
+			t.Errorf("expected only n1 to be printed, got %v", ids)
+		}
+	})
+
+	t.Run("tries a failed command again on the next poll", func(t *testing.T) {
+		calls := 0
+		defer func(run func(string, []byte, []string) error) { runNotificationCommand = run }(runNotificationCommand)
+		runNotificationCommand = func(command string, input []byte, env []string) error {
+			calls++
+			if calls == 1 {
+				return errors.NewError("exit status 1")
+			}
+			return nil
+		}
+
+		state := filepath.Join(t.TempDir(), "state.json")
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			notification("n1", "2024-01-01T00:00:00Z", false),
+		}}
+		var out bytes.Buffer
+		w, err := newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.exec = "notify-send fizzy"
+		if err := w.poll(); err == nil {
+			t.Fatal("expected the first poll to fail")
+		}
+		if ids := lines(t, &out); len(ids) != 0 {
+			t.Errorf("expected nothing printed, got %v", ids)
+		}
+
+		// A restarted watch still has n1 to do
+		w, err = newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.exec = "notify-send fizzy"
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n1" {
+			t.Errorf("expected n1 on the retry, got %v", ids)
+		}
+		if calls != 2 {
+			t.Errorf("expected the command to run twice, ran %d times", calls)
+		}
+	})
+
+	t.Run("tries only the read again when marking read fails", func(t *testing.T) {
+		calls := 0
+		defer func(run func(string, []byte, []string) error) { runNotificationCommand = run }(runNotificationCommand)
+		runNotificationCommand = func(command string, input []byte, env []string) error {
+			calls++
+			return nil
+		}
+
+		state := filepath.Join(t.TempDir(), "state.json")
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, Data: []interface{}{
+			notification("n1", "2024-01-01T00:00:00Z", false),
+		}}
+		mock.PostErrors = map[string]error{"/notifications/n1/read.json": errors.NewNetworkError("connection reset")}
+		var out bytes.Buffer
+		w, err := newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.exec = "notify-send fizzy"
+		w.markRead = true
+		err = w.poll()
+		if cliErr, ok := err.(*errors.CLIError); !ok || cliErr.Code != "READ_FAILED" || !transientError(err) {
+			t.Fatalf("expected the failed read to be reported, got %v", err)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n1" {
+			t.Errorf("expected n1 to be printed, got %v", ids)
+		}
+
+		// A restarted watch marks n1 read without handling it again
+		mock.PostErrors = nil
+		mock.PostCalls = nil
+		w, err = newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.exec = "notify-send fizzy"
+		w.markRead = true
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); len(ids) != 0 {
+			t.Errorf("expected nothing printed again, got %v", ids)
+		}
+		if calls != 1 {
+			t.Errorf("expected the command to run once, ran %d times", calls)
+		}
+		if paths := mock.PostPaths(); strings.Join(paths, " ") != "/notifications/n1/read.json" {
+			t.Errorf("expected n1 to be marked read, got %v", paths)
+		}
+		if len(w.cursor.Unread) != 0 {
+			t.Errorf("expected no reads left, got %v", w.cursor.Unread)
+		}
+	})
+
+	t.Run("reads pages back to the cursor", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(state, []byte(`{"account":"account","seen_at":"2024-01-01T00:00:00Z","seen_ids":["n1"]}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{StatusCode: 200, LinkNext: "/notifications.json?page=2", Data: []interface{}{
+			notification("n4", "2024-01-04T00:00:00Z", false),
+			notification("n3", "2024-01-03T00:00:00Z", false),
+		}}
+		mock.GetResponses = map[string]*client.APIResponse{
+			"/notifications.json?page=2": {StatusCode: 200, LinkNext: "/notifications.json?page=3", Data: []interface{}{
+				notification("n2", "2024-01-02T00:00:00Z", false),
+				notification("n1", "2024-01-01T00:00:00Z", false),
+			}},
+		}
+		var out bytes.Buffer
+		w, err := newNotificationWatch(mock, state, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.poll(); err != nil {
+			t.Fatal(err)
+		}
+		if ids := lines(t, &out); strings.Join(ids, " ") != "n2 n3 n4" {
+			t.Errorf("expected n2, n3 and n4, got %v", ids)
+		}
+		if len(mock.GetCalls) != 1 {
+			t.Errorf("expected to stop at the page with the cursor, got %v", mock.GetCalls)
+		}
+	})
+
+	t.Run("refuses a state file for another account", func(t *testing.T) {
+		state := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(state, []byte(`{"account":"other"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newNotificationWatch(NewMockClient(), state, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "belongs to account other") {
+			t.Errorf("expected an error about the account, got %v", err)
+		}
+	})
+
+	t.Run("rejects an invalid interval", func(t *testing.T) {
+		result := SetTestMode(NewMockClient())
+		SetTestConfig("token", "account", "https://api.example.com")
+		notificationWatchInterval = "soon"
+		defer func() { notificationWatchInterval = "30s" }()
+
+		RunTestCommand(func() {
+			notificationWatchCmd.Run(notificationWatchCmd, []string{})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/config"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// Notification watch flags
+var notificationWatchInterval string
+var notificationWatchState string
+var notificationWatchMarkRead bool
+var notificationWatchExec string
+var notificationWatchOnce bool
+
+var notificationWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print new notifications as they arrive",
+	Long: `Polls your notifications and prints each new unread one as a line of JSON
+(NDJSON), oldest first, until interrupted with Ctrl-C.
+
+The last notification seen is recorded in a state file, so a later watch
+carries on from there, reading back through as many pages as it needs; the
+first watch prints the unread notifications on the first page. Use --once to
+poll a single time and exit, e.g. from a status bar or cron job.
+
+With --exec, the command is run through the shell for each notification,
+with the notification's JSON on stdin and its ID and card number in
+FIZZY_NOTIFICATION_ID and FIZZY_CARD_NUMBER. A notification is printed once
+its command succeeds; if the command fails, the poll stops there and the
+notification is tried again on the next one. With --mark-read, each
+notification is marked as read once it has been printed; if that fails, only
+the read is tried again on the next poll.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := requireAuthAndAccount(); err != nil {
+			exitWithError(err)
+		}
+		interval, err := time.ParseDuration(notificationWatchInterval)
+		if err != nil || interval <= 0 {
+			exitWithError(errors.NewInvalidArgsError("Invalid interval " + notificationWatchInterval + " (use a duration like 30s or 2m)"))
+		}
+
+		statePath := notificationWatchState
+		if statePath == "" {
+			statePath = filepath.Join(config.ConfigDir(), "notification-watch-"+cfg.Account+".json")
+		}
+		w, err := newNotificationWatch(getClient(), statePath, os.Stdout)
+		if err != nil {
+			exitWithError(err)
+		}
+		w.markRead = notificationWatchMarkRead
+		w.exec = notificationWatchExec
+
+		ctx := commandContext()
+		for {
+			if err := w.poll(); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if notificationWatchOnce || !transientError(err) {
+					exitWithError(err)
+				}
+				fmt.Fprintf(os.Stderr, "Polling notifications failed, trying again in %s: %s\n", interval, err)
+			}
+			if notificationWatchOnce {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+// notificationCursor is the state of a watch: the creation time of the
+// newest notification seen, the IDs of the notifications created then, and
+// the notifications handled but not yet marked read.
+type notificationCursor struct {
+	Account string    `json:"account"`
+	SeenAt  time.Time `json:"seen_at"`
+	SeenIDs []string  `json:"seen_ids"`
+	Unread  []string  `json:"unread,omitempty"`
+}
+
+// notificationWatch prints new notifications, keeping its cursor in a
+// state file.
+type notificationWatch struct {
+	c         client.API
+	statePath string
+	out       io.Writer
+	markRead  bool
+	exec      string
+
+	cursor notificationCursor
+}
+
+// newNotificationWatch loads the watch's state file, if there is one.
+func newNotificationWatch(c client.API, statePath string, out io.Writer) (*notificationWatch, error) {
+	w := &notificationWatch{c: c, statePath: statePath, out: out, cursor: notificationCursor{Account: cfg.Account}}
+	data, err := os.ReadFile(statePath)
+	switch {
+	case os.IsNotExist(err):
+		return w, nil
+	case err != nil:
+		return nil, errors.NewError("Failed to read state file: " + err.Error())
+	}
+	if err := json.Unmarshal(data, &w.cursor); err != nil {
+		return nil, errors.NewError("State file " + statePath + " is damaged: " + err.Error())
+	}
+	if w.cursor.Account != cfg.Account {
+		return nil, errors.NewInvalidArgsError("State file " + statePath + " belongs to account " + w.cursor.Account + "; use --state to choose another")
+	}
+	return w, nil
+}
+
+// poll fetches the notifications newer than the cursor and handles them,
+// oldest first. It stops at the first one that fails, leaving the cursor
+// before it so the next poll tries it again. With --mark-read, the
+// notifications handled are marked read once the cursor has passed them.
+func (w *notificationWatch) poll() error {
+	items, err := w.list()
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		data      map[string]interface{}
+		id        string
+		createdAt time.Time
+	}
+	var entries []entry
+	for _, item := range items {
+		n, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := n["id"].(string)
+		createdAt, _ := n["created_at"].(string)
+		t, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil || !w.isNew(id, t) {
+			continue
+		}
+		entries = append(entries, entry{data: n, id: id, createdAt: t})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].createdAt.Before(entries[j].createdAt)
+	})
+
+	for _, e := range entries {
+		if read, _ := e.data["read"].(bool); !read {
+			if err := w.handle(e.id, e.data); err != nil {
+				w.markHandledRead()
+				return err
+			}
+			if w.markRead {
+				w.cursor.Unread = append(w.cursor.Unread, e.id)
+			}
+		}
+		if err := w.advance(e.id, e.createdAt); err != nil {
+			return err
+		}
+	}
+	return w.markHandledRead()
+}
+
+// markHandledRead marks the notifications handled so far as read. Those
+// that fail for a passing reason are kept to try again on the next poll,
+// without running their command or printing them again.
+func (w *notificationWatch) markHandledRead() error {
+	if len(w.cursor.Unread) == 0 {
+		return nil
+	}
+	var failed *errors.CLIError
+	var unread []string
+	for _, id := range w.cursor.Unread {
+		if _, err := w.c.Post("/notifications/"+id+"/read.json", nil); err != nil {
+			if transientError(err) {
+				unread = append(unread, id)
+			}
+			if failed == nil {
+				failed = errors.NewError("Failed to mark notification " + id + " as read: " + err.Error())
+				failed.Code = "READ_FAILED"
+			}
+		}
+	}
+	w.cursor.Unread = unread
+	if err := w.save(); err != nil {
+		return err
+	}
+	if failed != nil {
+		return failed
+	}
+	return nil
+}
+
+// list fetches pages of notifications until one reaches the cursor, so
+// none are missed when more than a page arrive between polls. Without a
+// cursor, only the first page is read.
+func (w *notificationWatch) list() ([]interface{}, error) {
+	var items []interface{}
+	resp, err := w.c.GetWithPagination("/notifications.json", false)
+	for {
+		if err != nil {
+			return nil, err
+		}
+		page, _ := resp.Data.([]interface{})
+		items = append(items, page...)
+		if resp.LinkNext == "" || w.cursor.SeenAt.IsZero() || w.reachesCursor(page) {
+			return items, nil
+		}
+		resp, err = w.c.Get(resp.LinkNext)
+	}
+}
+
+// reachesCursor reports whether a page has a notification created at or
+// before the cursor.
+func (w *notificationWatch) reachesCursor(page []interface{}) bool {
+	for _, item := range page {
+		n, _ := item.(map[string]interface{})
+		createdAt, _ := n["created_at"].(string)
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil && !t.After(w.cursor.SeenAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNew reports whether a notification is newer than the cursor.
+func (w *notificationWatch) isNew(id string, createdAt time.Time) bool {
+	if createdAt.After(w.cursor.SeenAt) {
+		return true
+	}
+	if !createdAt.Equal(w.cursor.SeenAt) {
+		return false
+	}
+	for _, seen := range w.cursor.SeenIDs {
+		if seen == id {
+			return false
+		}
+	}
+	return true
+}
+
+// handle runs the --exec command for a notification and prints it.
+func (w *notificationWatch) handle(id string, n map[string]interface{}) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return errors.NewError("Failed to encode notification: " + err.Error())
+	}
+
+	if w.exec != "" {
+		env := []string{"FIZZY_NOTIFICATION_ID=" + id}
+		if card, ok := n["card"].(map[string]interface{}); ok {
+			env = append(env, "FIZZY_CARD_NUMBER="+cardNumber(card))
+		}
+		if err := runNotificationCommand(w.exec, line, env); err != nil {
+			cmdErr := errors.NewError("Command for notification " + id + " failed: " + err.Error())
+			cmdErr.Code = "COMMAND_FAILED"
+			return cmdErr
+		}
+	}
+
+	if _, err := fmt.Fprintf(w.out, "%s\n", line); err != nil {
+		return errors.NewError("Failed to write notification: " + err.Error())
+	}
+	return nil
+}
+
+// advance moves the cursor past a notification and saves it.
+func (w *notificationWatch) advance(id string, createdAt time.Time) error {
+	if createdAt.After(w.cursor.SeenAt) {
+		w.cursor.SeenAt = createdAt
+		w.cursor.SeenIDs = nil
+	}
+	w.cursor.SeenIDs = append(w.cursor.SeenIDs, id)
+	return w.save()
+}
+
+// save writes the cursor, replacing the file in one step.
+func (w *notificationWatch) save() error {
+	data, err := json.MarshalIndent(w.cursor, "", "  ")
+	if err != nil {
+		return errors.NewError("Failed to encode state: " + err.Error())
+	}
+	if err := os.MkdirAll(filepath.Dir(w.statePath), 0o700); err != nil {
+		return errors.NewError("Failed to write state file: " + err.Error())
+	}
+	tmp := w.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return errors.NewError("Failed to write state file: " + err.Error())
+	}
+	if err := os.Rename(tmp, w.statePath); err != nil {
+		return errors.NewError("Failed to write state file: " + err.Error())
+	}
+	return nil
+}
+
+// runNotificationCommand runs a --exec command through the shell with input
+// on stdin (can be overridden for testing).
+var runNotificationCommand = func(command string, input []byte, env []string) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// transientError reports whether a poll may succeed if tried again later:
+// a request failed for a passing reason, a --exec command failed, or a
+// notification couldn't be marked read.
+func transientError(err error) bool {
+	cliErr, ok := err.(*errors.CLIError)
+	if !ok {
+		return false
+	}
+	switch cliErr.Code {
+	case "NETWORK_ERROR", "RATE_LIMITED", "COMMAND_FAILED", "READ_FAILED":
+		return true
+	}
+	return cliErr.Status >= 500
+}
+
+func init() {
+	notificationWatchCmd.Flags().StringVar(&notificationWatchInterval, "interval", "30s", "Time between polls")
+	notificationWatchCmd.Flags().StringVar(&notificationWatchState, "state", "", "State file recording the last notification seen (default in the config directory)")
+	notificationWatchCmd.Flags().BoolVar(&notificationWatchMarkRead, "mark-read", false, "Mark notifications as read once handled")
+	notificationWatchCmd.Flags().StringVar(&notificationWatchExec, "exec", "", "Shell command to run for each notification, with its JSON on stdin")
+	notificationWatchCmd.Flags().BoolVar(&notificationWatchOnce, "once", false, "Poll once and exit")
+	notificationCmd.AddCommand(notificationWatchCmd)
+}
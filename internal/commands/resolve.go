@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// resolveCandidate is a single match returned in an ambiguous-match error.
+type resolveCandidate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// looksLikeID reports whether a value should be used as an ID without a
+// lookup. Fizzy IDs are lowercase and always contain a digit, while names
+// usually contain spaces or capitals, so this avoids a list request for the
+// common case of passing an ID. Other values are looked up, and used as IDs
+// if nothing matches.
+func looksLikeID(value string) bool {
+	hasDigit := false
+	for _, r := range value {
+		switch {
+		case unicode.IsSpace(r), unicode.IsUpper(r):
+			return false
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasDigit
+}
+
+// resolveByName matches value against items by exact ID, then by
+// case-insensitive name, then by unique name prefix. A value that matches
+// nothing is returned as it is, to be used as an ID. nameKeys are tried in
+// order to find an item's display name.
+func resolveByName(kind, value string, items []interface{}, nameKeys ...string) (string, error) {
+	var all []resolveCandidate
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].(string)
+		if id == "" {
+			continue
+		}
+		name := ""
+		for _, key := range nameKeys {
+			if v, ok := m[key].(string); ok && v != "" {
+				name = v
+				break
+			}
+		}
+		all = append(all, resolveCandidate{ID: id, Name: name})
+	}
+
+	for _, c := range all {
+		if c.ID == value {
+			return c.ID, nil
+		}
+	}
+
+	lower := strings.ToLower(value)
+	var exact, prefix []resolveCandidate
+	for _, c := range all {
+		name := strings.ToLower(c.Name)
+		if name == lower {
+			exact = append(exact, c)
+		} else if strings.HasPrefix(name, lower) {
+			prefix = append(prefix, c)
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = prefix
+	}
+	switch len(matches) {
+	case 0:
+		return value, nil
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", errors.NewInvalidArgsError(fmt.Sprintf("%q matches %d %ss; use a longer name or the ID", value, len(matches), kind)).
+			WithDetails(map[string]interface{}{"candidates": matches})
+	}
+}
+
+// resolveBoard returns the board ID for an ID or board name.
+func resolveBoard(c client.API, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || looksLikeID(value) {
+		return value, nil
+	}
+	resp, err := c.GetWithPagination("/boards.json", true)
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.Data.([]interface{})
+	return resolveByName("board", value, items, "name")
+}
+
+// resolveColumn returns the column ID for an ID or column name on a board.
+// Pseudo columns (maybe, not-now, done) are returned unchanged.
+func resolveColumn(c client.API, boardID, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || looksLikeID(value) {
+		return value, nil
+	}
+	if _, ok := parsePseudoColumnID(value); ok {
+		return value, nil
+	}
+	if boardID == "" {
+		return "", errors.NewInvalidArgsError(fmt.Sprintf("Cannot look up column %q without a board. Set --board or pass the column ID", value))
+	}
+	resp, err := c.Get("/boards/" + boardID + "/columns.json")
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.Data.([]interface{})
+	return resolveByName("column", value, items, "name")
+}
+
+// resolveUser returns the user ID for an ID, name, email address or "me".
+func resolveUser(c client.API, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "me") {
+		return currentUserID(c)
+	}
+	if value == "" || looksLikeID(value) {
+		return value, nil
+	}
+	resp, err := c.GetWithPagination("/users.json", true)
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.Data.([]interface{})
+	if strings.Contains(value, "@") {
+		return resolveByName("user", value, items, "email_address")
+	}
+	return resolveByName("user", value, items, "name")
+}
+
+// resolveTag returns the tag ID for an ID or tag title.
+func resolveTag(c client.API, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || looksLikeID(value) {
+		return value, nil
+	}
+	resp, err := c.GetWithPagination("/tags.json", true)
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.Data.([]interface{})
+	return resolveByName("tag", strings.TrimPrefix(value, "#"), items, "title", "name")
+}
+
+// resolveTags resolves a comma-separated list of tag IDs or titles.
+func resolveTags(c client.API, value string) (string, error) {
+	var ids []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := resolveTag(c, part)
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ","), nil
+}
+
+// cardBoardID returns the ID of the board a card belongs to.
+func cardBoardID(c client.API, cardNumber string) (string, error) {
+	resp, err := c.Get("/cards/" + cardNumber + ".json")
+	if err != nil {
+		return "", err
+	}
+	card, _ := resp.Data.(map[string]interface{})
+	if board, ok := card["board"].(map[string]interface{}); ok {
+		if id, ok := board["id"].(string); ok {
+			return id, nil
+		}
+	}
+	if id, ok := card["board_id"].(string); ok {
+		return id, nil
+	}
+	return "", nil
+}
+
+// currentUserID returns the authenticated user's ID in the current account.
+func currentUserID(c client.API) (string, error) {
+	resp, err := c.Get(cfg.APIURL + "/my/identity.json")
+	if err != nil {
+		return "", err
+	}
+	data, _ := resp.Data.(map[string]interface{})
+	accounts, _ := data["accounts"].([]interface{})
+	for _, acc := range accounts {
+		accMap, ok := acc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		slug, _ := accMap["slug"].(string)
+		if strings.TrimPrefix(slug, "/") != cfg.Account {
+			continue
+		}
+		if user, ok := accMap["user"].(map[string]interface{}); ok {
+			if id, ok := user["id"].(string); ok && id != "" {
+				return id, nil
+			}
+		}
+	}
+	return "", errors.NewNotFoundError("Could not find your user in account " + cfg.Account)
+}
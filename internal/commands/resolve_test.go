@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func boardsMock() *MockClient {
+	mock := NewMockClient()
+	mock.GetWithPaginationResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data: []interface{}{
+			map[string]interface{}{"id": "b1", "name": "Engineering"},
+			map[string]interface{}{"id": "b2", "name": "Design"},
+			map[string]interface{}{"id": "b3", "name": "Design Ops"},
+			map[string]interface{}{"id": "b4", "name": "Ops"},
+		},
+	}
+	return mock
+}
+
+func TestLooksLikeID(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"123", true},
+		{"03f5v9zkft4hj9qq0lsn9ohcm", true},
+		{"col-123", true},
+		{"Engineering", false},
+		{"design", false},
+		{"Sprint 42", false},
+		{"Q3", false},
+	}
+
+	for _, tt := range tests {
+		if got := looksLikeID(tt.value); got != tt.expected {
+			t.Errorf("looksLikeID(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestResolveBoard(t *testing.T) {
+	t.Run("uses ID-like values without a lookup", func(t *testing.T) {
+		mock := boardsMock()
+		id, err := resolveBoard(mock, "123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "123" {
+			t.Errorf("expected '123', got '%s'", id)
+		}
+		if len(mock.GetWithPaginationCalls) != 0 {
+			t.Errorf("expected no lookup, got %d calls", len(mock.GetWithPaginationCalls))
+		}
+	})
+
+	t.Run("matches IDs before names", func(t *testing.T) {
+		mock := boardsMock()
+		mock.GetWithPaginationResponse.Data = append(mock.GetWithPaginationResponse.Data.([]interface{}),
+			map[string]interface{}{"id": "ops", "name": "Operations"})
+		id, err := resolveBoard(mock, "ops")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "ops" {
+			t.Errorf("expected 'ops', got '%s'", id)
+		}
+	})
+
+	t.Run("matches name case-insensitively", func(t *testing.T) {
+		mock := boardsMock()
+		id, err := resolveBoard(mock, "engineering")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "b1" {
+			t.Errorf("expected 'b1', got '%s'", id)
+		}
+		if mock.GetWithPaginationCalls[0].Path != "/boards.json" {
+			t.Errorf("expected '/boards.json', got '%s'", mock.GetWithPaginationCalls[0].Path)
+		}
+	})
+
+	t.Run("prefers exact name over prefix", func(t *testing.T) {
+		id, err := resolveBoard(boardsMock(), "Design")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "b2" {
+			t.Errorf("expected 'b2', got '%s'", id)
+		}
+	})
+
+	t.Run("matches unique prefix", func(t *testing.T) {
+		id, err := resolveBoard(boardsMock(), "Eng")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "b1" {
+			t.Errorf("expected 'b1', got '%s'", id)
+		}
+	})
+
+	t.Run("ambiguous prefix lists candidates", func(t *testing.T) {
+		_, err := resolveBoard(boardsMock(), "Des")
+		cliErr, ok := err.(*errors.CLIError)
+		if !ok {
+			t.Fatalf("expected CLIError, got %T", err)
+		}
+		if cliErr.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, cliErr.ExitCode)
+		}
+		details, _ := cliErr.Details.(map[string]interface{})
+		candidates, _ := details["candidates"].([]resolveCandidate)
+		if len(candidates) != 2 {
+			t.Fatalf("expected 2 candidates, got %v", cliErr.Details)
+		}
+		if candidates[0].ID != "b2" || candidates[1].ID != "b3" {
+			t.Errorf("unexpected candidates: %v", candidates)
+		}
+	})
+
+	t.Run("uses unknown values as IDs", func(t *testing.T) {
+		id, err := resolveBoard(boardsMock(), "Marketing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "Marketing" {
+			t.Errorf("expected 'Marketing', got '%s'", id)
+		}
+	})
+}
+
+func TestResolveColumn(t *testing.T) {
+	mock := NewMockClient()
+	mock.GetResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data: []interface{}{
+			map[string]interface{}{"id": "c1", "name": "Doing"},
+		},
+	}
+
+	id, err := resolveColumn(mock, "b1", "doing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "c1" {
+		t.Errorf("expected 'c1', got '%s'", id)
+	}
+	if mock.GetCalls[0].Path != "/boards/b1/columns.json" {
+		t.Errorf("expected columns lookup, got '%s'", mock.GetCalls[0].Path)
+	}
+
+	if id, _ := resolveColumn(mock, "b1", "maybe"); id != "maybe" {
+		t.Errorf("expected pseudo column to pass through, got '%s'", id)
+	}
+	if _, err := resolveColumn(mock, "", "Doing"); err == nil {
+		t.Error("expected error when resolving a column name without a board")
+	}
+}
+
+func TestResolveUser(t *testing.T) {
+	t.Run("resolves me from identity", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data: map[string]interface{}{
+				"accounts": []interface{}{
+					map[string]interface{}{"slug": "/other", "user": map[string]interface{}{"id": "u-other"}},
+					map[string]interface{}{"slug": "/account", "user": map[string]interface{}{"id": "u-me"}},
+				},
+			},
+		}
+
+		SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		id, err := resolveUser(mock, "me")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "u-me" {
+			t.Errorf("expected 'u-me', got '%s'", id)
+		}
+		if mock.GetCalls[0].Path != "https://api.example.com/my/identity.json" {
+			t.Errorf("expected identity lookup, got '%s'", mock.GetCalls[0].Path)
+		}
+	})
+
+	t.Run("resolves email address", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.GetWithPaginationResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data: []interface{}{
+				map[string]interface{}{"id": "u1", "name": "Ada", "email_address": "ada@example.com"},
+			},
+		}
+
+		id, err := resolveUser(mock, "ada@example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "u1" {
+			t.Errorf("expected 'u1', got '%s'", id)
+		}
+	})
+}
+
+func TestResolveTags(t *testing.T) {
+	mock := NewMockClient()
+	mock.GetWithPaginationResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data: []interface{}{
+			map[string]interface{}{"id": "t1", "title": "bug"},
+			map[string]interface{}{"id": "t2", "title": "feature"},
+		},
+	}
+
+	ids, err := resolveTags(mock, "bug, #feature, tag-9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids != "t1,t2,tag-9" {
+		t.Errorf("expected 't1,t2,tag-9', got '%s'", ids)
+	}
+}
+
+func TestCardAssignResolvesUserName(t *testing.T) {
+	mock := NewMockClient()
+	mock.GetWithPaginationResponse = &client.APIResponse{
+		StatusCode: 200,
+		Data: []interface{}{
+			map[string]interface{}{"id": "u1", "name": "Ada Lovelace"},
+		},
+	}
+
+	result := SetTestMode(mock)
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	cardAssignUser = "Ada"
+	RunTestCommand(func() {
+		cardAssignCmd.Run(cardAssignCmd, []string{"42"})
+	})
+	cardAssignUser = ""
+
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+	body := mock.PostCalls[0].Body.(map[string]interface{})
+	if body["assignee_id"] != "u1" {
+		t.Errorf("expected assignee_id 'u1', got '%v'", body["assignee_id"])
+	}
+}
+
+func TestCardListAmbiguousBoard(t *testing.T) {
+	mock := boardsMock()
+
+	result := SetTestMode(mock)
+	SetTestConfig("token", "account", "https://api.example.com")
+	defer ResetTestMode()
+
+	cardListBoard = "Des"
+	RunTestCommand(func() {
+		cardListCmd.Run(cardListCmd, []string{})
+	})
+	cardListBoard = ""
+
+	if result.ExitCode != errors.ExitInvalidArgs {
+		t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+	}
+	if result.Response.Error == nil || result.Response.Error.Details == nil {
+		t.Fatal("expected error details with candidates")
+	}
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+	"github.com/robzolkos/fizzy-cli/internal/markdown"
+	"github.com/robzolkos/fizzy-cli/internal/response"
+)
+
+const (
+	formatHTML     = "html"
+	formatMarkdown = "markdown"
+)
+
+// readRichText returns the HTML for a rich text field given inline text or a
+// file, which wins if both are set. Markdown is converted to HTML, uploading
+// any local images it references. The boolean reports whether a value was
+// given at all.
+func readRichText(text, file, format string) (string, bool, error) {
+	switch format {
+	case "", formatHTML, formatMarkdown:
+	default:
+		return "", false, errors.NewInvalidArgsError("Invalid format " + format + " (use html or markdown)")
+	}
+
+	baseDir := "."
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, err
+		}
+		text = string(content)
+		baseDir = filepath.Dir(file)
+		if format == "" && isMarkdownFile(file) {
+			format = formatMarkdown
+		}
+	} else if text == "" {
+		return "", false, nil
+	}
+
+	if format != formatMarkdown {
+		return text, true, nil
+	}
+	html, err := markdownToHTML(text, baseDir)
+	if err != nil {
+		return "", false, err
+	}
+	return html, true, nil
+}
+
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// markdownToHTML converts Markdown to HTML, uploading images that refer to
+// local files (relative to baseDir) and embedding them as attachments.
+func markdownToHTML(text, baseDir string) (string, error) {
+	var paths []string
+	local := make(map[string]string)
+	uploading := make(map[string]bool)
+	for _, dest := range markdown.Images(text) {
+		if isRemoteImage(dest) {
+			continue
+		}
+		path := dest
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		local[dest] = path
+		if !uploading[path] {
+			uploading[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	sgids := make(map[string]string, len(paths))
+	if len(paths) > 0 {
+		uploaded, err := uploadAttachables(getClient(), paths)
+		if err != nil {
+			return "", err
+		}
+		for i, path := range paths {
+			sgids[path] = uploaded[i]
+		}
+	}
+
+	return markdown.ToHTML(text, markdown.Options{
+		Image: func(dest, alt string) string {
+			if path, ok := local[dest]; ok {
+				return attachmentTag(sgids[path], alt)
+			}
+			return ""
+		},
+	}), nil
+}
+
+func isRemoteImage(dest string) bool {
+	return strings.Contains(dest, "://") || strings.HasPrefix(dest, "data:")
+}
+
+// setRichTextMode applies the --plain and --markdown flags, which choose how
+// descriptions and comment bodies are shown.
+func setRichTextMode(plain, asMarkdown bool) error {
+	switch {
+	case plain && asMarkdown:
+		return errors.NewInvalidArgsError("Use either --plain or --markdown, not both")
+	case plain:
+		response.SetRichText(response.RichTextPlain)
+	case asMarkdown:
+		response.SetRichText(response.RichTextMarkdown)
+	}
+	return nil
+}
@@ -2,7 +2,12 @@
 package commands
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/robzolkos/fizzy-cli/internal/client"
 	"github.com/robzolkos/fizzy-cli/internal/config"
@@ -17,10 +22,26 @@ var (
 	cfgAccount string
 	cfgAPIURL  string
 	cfgVerbose bool
+	cfgProfile string
+
+	cfgRetryAttempts int
+	cfgRetryMaxDelay string
+	cfgTimeout       string
+	cfgOutput        string
+
+	// Resource type of the running command (e.g. "card"), used to pick
+	// table columns for human-readable output.
+	currentResource string
+
+	// Context of the running command, cancelled on SIGINT/SIGTERM
+	runContext context.Context
 
 	// Loaded config
 	cfg *config.Config
 
+	// Set when the selected profile is not defined in the global config
+	cfgProfileErr error
+
 	// Client factory (can be overridden for testing)
 	clientFactory func() client.API
 )
@@ -34,8 +55,16 @@ var rootCmd = &cobra.Command{
 Use fizzy to manage boards, cards, comments, and more from your terminal.`,
 	Version: "dev",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		format, err := response.ParseFormat(cfgOutput)
+		if err != nil {
+			exitWithError(errors.NewInvalidArgsError(err.Error()))
+		}
+		response.SetFormat(format)
+		currentResource = resourceName(cmd)
+		runContext = cmd.Context()
+
 		// Load config from file/env
-		cfg = config.Load()
+		cfg, cfgProfileErr = config.LoadProfile(cfgProfile)
 
 		// Override with command-line flags
 		if cfgToken != "" {
@@ -47,6 +76,25 @@ Use fizzy to manage boards, cards, comments, and more from your terminal.`,
 		if cfgAPIURL != "" {
 			cfg.APIURL = cfgAPIURL
 		}
+		if cmd.Flags().Changed("retry-attempts") {
+			if cfgRetryAttempts < 1 {
+				exitWithError(invalidRetryAttempts(cfgRetryAttempts))
+			}
+			cfg.RetryAttempts = cfgRetryAttempts
+		}
+		if cfgRetryMaxDelay != "" {
+			cfg.RetryMaxDelay = cfgRetryMaxDelay
+		}
+		if cfgTimeout != "" {
+			cfg.Timeout = cfgTimeout
+		}
+		cfg.ResolveToken()
+		if _, err := requestTimeout(); err != nil {
+			exitWithError(err)
+		}
+		if _, err := retryPolicy(); err != nil {
+			exitWithError(err)
+		}
 	},
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -60,9 +108,17 @@ func SetVersion(v string) {
 	rootCmd.Version = v
 }
 
-// Execute runs the root command.
+// Execute runs the root command. The command's context is cancelled on
+// SIGINT or SIGTERM; a second signal exits immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		if cliErr, ok := err.(*errors.CLIError); ok {
 			response.Error(cliErr).PrintAndExit()
 		}
@@ -75,6 +131,22 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgAccount, "account", "", "Account slug")
 	rootCmd.PersistentFlags().StringVar(&cfgAPIURL, "api-url", "", "API base URL")
 	rootCmd.PersistentFlags().BoolVar(&cfgVerbose, "verbose", false, "Show request/response details")
+	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "Config profile to use (or set FIZZY_PROFILE)")
+	rootCmd.PersistentFlags().StringVarP(&cfgOutput, "output", "o", "json", "Output format (json, table, yaml, ndjson, csv)")
+	rootCmd.PersistentFlags().IntVar(&cfgRetryAttempts, "retry-attempts", client.DefaultRetryAttempts, "Total attempts for rate-limited or unavailable requests (1 disables retries)")
+	rootCmd.PersistentFlags().StringVar(&cfgRetryMaxDelay, "retry-max-delay", "", "Maximum delay between retries (e.g. 30s)")
+	rootCmd.PersistentFlags().StringVar(&cfgTimeout, "timeout", "", "Timeout for each HTTP request (default 30s, 0 disables)")
+}
+
+// resourceName returns the top-level command name below root (e.g. "card"
+// for "fizzy card list").
+func resourceName(cmd *cobra.Command) string {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.HasParent() && !c.Parent().HasParent() {
+			return c.Name()
+		}
+	}
+	return ""
 }
 
 // getClient returns an API client configured from global settings.
@@ -84,11 +156,72 @@ func getClient() client.API {
 	}
 	c := client.New(cfg.APIURL, cfg.Token, cfg.Account)
 	c.Verbose = cfgVerbose
+	c.Retry, _ = retryPolicy()
+	c.Context = commandContext()
+	if timeout, err := requestTimeout(); err == nil {
+		c.HTTPClient.Timeout = timeout
+	}
 	return c
 }
 
+// commandContext returns the context of the running command.
+func commandContext() context.Context {
+	if runContext != nil {
+		return runContext
+	}
+	return context.Background()
+}
+
+// requestTimeout returns the per-request timeout from config and flags.
+func requestTimeout() (time.Duration, error) {
+	if cfg == nil || cfg.Timeout == "" {
+		return client.DefaultTimeout, nil
+	}
+	if cfg.Timeout == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || d < 0 {
+		return 0, errors.NewInvalidArgsError("Invalid timeout " + cfg.Timeout + " (use a duration like 30s or 2m, or 0 to disable)")
+	}
+	return d, nil
+}
+
+// retryPolicy builds the client retry policy from config and flags.
+func retryPolicy() (client.RetryPolicy, error) {
+	policy := client.DefaultRetryPolicy()
+	if cfg == nil {
+		return policy, nil
+	}
+	if cfg.RetryAttempts < 0 {
+		return policy, invalidRetryAttempts(cfg.RetryAttempts)
+	}
+	if cfg.RetryAttempts > 0 {
+		policy.MaxAttempts = cfg.RetryAttempts
+	}
+	if cfg.RetryMaxDelay != "" {
+		d, err := time.ParseDuration(cfg.RetryMaxDelay)
+		if err != nil || d <= 0 {
+			return policy, errors.NewInvalidArgsError("Invalid retry max delay " + cfg.RetryMaxDelay + " (use a duration like 10s or 1m)")
+		}
+		policy.MaxDelay = d
+	}
+	return policy, nil
+}
+
+// invalidRetryAttempts is the error for a retry attempts setting below 1.
+func invalidRetryAttempts(n int) error {
+	return errors.NewInvalidArgsError("Invalid retry attempts " + strconv.Itoa(n) + " (use 1 or more; 1 disables retries)")
+}
+
 // requireAuth checks that we have authentication configured.
 func requireAuth() error {
+	if cfgProfileErr != nil {
+		return errors.NewInvalidArgsError(cfgProfileErr.Error() + ". Run 'fizzy auth login TOKEN --profile NAME' to create it")
+	}
+	if cfg.Token == "" && cfg.CredentialError != nil {
+		return errors.NewAuthError("Failed to read API token from credential store: " + cfg.CredentialError.Error())
+	}
 	if cfg.Token == "" {
 		return errors.NewAuthError("No API token configured. Run 'fizzy auth login TOKEN' or set FIZZY_TOKEN")
 	}
@@ -118,6 +251,11 @@ func effectiveConfig() *config.Config {
 	return config.Load()
 }
 
+// activeProfile returns the selected config profile name, if any.
+func activeProfile() string {
+	return effectiveConfig().Profile
+}
+
 func defaultBoard(board string) string {
 	if board != "" {
 		return board
@@ -125,12 +263,14 @@ func defaultBoard(board string) string {
 	return effectiveConfig().Board
 }
 
+// requireBoard returns the board ID from the flag or config, resolving a
+// board name to its ID.
 func requireBoard(board string) (string, error) {
 	board = defaultBoard(board)
 	if board == "" {
 		return "", errors.NewInvalidArgsError("No board configured. Set --board, FIZZY_BOARD, or add 'board' to your config file")
 	}
-	return board, nil
+	return resolveBoard(getClient(), board)
 }
 
 // CommandResult holds the result of a command execution for testing.
@@ -165,6 +305,7 @@ func exitWithError(err error) {
 // printSuccess prints a success response.
 func printSuccess(data interface{}) {
 	resp := response.Success(data)
+	resp.Resource = currentResource
 	if lastResult != nil {
 		lastResult.Response = resp
 		lastResult.ExitCode = errors.ExitSuccess
@@ -177,6 +318,7 @@ func printSuccess(data interface{}) {
 // printSuccessWithLocation prints a success response with location.
 func printSuccessWithLocation(data interface{}, location string) {
 	resp := response.SuccessWithLocation(data, location)
+	resp.Resource = currentResource
 	if lastResult != nil {
 		lastResult.Response = resp
 		lastResult.ExitCode = errors.ExitSuccess
@@ -189,6 +331,24 @@ func printSuccessWithLocation(data interface{}, location string) {
 // printSuccessWithPagination prints a success response with pagination.
 func printSuccessWithPagination(data interface{}, hasNext bool, nextURL string) {
 	resp := response.SuccessWithPagination(data, hasNext, nextURL)
+	resp.Resource = currentResource
+	if lastResult != nil {
+		lastResult.Response = resp
+		lastResult.ExitCode = errors.ExitSuccess
+		panic(testExitSignal{}) // Signal to stop execution in test mode
+	}
+	resp.Print()
+	os.Exit(errors.ExitSuccess)
+}
+
+// printSuccessWithMore prints a success response whose table output ends with
+// hint when hasNext is set, for commands that don't page with --page.
+func printSuccessWithMore(data interface{}, hasNext bool, hint string) {
+	resp := response.SuccessWithPagination(data, hasNext, "")
+	if resp.Pagination != nil {
+		resp.Pagination.Hint = hint
+	}
+	resp.Resource = currentResource
 	if lastResult != nil {
 		lastResult.Response = resp
 		lastResult.ExitCode = errors.ExitSuccess
@@ -222,6 +382,10 @@ func ResetTestMode() {
 	clientFactory = nil
 	lastResult = nil
 	cfg = nil
+	cfgProfileErr = nil
+	currentResource = ""
+	runContext = nil
+	response.SetRichText(response.RichTextStyled)
 }
 
 // GetRootCmd returns the root command for testing.
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/config"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"default", "", client.DefaultTimeout, false},
+		{"duration", "2m", 2 * time.Minute, false},
+		{"disabled", "0", 0, false},
+		{"invalid", "soon", 0, true},
+		{"negative", "-5s", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTestConfig("token", "account", "https://api.example.com")
+			defer ResetTestMode()
+			cfg.Timeout = tt.timeout
+
+			got, err := requestTimeout()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.timeout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDelay string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{"default", "", client.DefaultRetryPolicy().MaxDelay, false},
+		{"duration", "1m", time.Minute, false},
+		{"invalid", "soon", 0, true},
+		{"zero", "0s", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTestConfig("token", "account", "https://api.example.com")
+			defer ResetTestMode()
+			cfg.RetryMaxDelay = tt.maxDelay
+
+			got, err := retryPolicy()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.maxDelay)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MaxDelay != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got.MaxDelay)
+			}
+		})
+	}
+}
+
+func TestTokenFlagSkipsCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	config.SetTestConfigDir(dir)
+	defer config.ResetTestConfigDir()
+	os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("credential_store: helper:does-not-exist\n"), 0600)
+	t.Setenv("FIZZY_TOKEN", "")
+
+	SetTestMode(NewMockClient())
+	defer ResetTestMode()
+	cfgToken = "flag-token"
+	defer func() { cfgToken = "" }()
+
+	RunTestCommand(func() {
+		rootCmd.PersistentPreRun(versionCmd, nil)
+	})
+
+	if cfg.Token != "flag-token" {
+		t.Errorf("expected the flag token, got '%s'", cfg.Token)
+	}
+	if cfg.CredentialError != nil {
+		t.Errorf("expected the credential helper not to run, got %v", cfg.CredentialError)
+	}
+}
+
+func TestRetryAttemptsFlag(t *testing.T) {
+	for _, value := range []string{"0", "-1"} {
+		t.Run(value, func(t *testing.T) {
+			result := SetTestMode(NewMockClient())
+			defer ResetTestMode()
+			flag := rootCmd.PersistentFlags().Lookup("retry-attempts")
+			defer func() {
+				cfgRetryAttempts = client.DefaultRetryAttempts
+				flag.Changed = false
+			}()
+			if err := rootCmd.ParseFlags([]string{"--retry-attempts", value}); err != nil {
+				t.Fatal(err)
+			}
+
+			RunTestCommand(func() {
+				rootCmd.PersistentPreRun(rootCmd, nil)
+			})
+
+			if result.ExitCode != errors.ExitInvalidArgs {
+				t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package commands
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/robzolkos/fizzy-cli/internal/client"
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+// Weights of the fields a card is searched in: a hit in the title counts
+// most, one in a comment least.
+const (
+	titleWeight       = 10
+	descriptionWeight = 4
+	stepWeight        = 2
+	commentWeight     = 1
+)
+
+// snippetContext is roughly how many characters of context a snippet shows
+// before the first hit.
+const snippetContext = 30
+
+// snippetLength is the longest a snippet gets, not counting highlights.
+const snippetLength = 100
+
+// searchCards pages through the cards at path until limit of them match (0
+// for no limit), and returns the matches best first with a "search" object
+// added to each. Locally, every card's steps and comments are fetched and
+// matched too. Otherwise the server has found the cards, but only those that
+// contain every term are kept; cards whose title and description don't are
+// checked against their steps and comments. Cards are fetched concurrency
+// at a time; a card that can't be fetched is searched on its title and
+// description alone. It reports whether more cards may match.
+func searchCards(c client.API, path string, query cardQuery, terms []string, local bool, limit, concurrency int) ([]interface{}, bool, error) {
+	type ranked struct {
+		card map[string]interface{}
+		hit  searchHit
+	}
+	var found []ranked
+
+	more := false
+	resp, err := c.GetWithPagination(path, false)
+pages:
+	for {
+		if err != nil {
+			return nil, false, err
+		}
+		items, ok := resp.Data.([]interface{})
+		if !ok {
+			return nil, false, errors.NewError("Unexpected cards list response")
+		}
+
+		var cards []map[string]interface{}
+		var fields [][]searchField
+		var numbers []string
+		for _, item := range items {
+			card, ok := item.(map[string]interface{})
+			if !ok || !query.keeps(item) {
+				continue
+			}
+			cards = append(cards, card)
+			fields = append(fields, cardSearchFields(card))
+			if _, matched := scoreCard(terms, fields[len(fields)-1]); local || !matched {
+				numbers = append(numbers, cardNumber(card))
+			}
+		}
+		if len(numbers) > 0 {
+			results, err := runBulk(numbers, concurrency, func(number string) (interface{}, error) {
+				fields, err := cardDetailSearchFields(c, number)
+				if cliErr, ok := err.(*errors.CLIError); ok && cliErr.ExitCode != errors.ExitCancelled {
+					// The card may have been deleted or moved since it was
+					// listed; search its title and description only
+					return []searchField{}, nil
+				}
+				return fields, err
+			})
+			if err != nil {
+				return nil, false, err
+			}
+			extra := make(map[string][]searchField, len(numbers))
+			for i, result := range results {
+				extra[numbers[i]] = result.(map[string]interface{})["data"].([]searchField)
+			}
+			for i, card := range cards {
+				fields[i] = append(fields[i], extra[cardNumber(card)]...)
+			}
+		}
+
+		for i, card := range cards {
+			hit, ok := scoreCard(terms, fields[i])
+			if !ok {
+				continue
+			}
+			found = append(found, ranked{card, hit})
+			if limit > 0 && len(found) >= limit {
+				more = i < len(cards)-1 || resp.LinkNext != ""
+				break pages
+			}
+		}
+		if resp.LinkNext == "" {
+			break
+		}
+		resp, err = c.Get(resp.LinkNext)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].hit.score > found[j].hit.score
+	})
+	results := make([]interface{}, 0, len(found))
+	for _, r := range found {
+		card := make(map[string]interface{}, len(r.card)+1)
+		for k, v := range r.card {
+			card[k] = v
+		}
+		matches := r.hit.matches
+		if matches == nil {
+			matches = []map[string]interface{}{}
+		}
+		search := map[string]interface{}{
+			"score":   r.hit.score,
+			"matches": matches,
+		}
+		if len(r.hit.matches) > 0 {
+			search["field"] = r.hit.matches[0]["field"]
+			search["snippet"] = r.hit.matches[0]["snippet"]
+		}
+		card["search"] = search
+		results = append(results, card)
+	}
+	return results, more, nil
+}
+
+// cardSearchFields returns the searchable text in a card from a list.
+func cardSearchFields(card map[string]interface{}) []searchField {
+	title, _ := card["title"].(string)
+	description, _ := card["description"].(string)
+	return []searchField{
+		{name: "title", text: title, weight: titleWeight},
+		{name: "description", text: description, weight: descriptionWeight},
+	}
+}
+
+// cardDetailSearchFields fetches a card's steps and comments to search.
+func cardDetailSearchFields(c client.API, number string) ([]searchField, error) {
+	var fields []searchField
+	resp, err := c.Get("/cards/" + number + ".json")
+	if err != nil {
+		return nil, err
+	}
+	if card, ok := resp.Data.(map[string]interface{}); ok {
+		steps, _ := card["steps"].([]interface{})
+		for _, item := range steps {
+			step, _ := item.(map[string]interface{})
+			content, _ := step["content"].(string)
+			fields = append(fields, searchField{name: "step", text: content, weight: stepWeight})
+		}
+	}
+
+	resp, err = c.GetWithPagination("/cards/"+number+"/comments.json", true)
+	if err != nil {
+		return nil, err
+	}
+	comments, _ := resp.Data.([]interface{})
+	for _, item := range comments {
+		comment, _ := item.(map[string]interface{})
+		body, _ := comment["body"].(map[string]interface{})
+		text, _ := body["plain_text"].(string)
+		fields = append(fields, searchField{name: "comment", text: text, weight: commentWeight})
+	}
+	return fields, nil
+}
+
+// searchTerms splits a query into lowercase terms. Double-quoted phrases are
+// kept together as one term.
+func searchTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		term = strings.Join(strings.Fields(strings.ToLower(term)), " ")
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			add(part)
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			add(word)
+		}
+	}
+	return terms
+}
+
+// searchField is a piece of card text to search.
+type searchField struct {
+	name   string
+	text   string
+	weight int
+}
+
+// searchHit is where a card matched a query.
+type searchHit struct {
+	score int
+	// matches are {"field", "snippet"} maps, best first
+	matches []map[string]interface{}
+}
+
+// scoreCard matches terms against a card's fields. It reports false unless
+// every term appears in at least one field.
+func scoreCard(terms []string, fields []searchField) (searchHit, bool) {
+	var hit searchHit
+	if len(terms) == 0 {
+		return hit, false
+	}
+	found := make(map[string]bool, len(terms))
+
+	type fieldMatch struct {
+		score   int
+		field   string
+		snippet string
+	}
+	var matches []fieldMatch
+	for _, f := range fields {
+		text := []rune(strings.Join(strings.Fields(f.text), " "))
+		lower := foldRunes(text)
+
+		score := 0
+		var ranges [][2]int
+		for _, term := range terms {
+			positions := findAll(lower, []rune(term))
+			if len(positions) == 0 {
+				continue
+			}
+			found[term] = true
+			// Repeats count, but only up to a point
+			score += f.weight * min(len(positions), 3)
+			for _, pos := range positions {
+				if pos == 0 || !isWordRune(lower[pos-1]) {
+					score += f.weight
+				}
+				ranges = append(ranges, [2]int{pos, pos + len([]rune(term))})
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		if f.weight == titleWeight && len(terms) > 1 && len(findAll(lower, []rune(strings.Join(terms, " ")))) > 0 {
+			score += 2 * titleWeight
+		}
+		hit.score += score
+		matches = append(matches, fieldMatch{score, f.name, snippet(text, ranges)})
+	}
+	if len(found) < len(terms) {
+		return searchHit{}, false
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	for _, m := range matches {
+		hit.matches = append(hit.matches, map[string]interface{}{
+			"field":   m.field,
+			"snippet": m.snippet,
+		})
+	}
+	return hit, true
+}
+
+// snippet returns the text around the first hit, with every hit in it
+// highlighted as **hit**.
+func snippet(text []rune, ranges [][2]int) string {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	start := max(0, merged[0][0]-snippetContext)
+	if start > 0 {
+		// Start at a word
+		if space := indexRune(text[start:merged[0][0]], ' '); space >= 0 {
+			start += space + 1
+		}
+	}
+	end := min(len(text), start+snippetLength)
+	if end < len(text) {
+		if space := lastIndexRune(text[merged[0][1]:end], ' '); space >= 0 {
+			end = merged[0][1] + space
+		}
+		end = max(end, merged[0][1])
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, r := range merged {
+		if r[0] >= end {
+			break
+		}
+		b.WriteString(string(text[pos:r[0]]))
+		b.WriteString("**" + string(text[r[0]:min(r[1], end)]) + "**")
+		pos = min(r[1], end)
+	}
+	b.WriteString(string(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// foldRunes lowercases text rune by rune, so positions in the result are
+// positions in text.
+func foldRunes(text []rune) []rune {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// findAll returns the positions of non-overlapping occurrences of term.
+func findAll(text, term []rune) []int {
+	var positions []int
+	for i := 0; i+len(term) <= len(text); {
+		if slices.Equal(text[i:i+len(term)], term) {
+			positions = append(positions, i)
+			i += len(term)
+			continue
+		}
+		i++
+	}
+	return positions
+}
+
+func indexRune(text []rune, r rune) int {
+	for i, c := range text {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func lastIndexRune(text []rune, r rune) int {
+	for i := len(text) - 1; i >= 0; i-- {
+		if text[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
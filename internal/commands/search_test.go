@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Login Timeout", []string{"login", "timeout"}},
+		{`"login  page" broken login`, []string{"login page", "broken", "login"}},
+		{`  "" `, nil},
+		{`unterminated "quote here`, []string{"unterminated", "quote here"}},
+	}
+	for _, tt := range tests {
+		if got := searchTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchTerms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestScoreCard(t *testing.T) {
+	fields := func(title, description string) []searchField {
+		return cardSearchFields(map[string]interface{}{"title": title, "description": description})
+	}
+
+	t.Run("requires every term", func(t *testing.T) {
+		if _, ok := scoreCard([]string{"login", "crash"}, fields("Login page", "Times out")); ok {
+			t.Error("expected no match")
+		}
+		if _, ok := scoreCard([]string{"login", "crash"}, fields("Login page", "It can crash")); !ok {
+			t.Error("expected terms across fields to match")
+		}
+	})
+
+	t.Run("ranks title hits above description hits", func(t *testing.T) {
+		title, _ := scoreCard([]string{"login"}, fields("Fix login", ""))
+		description, _ := scoreCard([]string{"login"}, fields("Fix it", "The login page"))
+		if title.score <= description.score {
+			t.Errorf("expected title score %d > description score %d", title.score, description.score)
+		}
+	})
+
+	t.Run("favours whole words and phrases", func(t *testing.T) {
+		word, _ := scoreCard([]string{"log"}, fields("Read the log", ""))
+		part, _ := scoreCard([]string{"log"}, fields("Read the blog", ""))
+		if word.score <= part.score {
+			t.Errorf("expected word score %d > substring score %d", word.score, part.score)
+		}
+		phrase, _ := scoreCard([]string{"login", "page"}, fields("Login page", ""))
+		apart, _ := scoreCard([]string{"login", "page"}, fields("Page for login", ""))
+		if phrase.score <= apart.score {
+			t.Errorf("expected phrase score %d > scattered score %d", phrase.score, apart.score)
+		}
+	})
+
+	t.Run("lists matches best first with highlights", func(t *testing.T) {
+		hit, ok := scoreCard([]string{"login"}, fields("Fix LOGIN", "The login page and login form"))
+		if !ok {
+			t.Fatal("expected a match")
+		}
+		want := []map[string]interface{}{
+			{"field": "title", "snippet": "Fix **LOGIN**"},
+			{"field": "description", "snippet": "The **login** page and **login** form"},
+		}
+		if !reflect.DeepEqual(hit.matches, want) {
+			t.Errorf("matches = %v, want %v", hit.matches, want)
+		}
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	text := []rune("Steps to reproduce: open the app, go to settings, tap the login button twice and wait for the spinner to finish before it crashes completely")
+	got := snippet(text, [][2]int{{122, 129}, {58, 63}})
+	// Starts at a word before the first hit; the second is out of reach
+	want := "…app, go to settings, tap the **login** button twice and wait for the spinner to finish before it…"
+	if got != want {
+		t.Errorf("snippet = %q, want %q", got, want)
+	}
+
+	// Multibyte text keeps its characters intact
+	text = []rune("Ünïcode café crème")
+	if got := snippet(text, [][2]int{{8, 12}}); got != "Ünïcode **café** crème" {
+		t.Errorf("snippet = %q", got)
+	}
+}
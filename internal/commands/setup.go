@@ -28,8 +28,11 @@ type Board struct {
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Interactive setup wizard",
-	Long:  "Configure Fizzy CLI with your API token, account, and default board.",
-	Run:   runSetup,
+	Long: `Configure Fizzy CLI with your API token, account, and default board.
+
+With --profile NAME, the settings are saved as a named profile in the global
+config, leaving the top-level settings and other profiles untouched.`,
+	Run: runSetup,
 }
 
 func init() {
@@ -41,14 +44,22 @@ func runSetup(cmd *cobra.Command, args []string) {
 	fmt.Println("Welcome to Fizzy CLI setup!")
 	fmt.Println()
 
+	profile := activeProfile()
+
 	// Check for existing config
 	globalExists := config.Exists()
 	localPath := config.LocalConfigPath()
+	if profile != "" {
+		_, globalExists = config.LoadGlobal().Profiles[profile]
+		localPath = ""
+	}
 
 	if globalExists || localPath != "" {
 		var reconfigure bool
 		configLocation := "global config"
-		if localPath != "" {
+		if profile != "" {
+			configLocation = "profile " + profile
+		} else if localPath != "" {
 			configLocation = "local config (" + localPath + ")"
 		}
 
@@ -214,6 +225,30 @@ func runSetup(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Build the new settings
+	newConfig := &config.Config{
+		Token:   token,
+		Account: selectedAccountSlug,
+		Board:   selectedBoardID,
+	}
+
+	// Only set API URL if not default
+	if apiURL != config.DefaultAPIURL {
+		newConfig.APIURL = apiURL
+	}
+
+	// Profiles always live in the global config
+	if profile != "" {
+		if err := saveProfile(profile, newConfig); err != nil {
+			exitWithError(err)
+		}
+		fmt.Println()
+		fmt.Printf("✓ Profile %s saved to ~/.config/fizzy/config.yaml\n", profile)
+		fmt.Println()
+		fmt.Printf("You're all set! Try: fizzy board list --profile %s\n", profile)
+		return
+	}
+
 	// Ask where to save
 	var saveGlobal bool
 	err = huh.NewSelect[bool]().
@@ -230,18 +265,6 @@ func runSetup(cmd *cobra.Command, args []string) {
 		os.Exit(0)
 	}
 
-	// Build and save config
-	newConfig := &config.Config{
-		Token:   token,
-		Account: selectedAccountSlug,
-		Board:   selectedBoardID,
-	}
-
-	// Only set API URL if not default
-	if apiURL != config.DefaultAPIURL {
-		newConfig.APIURL = apiURL
-	}
-
 	if saveGlobal {
 		// Load existing global config to preserve any other settings
 		existingConfig := config.LoadGlobal()
@@ -271,6 +294,18 @@ func runSetup(cmd *cobra.Command, args []string) {
 	fmt.Println("You're all set! Try: fizzy board list")
 }
 
+// saveProfile stores settings as a named profile in the global config,
+// preserving the top-level settings and any other profiles.
+func saveProfile(name string, settings *config.Config) error {
+	globalCfg := config.LoadGlobal()
+	profile := globalCfg.EnsureProfile(name)
+	profile.Token = settings.Token
+	profile.Account = settings.Account
+	profile.Board = settings.Board
+	profile.APIURL = settings.APIURL
+	return globalCfg.Save()
+}
+
 // validateToken validates the token by calling the identity endpoint.
 // Returns the list of accounts on success.
 func validateToken(apiURL, token string) ([]Account, error) {
@@ -284,3 +284,37 @@ func TestSaveLocal(t *testing.T) {
 		}
 	})
 }
+
+func TestSaveProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	config.SetTestConfigDir(tempDir)
+	defer config.ResetTestConfigDir()
+
+	existing := "token: top-token\nprofiles:\n  oss:\n    token: oss-token\n"
+	os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(existing), 0600)
+
+	err := saveProfile("work", &config.Config{
+		Token:   "work-token",
+		Account: "123",
+		Board:   "board-1",
+		APIURL:  "https://fizzy.example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved := config.LoadGlobal()
+	if saved.Token != "top-token" {
+		t.Errorf("expected top-level token to be preserved, got '%s'", saved.Token)
+	}
+	if saved.Profiles["oss"] == nil || saved.Profiles["oss"].Token != "oss-token" {
+		t.Error("expected existing profile 'oss' to be preserved")
+	}
+	work := saved.Profiles["work"]
+	if work == nil {
+		t.Fatal("expected profile 'work' to be saved")
+	}
+	if work.Token != "work-token" || work.Account != "123" || work.Board != "board-1" || work.APIURL != "https://fizzy.example.com" {
+		t.Errorf("unexpected profile contents: %+v", work)
+	}
+}
@@ -1,12 +1,25 @@
 package commands
 
 import (
+	"fmt"
+	"io"
+	"mime"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/robzolkos/fizzy-cli/internal/client"
 	"github.com/robzolkos/fizzy-cli/internal/errors"
 	"github.com/spf13/cobra"
 )
 
+// defaultUploadConcurrency is how many files are uploaded at once.
+const defaultUploadConcurrency = 4
+
+// Upload command flags
+var uploadConcurrency int
+var uploadContentType string
+
 var uploadCmd = &cobra.Command{
 	Use:   "upload",
 	Short: "Upload files",
@@ -14,33 +27,222 @@ var uploadCmd = &cobra.Command{
 }
 
 var uploadFileCmd = &cobra.Command{
-	Use:   "file PATH",
-	Short: "Upload a file",
-	Long:  "Uploads a file and returns a signed_id for use in rich text fields.",
-	Args:  cobra.ExactArgs(1),
+	Use:   "file PATH...",
+	Short: "Upload files",
+	Long: `Uploads files and returns a signed_id for use in rich text fields.
+
+With several paths, the files are uploaded concurrently and the result is a
+list with one entry per file, in the order given. Progress is shown on stderr
+when it is a terminal.
+
+The content type is detected from each file's contents, falling back to its
+extension. Use --content-type to set it explicitly.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := requireAuthAndAccount(); err != nil {
 			exitWithError(err)
 		}
+		if uploadConcurrency < 1 {
+			exitWithError(errors.NewInvalidArgsError("--concurrency must be at least 1"))
+		}
+		if uploadContentType != "" {
+			if _, _, err := mime.ParseMediaType(uploadContentType); err != nil {
+				exitWithError(errors.NewInvalidArgsError("Invalid content type " + uploadContentType))
+			}
+		}
 
-		filePath := args[0]
-
-		// Check if file exists
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			exitWithError(errors.NewError("File not found: " + filePath))
+		// Check all files exist before uploading any
+		for _, filePath := range args {
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				exitWithError(errors.NewError("File not found: " + filePath))
+			}
 		}
 
 		client := getClient()
-		resp, err := client.UploadFile(filePath)
+		done := showUploadProgress(client, args)
+		results, err := uploadFiles(client, args, uploadContentType, uploadConcurrency)
+		done()
 		if err != nil {
 			exitWithError(err)
 		}
 
-		printSuccess(resp.Data)
+		if len(args) == 1 {
+			printSuccess(results[0])
+			return
+		}
+		printSuccess(results)
 	},
 }
 
+// uploadFiles uploads paths with at most concurrency uploads in flight,
+// detecting each content type unless contentType is set. With
+// several paths, each result is tagged with its file. If any upload fails,
+// the error for the first failed path is returned with the successful
+// results as partial data.
+func uploadFiles(c client.API, paths []string, contentType string, concurrency int) ([]interface{}, error) {
+	results := make([]interface{}, len(paths))
+	errs := make([]error, len(paths))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(paths)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := c.UploadFile(paths[i], contentType)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i] = resp.Data
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(paths) == 1 {
+		return results, errs[0]
+	}
+
+	uploaded := []interface{}{}
+	failed := -1
+	for i, path := range paths {
+		if errs[i] != nil {
+			if failed < 0 {
+				failed = i
+			}
+			continue
+		}
+		entry := map[string]interface{}{"file": path}
+		if data, ok := results[i].(map[string]interface{}); ok {
+			for k, v := range data {
+				entry[k] = v
+			}
+		}
+		results[i] = entry
+		uploaded = append(uploaded, entry)
+	}
+	if failed >= 0 {
+		return nil, partialUploadError(errs[failed], paths[failed], uploaded)
+	}
+	return results, nil
+}
+
+// partialUploadError names the file that failed and carries the files that
+// were uploaded.
+func partialUploadError(err error, path string, uploaded []interface{}) error {
+	cliErr, ok := err.(*errors.CLIError)
+	if !ok {
+		cliErr = errors.NewError(err.Error())
+	}
+	failed := *cliErr
+	failed.Message = path + ": " + cliErr.Message
+	failed.Partial = uploaded
+	return &failed
+}
+
+// showUploadProgress reports the progress of uploading paths on stderr when
+// it is a terminal. The returned function ends the progress line.
+func showUploadProgress(api client.API, paths []string) func() {
+	c, ok := api.(*client.Client)
+	if !ok || !isTerminal(os.Stderr) {
+		return func() {}
+	}
+	p := newUploadProgress(os.Stderr, paths)
+	c.Progress = p.report
+	return p.done
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// uploadProgress keeps one status line up to date for a set of uploads.
+type uploadProgress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	files   int
+	total   int64
+	sent    map[string]int64
+	last    time.Time
+	printed bool
+}
+
+func newUploadProgress(w io.Writer, paths []string) *uploadProgress {
+	p := &uploadProgress{w: w, files: len(paths), sent: map[string]int64{}}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			p.total += info.Size()
+		}
+	}
+	return p
+}
+
+// report records the bytes sent for path, redrawing at most every 100ms.
+func (p *uploadProgress) report(path string, sent, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent[path] = sent
+	if time.Since(p.last) < 100*time.Millisecond && sent < total {
+		return
+	}
+	p.last = time.Now()
+	p.draw()
+}
+
+func (p *uploadProgress) draw() {
+	var sent int64
+	for _, n := range p.sent {
+		sent += n
+	}
+	percent := int64(100)
+	if p.total > 0 {
+		percent = sent * 100 / p.total
+	}
+	noun := "files"
+	if p.files == 1 {
+		noun = "file"
+	}
+	fmt.Fprintf(p.w, "\rUploading %d %s: %s / %s (%d%%)", p.files, noun, formatBytes(sent), formatBytes(p.total), percent)
+	p.printed = true
+}
+
+// done draws the final state and ends the line.
+func (p *uploadProgress) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.printed {
+		return
+	}
+	p.draw()
+	fmt.Fprintln(p.w)
+}
+
+// formatBytes formats n as a human-readable size, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func init() {
 	rootCmd.AddCommand(uploadCmd)
 	uploadCmd.AddCommand(uploadFileCmd)
+	uploadFileCmd.Flags().IntVar(&uploadConcurrency, "concurrency", defaultUploadConcurrency, "Number of files to upload at once")
+	uploadFileCmd.Flags().StringVar(&uploadContentType, "content-type", "", "Content type to upload with (default: detected from the file)")
 }
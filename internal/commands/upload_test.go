@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/robzolkos/fizzy-cli/internal/client"
@@ -52,3 +54,157 @@ func TestUploadFile(t *testing.T) {
 		}
 	})
 }
+
+func TestUploadMultipleFiles(t *testing.T) {
+	t.Run("uploads every file and lists results in order", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.UploadFileResponse = &client.APIResponse{
+			StatusCode: 200,
+			Data:       map[string]interface{}{"signed_id": "abc123"},
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		files := []string{"mock_client_test.go", "upload_test.go", "card_test.go"}
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, files)
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if len(mock.UploadFileCalls) != 3 {
+			t.Errorf("expected 3 UploadFile calls, got %d", len(mock.UploadFileCalls))
+		}
+		data, ok := result.Response.Data.([]interface{})
+		if !ok || len(data) != 3 {
+			t.Fatalf("expected 3 results, got %v", result.Response.Data)
+		}
+		for i, file := range files {
+			entry := data[i].(map[string]interface{})
+			if entry["file"] != file || entry["signed_id"] != "abc123" {
+				t.Errorf("unexpected result %d: %v", i, entry)
+			}
+		}
+	})
+
+	t.Run("returns uploaded files as partial data on failure", func(t *testing.T) {
+		mock := NewMockClient()
+		mock.UploadFileErrors = map[string]error{
+			"upload_test.go": errors.NewNetworkError("Upload failed"),
+		}
+
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, []string{"mock_client_test.go", "upload_test.go"})
+		})
+
+		if result.ExitCode != errors.ExitNetwork {
+			t.Errorf("expected exit code %d, got %d", errors.ExitNetwork, result.ExitCode)
+		}
+		if result.Response.Error.Message != "upload_test.go: Upload failed" {
+			t.Errorf("unexpected message %q", result.Response.Error.Message)
+		}
+		data, ok := result.Response.Data.([]interface{})
+		if !ok || len(data) != 1 || data[0].(map[string]interface{})["file"] != "mock_client_test.go" {
+			t.Errorf("expected the uploaded file as partial data, got %v", result.Response.Data)
+		}
+	})
+
+	t.Run("checks every file before uploading", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, []string{"mock_client_test.go", "/nonexistent/file.png"})
+		})
+
+		if result.ExitCode != errors.ExitError {
+			t.Errorf("expected exit code %d, got %d", errors.ExitError, result.ExitCode)
+		}
+		if len(mock.UploadFileCalls) != 0 {
+			t.Errorf("expected no uploads, got %v", mock.UploadFileCalls)
+		}
+	})
+}
+
+func TestUploadProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := &uploadProgress{w: &buf, files: 2, total: 3 * 1024, sent: map[string]int64{}}
+
+	p.report("a.png", 1024, 1024)
+	p.report("b.pdf", 512, 2048)
+	p.done()
+
+	lines := strings.Split(buf.String(), "\r")
+	last := lines[len(lines)-1]
+	if last != "Uploading 2 files: 1.5 KB / 3.0 KB (50%)\n" {
+		t.Errorf("unexpected final progress line %q", last)
+	}
+}
+
+func TestUploadContentType(t *testing.T) {
+	t.Run("passes --content-type to every upload", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		uploadContentType = "text/markdown"
+		defer func() { uploadContentType = "" }()
+
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, []string{"mock_client_test.go", "upload_test.go"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		for i, ct := range mock.UploadFileContentTypes {
+			if ct != "text/markdown" {
+				t.Errorf("expected content type 'text/markdown' for upload %d, got '%s'", i, ct)
+			}
+		}
+	})
+
+	t.Run("detects the content type by default", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, []string{"mock_client_test.go"})
+		})
+
+		if result.ExitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+		}
+		if mock.UploadFileContentTypes[0] != "" {
+			t.Errorf("expected no content type override, got '%s'", mock.UploadFileContentTypes[0])
+		}
+	})
+
+	t.Run("rejects an invalid content type", func(t *testing.T) {
+		mock := NewMockClient()
+		result := SetTestMode(mock)
+		SetTestConfig("token", "account", "https://api.example.com")
+		defer ResetTestMode()
+		uploadContentType = "not a type"
+		defer func() { uploadContentType = "" }()
+
+		RunTestCommand(func() {
+			uploadFileCmd.Run(uploadFileCmd, []string{"mock_client_test.go"})
+		})
+
+		if result.ExitCode != errors.ExitInvalidArgs {
+			t.Errorf("expected exit code %d, got %d", errors.ExitInvalidArgs, result.ExitCode)
+		}
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 
+	"github.com/robzolkos/fizzy-cli/internal/credentials"
 	"gopkg.in/yaml.v3"
 )
 
@@ -50,6 +53,29 @@ type Config struct {
 	Account string `yaml:"account"`
 	APIURL  string `yaml:"api_url"`
 	Board   string `yaml:"board"`
+
+	// RetryAttempts is the total number of attempts for retryable requests.
+	RetryAttempts int `yaml:"retry_attempts,omitempty"`
+	// RetryMaxDelay caps the backoff between retries (e.g. "30s").
+	RetryMaxDelay string `yaml:"retry_max_delay,omitempty"`
+	// Timeout limits each HTTP request (e.g. "30s"); "0" disables it.
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// CredentialStore selects where the token is kept: "config" (default,
+	// plaintext in this file), "file" (encrypted file), or "helper:NAME"
+	// (an external fizzy-credential-NAME program).
+	CredentialStore string `yaml:"credential_store,omitempty"`
+
+	// CredentialError is set when the token could not be read from the
+	// credential store.
+	CredentialError error `yaml:"-"`
+
+	// Profile names the profile to use. In the global config it is the
+	// default profile; in .fizzy.yaml it pins a project to a profile.
+	Profile string `yaml:"profile,omitempty"`
+	// Profiles holds named sets of settings, e.g. one per Fizzy instance.
+	// Only read from the global config.
+	Profiles map[string]*Config `yaml:"profiles,omitempty"`
 }
 
 // globalConfigPaths returns the possible global configuration file paths in order of preference.
@@ -99,11 +125,27 @@ func findLocalConfig() string {
 }
 
 // Load loads configuration from files, environment variables, and defaults.
-// Priority (highest to lowest): flags > env vars > local config > global config > defaults
+// Priority (highest to lowest): flags > env vars > local config > profile > global config > defaults
 //
 // Local config (.fizzy.yaml) is searched for in the current directory and parent
 // directories. Values from local config override global config values.
+//
+// The active profile is taken from FIZZY_PROFILE, then the local config's
+// profile key, then the global config's profile key. An unknown profile is
+// ignored; use LoadProfile to detect it.
 func Load() *Config {
+	cfg, _ := LoadProfile("")
+	cfg.ResolveToken()
+	return cfg
+}
+
+// LoadProfile loads configuration like Load, selecting the named profile.
+// An empty name falls back to FIZZY_PROFILE and the profile keys in the
+// local and global config files. It returns an error if the selected
+// profile is not defined in the global config, along with the config
+// loaded without it. Unlike Load, it doesn't read the token from a
+// credential store; call ResolveToken once any flags have been applied.
+func LoadProfile(name string) (*Config, error) {
 	cfg := &Config{
 		APIURL: DefaultAPIURL,
 	}
@@ -116,28 +158,47 @@ func Load() *Config {
 		}
 	}
 
-	// Override with local config (walks up directory tree)
+	// Read local config (walks up directory tree)
+	var localCfg *Config
 	if localPath := findLocalConfig(); localPath != "" {
 		if data, err := os.ReadFile(localPath); err == nil {
-			var localCfg Config
-			if yaml.Unmarshal(data, &localCfg) == nil {
-				// Only override non-empty values from local config
-				if localCfg.Token != "" {
-					cfg.Token = localCfg.Token
-				}
-				if localCfg.Account != "" {
-					cfg.Account = localCfg.Account
-				}
-				if localCfg.APIURL != "" {
-					cfg.APIURL = localCfg.APIURL
-				}
-				if localCfg.Board != "" {
-					cfg.Board = localCfg.Board
-				}
+			var parsed Config
+			if yaml.Unmarshal(data, &parsed) == nil {
+				localCfg = &parsed
 			}
 		}
 	}
 
+	// Resolve the active profile
+	if name == "" {
+		name = os.Getenv("FIZZY_PROFILE")
+	}
+	if name == "" && localCfg != nil {
+		name = localCfg.Profile
+	}
+	if name == "" {
+		name = cfg.Profile
+	}
+
+	var profileErr error
+	cfg.Profile = name
+	if name != "" {
+		if profile, ok := cfg.Profiles[name]; ok && profile != nil {
+			// The token and account belong to the profile alone, so a
+			// top-level token is never sent to a profile's server
+			cfg.Token = profile.Token
+			cfg.Account = profile.Account
+			cfg.merge(profile)
+		} else {
+			profileErr = fmt.Errorf("profile %q not found in global config", name)
+		}
+	}
+
+	// Override with local config; only non-empty values override
+	if localCfg != nil {
+		cfg.merge(localCfg)
+	}
+
 	// Override with environment variables
 	if token := os.Getenv("FIZZY_TOKEN"); token != "" {
 		cfg.Token = token
@@ -151,8 +212,113 @@ func Load() *Config {
 	if board := os.Getenv("FIZZY_BOARD"); board != "" {
 		cfg.Board = board
 	}
+	if attempts := os.Getenv("FIZZY_RETRY_ATTEMPTS"); attempts != "" {
+		if n, err := strconv.Atoi(attempts); err == nil {
+			cfg.RetryAttempts = n
+		}
+	}
+	if maxDelay := os.Getenv("FIZZY_RETRY_MAX_DELAY"); maxDelay != "" {
+		cfg.RetryMaxDelay = maxDelay
+	}
+	if timeout := os.Getenv("FIZZY_TIMEOUT"); timeout != "" {
+		cfg.Timeout = timeout
+	}
 
-	return cfg
+	return cfg, profileErr
+}
+
+// ResolveToken reads the token from the credential store if it isn't set
+// directly, so a credential helper only runs when it's needed.
+func (c *Config) ResolveToken() {
+	if c.Token == "" {
+		c.Token, c.CredentialError = c.storedToken()
+	}
+}
+
+// storedToken reads the token from the configured credential store.
+func (c *Config) storedToken() (string, error) {
+	store, err := c.Credentials()
+	if err != nil || store == nil {
+		return "", err
+	}
+	return store.Get(c.CredentialKey())
+}
+
+// Credentials returns the configured credential store, or nil when tokens
+// are kept in the config file.
+func (c *Config) Credentials() (credentials.Store, error) {
+	return credentials.New(c.CredentialStore, ConfigDir())
+}
+
+// CredentialKey identifies this config's token in a credential store.
+func (c *Config) CredentialKey() credentials.Key {
+	return credentials.Key{APIURL: c.APIURL, Profile: c.Profile}
+}
+
+// merge copies the non-empty settings from other into c.
+func (c *Config) merge(other *Config) {
+	if other.Token != "" {
+		c.Token = other.Token
+	}
+	if other.Account != "" {
+		c.Account = other.Account
+	}
+	if other.APIURL != "" {
+		c.APIURL = other.APIURL
+	}
+	if other.Board != "" {
+		c.Board = other.Board
+	}
+	if other.RetryAttempts != 0 {
+		c.RetryAttempts = other.RetryAttempts
+	}
+	if other.RetryMaxDelay != "" {
+		c.RetryMaxDelay = other.RetryMaxDelay
+	}
+	if other.Timeout != "" {
+		c.Timeout = other.Timeout
+	}
+	if other.CredentialStore != "" {
+		c.CredentialStore = other.CredentialStore
+	}
+}
+
+// ProfileNames returns the names of the profiles defined in this config, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnsureProfile returns the named profile, creating it if needed.
+func (c *Config) EnsureProfile(name string) *Config {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Config)
+	}
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		profile = &Config{}
+		c.Profiles[name] = profile
+	}
+	return profile
+}
+
+// RemoveProfile deletes the named profile. It reports whether it existed.
+func (c *Config) RemoveProfile(name string) bool {
+	if _, ok := c.Profiles[name]; !ok {
+		return false
+	}
+	delete(c.Profiles, name)
+	if len(c.Profiles) == 0 {
+		c.Profiles = nil
+	}
+	if c.Profile == name {
+		c.Profile = ""
+	}
+	return true
 }
 
 // LoadGlobal loads configuration only from the global config file(s) and defaults.
@@ -195,6 +361,21 @@ func ConfigPath() (string, error) {
 	return preferred, nil
 }
 
+// ConfigDir returns the directory of the global config file, preferring one
+// that already exists. It does not create the directory.
+func ConfigDir() string {
+	paths := globalConfigPaths()
+	if len(paths) == 0 {
+		return ""
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return filepath.Dir(path)
+		}
+	}
+	return filepath.Dir(paths[0])
+}
+
 // Save saves the configuration to the global config file.
 func (c *Config) Save() error {
 	path, err := ConfigPath()
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/credentials"
 )
 
 func TestDefaultAPIURL(t *testing.T) {
@@ -696,3 +698,263 @@ api_url: https://global.api.url
 		t.Errorf("expected APIURL 'https://env.api.url' (from env), got '%s'", cfg.APIURL)
 	}
 }
+
+func TestLoad_RetrySettings(t *testing.T) {
+	origHome := os.Getenv("HOME")
+	tempDir := t.TempDir()
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tempDir, ".config", "fizzy")
+	os.MkdirAll(configDir, 0700)
+	configContent := `token: file-token
+retry_attempts: 5
+retry_max_delay: 1m
+`
+	os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0600)
+
+	cfg := Load()
+	if cfg.RetryAttempts != 5 {
+		t.Errorf("expected RetryAttempts 5, got %d", cfg.RetryAttempts)
+	}
+	if cfg.RetryMaxDelay != "1m" {
+		t.Errorf("expected RetryMaxDelay '1m', got '%s'", cfg.RetryMaxDelay)
+	}
+
+	os.Setenv("FIZZY_RETRY_ATTEMPTS", "2")
+	os.Setenv("FIZZY_RETRY_MAX_DELAY", "10s")
+	defer func() {
+		os.Unsetenv("FIZZY_RETRY_ATTEMPTS")
+		os.Unsetenv("FIZZY_RETRY_MAX_DELAY")
+	}()
+
+	cfg = Load()
+	if cfg.RetryAttempts != 2 {
+		t.Errorf("expected RetryAttempts 2 from env, got %d", cfg.RetryAttempts)
+	}
+	if cfg.RetryMaxDelay != "10s" {
+		t.Errorf("expected RetryMaxDelay '10s' from env, got '%s'", cfg.RetryMaxDelay)
+	}
+}
+
+func TestLoad_Timeout(t *testing.T) {
+	origHome := os.Getenv("HOME")
+	tempDir := t.TempDir()
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tempDir, ".config", "fizzy")
+	os.MkdirAll(configDir, 0700)
+	os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("token: file-token\ntimeout: 2m\n"), 0600)
+
+	cfg := Load()
+	if cfg.Timeout != "2m" {
+		t.Errorf("expected Timeout '2m', got '%s'", cfg.Timeout)
+	}
+
+	os.Setenv("FIZZY_TIMEOUT", "5s")
+	defer os.Unsetenv("FIZZY_TIMEOUT")
+
+	cfg = Load()
+	if cfg.Timeout != "5s" {
+		t.Errorf("expected Timeout '5s' from env, got '%s'", cfg.Timeout)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	os.Unsetenv("FIZZY_TOKEN")
+	os.Unsetenv("FIZZY_ACCOUNT")
+	os.Unsetenv("FIZZY_API_URL")
+	os.Unsetenv("FIZZY_BOARD")
+
+	globalDir := t.TempDir()
+	SetTestConfigDir(globalDir)
+	defer ResetTestConfigDir()
+
+	workDir := t.TempDir()
+	SetTestWorkingDir(workDir)
+	defer ResetTestWorkingDir()
+
+	globalContent := `token: top-token
+account: top-account
+profiles:
+  work:
+    token: work-token
+    account: work-account
+    api_url: https://fizzy.work.example
+  oss:
+    token: oss-token
+    board: oss-board
+  selfhosted:
+    api_url: https://fizzy.selfhosted.example
+`
+	os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0600)
+
+	t.Run("no profile uses top-level settings", func(t *testing.T) {
+		cfg, err := LoadProfile("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Token != "top-token" || cfg.Account != "top-account" {
+			t.Errorf("expected top-level settings, got token=%s account=%s", cfg.Token, cfg.Account)
+		}
+	})
+
+	t.Run("named profile overrides top-level settings", func(t *testing.T) {
+		cfg, err := LoadProfile("work")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Token != "work-token" || cfg.Account != "work-account" || cfg.APIURL != "https://fizzy.work.example" {
+			t.Errorf("unexpected profile settings: %+v", cfg)
+		}
+		if cfg.Profile != "work" {
+			t.Errorf("expected Profile 'work', got '%s'", cfg.Profile)
+		}
+	})
+
+	t.Run("profile does not inherit the token or account", func(t *testing.T) {
+		cfg, _ := LoadProfile("oss")
+		if cfg.Account != "" {
+			t.Errorf("expected no account, got '%s'", cfg.Account)
+		}
+		if cfg.Board != "oss-board" {
+			t.Errorf("expected board 'oss-board', got '%s'", cfg.Board)
+		}
+	})
+
+	t.Run("profile with its own server and no token has no token", func(t *testing.T) {
+		cfg, err := LoadProfile("selfhosted")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIURL != "https://fizzy.selfhosted.example" {
+			t.Errorf("expected the profile's API URL, got '%s'", cfg.APIURL)
+		}
+		if cfg.Token != "" {
+			t.Errorf("expected the top-level token not to be used, got '%s'", cfg.Token)
+		}
+	})
+
+	t.Run("FIZZY_PROFILE selects profile", func(t *testing.T) {
+		os.Setenv("FIZZY_PROFILE", "oss")
+		defer os.Unsetenv("FIZZY_PROFILE")
+
+		cfg := Load()
+		if cfg.Token != "oss-token" {
+			t.Errorf("expected token 'oss-token', got '%s'", cfg.Token)
+		}
+	})
+
+	t.Run("local profile key selects profile", func(t *testing.T) {
+		localPath := filepath.Join(workDir, LocalConfigFile)
+		os.WriteFile(localPath, []byte("profile: work\nboard: local-board\n"), 0600)
+		defer os.Remove(localPath)
+
+		cfg := Load()
+		if cfg.Token != "work-token" {
+			t.Errorf("expected token 'work-token', got '%s'", cfg.Token)
+		}
+		if cfg.Board != "local-board" {
+			t.Errorf("expected local board to override profile, got '%s'", cfg.Board)
+		}
+	})
+
+	t.Run("unknown profile returns error", func(t *testing.T) {
+		cfg, err := LoadProfile("missing")
+		if err == nil {
+			t.Fatal("expected error for unknown profile")
+		}
+		if cfg.Token != "top-token" {
+			t.Errorf("expected fallback to top-level settings, got '%s'", cfg.Token)
+		}
+	})
+}
+
+func TestProfileHelpers(t *testing.T) {
+	cfg := &Config{}
+	cfg.EnsureProfile("b").Token = "b-token"
+	cfg.EnsureProfile("a")
+
+	names := cfg.ProfileNames()
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("expected sorted names [a b], got %v", names)
+	}
+	if cfg.EnsureProfile("b").Token != "b-token" {
+		t.Error("expected EnsureProfile to return existing profile")
+	}
+	if !cfg.RemoveProfile("a") || cfg.RemoveProfile("a") {
+		t.Error("expected RemoveProfile to report existence")
+	}
+}
+
+func TestLoad_TokenFromCredentialStore(t *testing.T) {
+	os.Unsetenv("FIZZY_TOKEN")
+	os.Unsetenv("FIZZY_API_URL")
+
+	globalDir := t.TempDir()
+	SetTestConfigDir(globalDir)
+	defer ResetTestConfigDir()
+	SetTestWorkingDir(t.TempDir())
+	defer ResetTestWorkingDir()
+
+	os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte("credential_store: file\naccount: acct\n"), 0600)
+
+	store := credentials.NewFileStore(globalDir)
+	if err := store.Set(credentials.Key{APIURL: DefaultAPIURL}, "stored-token"); err != nil {
+		t.Fatalf("failed to store token: %v", err)
+	}
+
+	cfg := Load()
+	if cfg.Token != "stored-token" {
+		t.Errorf("expected token from credential store, got '%s'", cfg.Token)
+	}
+	if cfg.CredentialError != nil {
+		t.Errorf("unexpected credential error: %v", cfg.CredentialError)
+	}
+
+	os.Setenv("FIZZY_TOKEN", "env-token")
+	defer os.Unsetenv("FIZZY_TOKEN")
+	if cfg := Load(); cfg.Token != "env-token" {
+		t.Errorf("expected env token to take precedence, got '%s'", cfg.Token)
+	}
+}
+
+func TestLoad_CredentialStoreError(t *testing.T) {
+	os.Unsetenv("FIZZY_TOKEN")
+
+	globalDir := t.TempDir()
+	SetTestConfigDir(globalDir)
+	defer ResetTestConfigDir()
+	SetTestWorkingDir(t.TempDir())
+	defer ResetTestWorkingDir()
+
+	os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte("credential_store: bogus\n"), 0600)
+
+	cfg := Load()
+	if cfg.Token != "" {
+		t.Errorf("expected empty token, got '%s'", cfg.Token)
+	}
+	if cfg.CredentialError == nil {
+		t.Error("expected CredentialError for unknown store")
+	}
+}
+
+func TestLoad_CredentialHelperNotRunWithToken(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestConfigDir(globalDir)
+	defer ResetTestConfigDir()
+	SetTestWorkingDir(t.TempDir())
+	defer ResetTestWorkingDir()
+
+	os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte("credential_store: helper:does-not-exist\n"), 0600)
+	t.Setenv("FIZZY_TOKEN", "env-token")
+
+	cfg := Load()
+	if cfg.Token != "env-token" {
+		t.Errorf("expected env token, got '%s'", cfg.Token)
+	}
+	if cfg.CredentialError != nil {
+		t.Errorf("expected the credential helper not to run, got %v", cfg.CredentialError)
+	}
+}
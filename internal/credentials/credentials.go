@@ -0,0 +1,64 @@
+// Package credentials provides pluggable storage for API tokens so they
+// do not have to be kept in plaintext config files.
+package credentials
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backend names accepted by New.
+const (
+	// BackendConfig keeps the token in the config file (the default).
+	BackendConfig = "config"
+	// BackendFile keeps tokens in an encrypted file next to the global config.
+	BackendFile = "file"
+	// BackendHelperPrefix selects an external fizzy-credential-<name> program,
+	// e.g. "helper:pass".
+	BackendHelperPrefix = "helper:"
+)
+
+// Key identifies a stored token.
+type Key struct {
+	APIURL  string
+	Profile string
+}
+
+// String returns a stable identifier for the key.
+func (k Key) String() string {
+	profile := k.Profile
+	if profile == "" {
+		profile = "default"
+	}
+	return profile + "@" + k.APIURL
+}
+
+// Store reads and writes tokens.
+type Store interface {
+	// Get returns the token for key, or "" if none is stored.
+	Get(key Key) (string, error)
+	// Set stores the token for key.
+	Set(key Key, token string) error
+	// Delete removes the token for key. Deleting a missing token is not an error.
+	Delete(key Key) error
+}
+
+// New returns the store for a backend name. configDir is the directory of
+// the global config file, used by the file backend. It returns nil for the
+// config backend, since those tokens are read from the config file directly.
+func New(backend, configDir string) (Store, error) {
+	switch {
+	case backend == "" || backend == BackendConfig:
+		return nil, nil
+	case backend == BackendFile:
+		return NewFileStore(configDir), nil
+	case strings.HasPrefix(backend, BackendHelperPrefix):
+		name := strings.TrimPrefix(backend, BackendHelperPrefix)
+		if name == "" {
+			return nil, fmt.Errorf("credential helper name is required (helper:NAME)")
+		}
+		return NewHelperStore(name), nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (expected config, file, or helper:NAME)", backend)
+	}
+}
@@ -0,0 +1,179 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		backend string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"config", "", false},
+		{"file", "*credentials.FileStore", false},
+		{"helper:pass", "*credentials.HelperStore", false},
+		{"helper:", "", true},
+		{"keychain", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			store, err := New(tt.backend, t.TempDir())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.backend)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch tt.want {
+			case "":
+				if store != nil {
+					t.Errorf("expected nil store, got %T", store)
+				}
+			case "*credentials.FileStore":
+				if _, ok := store.(*FileStore); !ok {
+					t.Errorf("expected FileStore, got %T", store)
+				}
+			case "*credentials.HelperStore":
+				if h, ok := store.(*HelperStore); !ok || h.Program() != "fizzy-credential-pass" {
+					t.Errorf("expected fizzy-credential-pass helper, got %T", store)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	if got := (Key{APIURL: "https://app.fizzy.do"}).String(); got != "default@https://app.fizzy.do" {
+		t.Errorf("unexpected key: %s", got)
+	}
+	if got := (Key{APIURL: "https://x", Profile: "work"}).String(); got != "work@https://x" {
+		t.Errorf("unexpected key: %s", got)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		name := "key file"
+		if passphrase != "" {
+			name = "passphrase"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(PassphraseEnv, passphrase)
+			dir := t.TempDir()
+			store := NewFileStore(dir)
+			work := Key{APIURL: "https://app.fizzy.do", Profile: "work"}
+			oss := Key{APIURL: "https://fizzy.example.com", Profile: "oss"}
+
+			if token, err := store.Get(work); err != nil || token != "" {
+				t.Fatalf("expected empty token before Set, got %q (%v)", token, err)
+			}
+			if err := store.Set(work, "work-token"); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+			if err := store.Set(oss, "oss-token"); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			raw, _ := os.ReadFile(filepath.Join(dir, FileName))
+			if strings.Contains(string(raw), "work-token") {
+				t.Error("expected token to be encrypted on disk")
+			}
+
+			if token, _ := NewFileStore(dir).Get(work); token != "work-token" {
+				t.Errorf("expected 'work-token', got %q", token)
+			}
+			if err := store.Delete(work); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if token, _ := store.Get(work); token != "" {
+				t.Errorf("expected token to be deleted, got %q", token)
+			}
+			if token, _ := store.Get(oss); token != "oss-token" {
+				t.Errorf("expected other token to be kept, got %q", token)
+			}
+		})
+	}
+}
+
+func TestFileStore_WrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(PassphraseEnv, "right")
+	NewFileStore(dir).Set(Key{APIURL: "https://x"}, "secret")
+
+	t.Setenv(PassphraseEnv, "wrong")
+	if _, err := NewFileStore(dir).Get(Key{APIURL: "https://x"}); err == nil {
+		t.Error("expected decrypt error with wrong passphrase")
+	}
+}
+
+// installFakeHelper writes a fizzy-credential-fake script that stores tokens
+// in files under dir and puts it on PATH.
+func installFakeHelper(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake credential helper requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	dataDir := t.TempDir()
+	script := `#!/bin/sh
+set -e
+input=$(cat)
+profile=$(printf '%s\n' "$input" | sed -n 's/^profile=//p')
+token=$(printf '%s\n' "$input" | sed -n 's/^token=//p')
+file="` + dataDir + `/${profile:-default}"
+case "$1" in
+  get) [ -f "$file" ] && printf 'token=%s\n' "$(cat "$file")" || true ;;
+  store) printf '%s' "$token" > "$file" ;;
+  erase) rm -f "$file" ;;
+  *) exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "fizzy-credential-fake"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dataDir
+}
+
+func TestHelperStore(t *testing.T) {
+	dataDir := installFakeHelper(t)
+	store := NewHelperStore("fake")
+	key := Key{APIURL: "https://app.fizzy.do", Profile: "work"}
+
+	if token, err := store.Get(key); err != nil || token != "" {
+		t.Fatalf("expected empty token, got %q (%v)", token, err)
+	}
+	if err := store.Set(key, "helper-token"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dataDir, "work")); string(data) != "helper-token" {
+		t.Errorf("expected helper to receive token, got %q", string(data))
+	}
+	if token, err := store.Get(key); err != nil || token != "helper-token" {
+		t.Errorf("expected 'helper-token', got %q (%v)", token, err)
+	}
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if token, _ := store.Get(key); token != "" {
+		t.Errorf("expected token to be erased, got %q", token)
+	}
+}
+
+func TestHelperStore_MissingProgram(t *testing.T) {
+	store := NewHelperStore("does-not-exist-anywhere")
+	if _, err := store.Get(Key{APIURL: "https://x"}); err == nil {
+		t.Error("expected error for missing helper program")
+	}
+}
@@ -0,0 +1,197 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// FileName is the encrypted credentials file in the config directory.
+	FileName = "credentials.enc"
+	// KeyFileName holds the random encryption key when no passphrase is set.
+	// It sits next to the credentials file, so without a passphrase the
+	// encryption only keeps tokens out of plain sight; it doesn't protect
+	// them from anyone who can read the config directory.
+	KeyFileName = "credentials.key"
+	// PassphraseEnv, when set, derives the encryption key from a passphrase
+	// instead of the key file.
+	PassphraseEnv = "FIZZY_CREDENTIAL_PASSPHRASE"
+
+	fileVersion      = 1
+	pbkdf2Iterations = 210000
+)
+
+// FileStore keeps tokens in an AES-GCM encrypted file. Only a passphrase
+// (FIZZY_CREDENTIAL_PASSPHRASE) protects them from other readers of the
+// config directory; with the key file they are merely obscured.
+type FileStore struct {
+	Dir string
+}
+
+// encryptedFile is the on-disk format of the credentials file.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// NewFileStore creates a file store in dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Get returns the token for key.
+func (f *FileStore) Get(key Key) (string, error) {
+	tokens, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	return tokens[key.String()], nil
+}
+
+// Set stores the token for key.
+func (f *FileStore) Set(key Key, token string) error {
+	tokens, err := f.load()
+	if err != nil {
+		return err
+	}
+	tokens[key.String()] = token
+	return f.save(tokens)
+}
+
+// Delete removes the token for key.
+func (f *FileStore) Delete(key Key) error {
+	tokens, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key.String()]; !ok {
+		return nil
+	}
+	delete(tokens, key.String())
+	return f.save(tokens)
+}
+
+func (f *FileStore) path() string {
+	return filepath.Join(f.Dir, FileName)
+}
+
+func (f *FileStore) load() (map[string]string, error) {
+	tokens := map[string]string{}
+
+	raw, err := os.ReadFile(f.path())
+	if os.IsNotExist(err) {
+		return tokens, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %v", err)
+	}
+	if file.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported credentials file version %d", file.Version)
+	}
+
+	gcm, err := f.cipher(file.Salt, false)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file (wrong passphrase or key?)")
+	}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid credentials file contents: %v", err)
+	}
+	return tokens, nil
+}
+
+func (f *FileStore) save(tokens map[string]string) error {
+	if err := os.MkdirAll(f.Dir, 0700); err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := f.cipher(salt, true)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(encryptedFile{
+		Version: fileVersion,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.path(), out, 0600)
+}
+
+// cipher builds the AES-GCM cipher, deriving the key from the passphrase if
+// set or from the key file otherwise. create allows generating the key file.
+func (f *FileStore) cipher(salt []byte, create bool) (cipher.AEAD, error) {
+	var key []byte
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		key = pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, 32, sha256.New)
+	} else {
+		var err error
+		key, err = f.keyFile(create)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (f *FileStore) keyFile(create bool) ([]byte, error) {
+	path := filepath.Join(f.Dir, KeyFileName)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid credentials key file %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("credentials key file not readable: %v", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
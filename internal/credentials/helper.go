@@ -0,0 +1,75 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// HelperStore delegates to an external fizzy-credential-<name> program,
+// in the style of git credential helpers.
+//
+// The helper is run as "fizzy-credential-<name> get|store|erase" with
+// key=value lines on stdin (api_url, profile, and token for store). For get,
+// it prints "token=<value>" on stdout, or nothing if no token is stored.
+type HelperStore struct {
+	Name string
+}
+
+// NewHelperStore creates a store backed by fizzy-credential-<name>.
+func NewHelperStore(name string) *HelperStore {
+	return &HelperStore{Name: name}
+}
+
+// Program returns the helper executable name.
+func (h *HelperStore) Program() string {
+	return "fizzy-credential-" + h.Name
+}
+
+// Get returns the token for key.
+func (h *HelperStore) Get(key Key) (string, error) {
+	out, err := h.run("get", key, "")
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if k, v, ok := strings.Cut(scanner.Text(), "="); ok && k == "token" {
+			return v, nil
+		}
+	}
+	return "", nil
+}
+
+// Set stores the token for key.
+func (h *HelperStore) Set(key Key, token string) error {
+	_, err := h.run("store", key, token)
+	return err
+}
+
+// Delete removes the token for key.
+func (h *HelperStore) Delete(key Key) error {
+	_, err := h.run("erase", key, "")
+	return err
+}
+
+func (h *HelperStore) run(action string, key Key, token string) ([]byte, error) {
+	var input strings.Builder
+	fmt.Fprintf(&input, "api_url=%s\n", key.APIURL)
+	fmt.Fprintf(&input, "profile=%s\n", key.Profile)
+	if token != "" {
+		fmt.Fprintf(&input, "token=%s\n", token)
+	}
+
+	cmd := exec.Command(h.Program(), action)
+	cmd.Stdin = strings.NewReader(input.String())
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %s %s failed: %v", h.Program(), action, err)
+	}
+	return out, nil
+}
@@ -13,6 +13,8 @@ const (
 	ExitNotFound    = 5
 	ExitValidation  = 6
 	ExitNetwork     = 7
+	ExitRateLimited = 8
+	ExitCancelled   = 130
 )
 
 // CLIError represents an error with an associated exit code.
@@ -21,6 +23,11 @@ type CLIError struct {
 	Message  string
 	Status   int
 	ExitCode int
+	Details  interface{}
+
+	// Partial holds results fetched before the error, e.g. the pages read
+	// before a paginated request was cancelled.
+	Partial interface{}
 }
 
 func (e *CLIError) Error() string {
@@ -85,6 +92,16 @@ func NewNetworkError(message string) *CLIError {
 	}
 }
 
+// NewRateLimitError creates a rate limited error.
+func NewRateLimitError(message string) *CLIError {
+	return &CLIError{
+		Code:     "RATE_LIMITED",
+		Message:  message,
+		Status:   429,
+		ExitCode: ExitRateLimited,
+	}
+}
+
 // NewInvalidArgsError creates an invalid arguments error.
 func NewInvalidArgsError(message string) *CLIError {
 	return &CLIError{
@@ -94,6 +111,33 @@ func NewInvalidArgsError(message string) *CLIError {
 	}
 }
 
+// NewCancelledError creates an error for a request cancelled by the user
+// (e.g. Ctrl-C).
+func NewCancelledError(message string) *CLIError {
+	return &CLIError{
+		Code:     "CANCELLED",
+		Message:  message,
+		ExitCode: ExitCancelled,
+	}
+}
+
+// NewConflictError creates an error for a change that would overwrite
+// someone else's edit.
+func NewConflictError(message string) *CLIError {
+	return &CLIError{
+		Code:     "CONFLICT",
+		Message:  message,
+		Status:   409,
+		ExitCode: ExitError,
+	}
+}
+
+// WithDetails attaches structured details to the error and returns it.
+func (e *CLIError) WithDetails(details interface{}) *CLIError {
+	e.Details = details
+	return e
+}
+
 // FromHTTPStatus creates an appropriate error from an HTTP status code.
 func FromHTTPStatus(status int, message string) *CLIError {
 	switch status {
@@ -105,6 +149,8 @@ func FromHTTPStatus(status int, message string) *CLIError {
 		return NewNotFoundError(message)
 	case 422:
 		return NewValidationError(message)
+	case 429:
+		return NewRateLimitError(message)
 	default:
 		return &CLIError{
 			Code:     "ERROR",
@@ -18,6 +18,8 @@ func TestExitCodes(t *testing.T) {
 		{"ExitNotFound", ExitNotFound, 5},
 		{"ExitValidation", ExitValidation, 6},
 		{"ExitNetwork", ExitNetwork, 7},
+		{"ExitRateLimited", ExitRateLimited, 8},
+		{"ExitCancelled", ExitCancelled, 130},
 	}
 
 	for _, tt := range tests {
@@ -136,6 +138,20 @@ func TestNewNetworkError(t *testing.T) {
 	}
 }
 
+func TestNewRateLimitError(t *testing.T) {
+	err := NewRateLimitError("too many requests")
+
+	if err.Code != "RATE_LIMITED" {
+		t.Errorf("expected code 'RATE_LIMITED', got '%s'", err.Code)
+	}
+	if err.Status != 429 {
+		t.Errorf("expected status 429, got %d", err.Status)
+	}
+	if err.ExitCode != ExitRateLimited {
+		t.Errorf("expected exit code %d, got %d", ExitRateLimited, err.ExitCode)
+	}
+}
+
 func TestNewInvalidArgsError(t *testing.T) {
 	err := NewInvalidArgsError("missing required flag")
 
@@ -150,6 +166,31 @@ func TestNewInvalidArgsError(t *testing.T) {
 	}
 }
 
+func TestNewCancelledError(t *testing.T) {
+	err := NewCancelledError("request cancelled")
+
+	if err.Code != "CANCELLED" {
+		t.Errorf("expected code 'CANCELLED', got '%s'", err.Code)
+	}
+	if err.ExitCode != ExitCancelled {
+		t.Errorf("expected exit code %d, got %d", ExitCancelled, err.ExitCode)
+	}
+}
+
+func TestNewConflictError(t *testing.T) {
+	err := NewConflictError("card changed")
+
+	if err.Code != "CONFLICT" {
+		t.Errorf("expected code 'CONFLICT', got '%s'", err.Code)
+	}
+	if err.Status != 409 {
+		t.Errorf("expected status 409, got %d", err.Status)
+	}
+	if err.ExitCode != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, err.ExitCode)
+	}
+}
+
 func TestFromHTTPStatus(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -162,6 +203,7 @@ func TestFromHTTPStatus(t *testing.T) {
 		{"403 Forbidden", 403, "Forbidden", "FORBIDDEN", ExitForbidden},
 		{"404 Not Found", 404, "Not Found", "NOT_FOUND", ExitNotFound},
 		{"422 Unprocessable", 422, "Validation failed", "VALIDATION_ERROR", ExitValidation},
+		{"429 Too Many Requests", 429, "Too Many Requests", "RATE_LIMITED", ExitRateLimited},
 		{"500 Server Error", 500, "Internal Server Error", "ERROR", ExitError},
 		{"502 Bad Gateway", 502, "Bad Gateway", "ERROR", ExitError},
 	}
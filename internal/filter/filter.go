@@ -0,0 +1,344 @@
+// Package filter matches and sorts JSON items (as decoded into
+// map[string]interface{}) with small expressions such as
+//
+//	assignees = [] and created_at > -7d
+//	column.name = "In progress" or (golden and not closed)
+//	steps.completed = false
+//
+// A comparison is a field path, an operator and a value. Paths follow
+// nested objects with dots; when they pass through a list, the comparison
+// holds if it holds for any element. The operators are = and != (strings
+// compare case-insensitively), <, <=, >, >=, and ~ and !~, which test for a
+// substring. Values are quoted or bare strings, numbers, true, false, null,
+// [] (an empty list), dates (2024-05-01), times (RFC 3339) and times
+// relative to now (-7d, -12h, -30m, -2w). A field on its own tests that it
+// is set: not null, false, zero, "" or []. Comparisons combine with and, or,
+// not and parentheses.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// now returns the current time (can be overridden for testing).
+var now = time.Now
+
+var (
+	relativeTimeRE = regexp.MustCompile(`^([-+]?)(\d+)([mhdw])$`)
+	dateRE         = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Expr is a parsed filter expression.
+type Expr struct {
+	root node
+}
+
+// Parse parses a filter expression. Relative times are resolved against
+// the current time.
+func Parse(src string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, now: now()}
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("empty expression")
+	}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos+1)
+	}
+	return &Expr{root: root}, nil
+}
+
+// Match reports whether item matches the expression.
+func (e *Expr) Match(item interface{}) bool {
+	return e.root.match(item)
+}
+
+// Uses reports whether the expression refers to field (the first part of
+// a path), e.g. to fetch fields that list responses leave out.
+func (e *Expr) Uses(field string) bool {
+	return e.root.uses(field)
+}
+
+type node interface {
+	match(item interface{}) bool
+	uses(field string) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(item interface{}) bool { return n.left.match(item) && n.right.match(item) }
+func (n andNode) uses(field string) bool      { return n.left.uses(field) || n.right.uses(field) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(item interface{}) bool { return n.left.match(item) || n.right.match(item) }
+func (n orNode) uses(field string) bool      { return n.left.uses(field) || n.right.uses(field) }
+
+type notNode struct{ operand node }
+
+func (n notNode) match(item interface{}) bool { return !n.operand.match(item) }
+func (n notNode) uses(field string) bool      { return n.operand.uses(field) }
+
+// setNode tests that a field is set.
+type setNode struct{ path []string }
+
+func (n setNode) match(item interface{}) bool { return truthy(Lookup(item, n.path)) }
+func (n setNode) uses(field string) bool      { return n.path[0] == field }
+
+type compareNode struct {
+	path []string
+	op   string
+	val  value
+}
+
+func (n compareNode) uses(field string) bool { return n.path[0] == field }
+
+func (n compareNode) match(item interface{}) bool {
+	found := Lookup(item, n.path)
+	switch n.val.kind {
+	case kindEmptyList:
+		list, isList := found.([]interface{})
+		empty := found == nil || isList && len(list) == 0
+		return empty == (n.op == "=")
+	case kindNull:
+		return (found == nil) == (n.op == "=")
+	}
+
+	switch n.op {
+	case "!=":
+		return !anyLeaf(found, func(v interface{}) bool { return n.val.compare(v, "=") })
+	case "!~":
+		return !anyLeaf(found, func(v interface{}) bool { return n.val.compare(v, "~") })
+	}
+	return anyLeaf(found, func(v interface{}) bool { return n.val.compare(v, n.op) })
+}
+
+// Lookup returns the value at a path in item. Paths through lists collect
+// the value from each element into a list.
+func Lookup(item interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return item
+	}
+	switch v := item.(type) {
+	case map[string]interface{}:
+		return Lookup(v[path[0]], path[1:])
+	case []interface{}:
+		out := []interface{}{}
+		for _, el := range v {
+			if found := Lookup(el, path); found != nil {
+				if list, ok := found.([]interface{}); ok {
+					out = append(out, list...)
+				} else {
+					out = append(out, found)
+				}
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// ParsePath splits a dotted field path.
+func ParsePath(field string) ([]string, error) {
+	path := strings.Split(field, ".")
+	for _, part := range path {
+		if part == "" {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+	}
+	return path, nil
+}
+
+// anyLeaf reports whether f holds for v or, if v is a list, any of its
+// elements.
+func anyLeaf(v interface{}, f func(interface{}) bool) bool {
+	if list, ok := v.([]interface{}); ok {
+		for _, el := range list {
+			if anyLeaf(el, f) {
+				return true
+			}
+		}
+		return false
+	}
+	return v != nil && f(v)
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != ""
+	case float64:
+		return val != 0
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return true
+}
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindBool
+	kindNull
+	kindEmptyList
+	kindTime
+)
+
+// value is the right-hand side of a comparison.
+type value struct {
+	kind valueKind
+	text string
+	num  float64
+	b    bool
+	// A time value covers [start, end): a whole day for dates, an instant
+	// otherwise.
+	start, end time.Time
+}
+
+// parseValue interprets a bare value.
+func parseValue(text string, now time.Time) value {
+	switch strings.ToLower(text) {
+	case "true", "false":
+		return value{kind: kindBool, text: text, b: strings.EqualFold(text, "true")}
+	case "null":
+		return value{kind: kindNull, text: text}
+	}
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		return value{kind: kindNumber, text: text, num: n}
+	}
+	if m := relativeTimeRE.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[3]]
+		d := time.Duration(n) * unit
+		if m[1] == "-" {
+			d = -d
+		}
+		t := now.Add(d)
+		return value{kind: kindTime, text: text, start: t, end: t}
+	}
+	if dateRE.MatchString(text) {
+		if t, err := time.ParseInLocation(time.DateOnly, text, now.Location()); err == nil {
+			return value{kind: kindTime, text: text, start: t, end: t.AddDate(0, 0, 1)}
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return value{kind: kindTime, text: text, start: t, end: t}
+	}
+	return value{kind: kindString, text: text}
+}
+
+// compare applies op to a field value v and the value.
+func (val value) compare(v interface{}, op string) bool {
+	if op == "~" {
+		return strings.Contains(strings.ToLower(leafText(v)), strings.ToLower(val.text))
+	}
+
+	switch val.kind {
+	case kindBool:
+		b, ok := v.(bool)
+		return ok && op == "=" && b == val.b
+	case kindNumber:
+		n, ok := number(v)
+		return ok && ordered(compareFloats(n, val.num), op)
+	case kindTime:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return false
+		}
+		// Instants include their end, days don't
+		instant := val.start.Equal(val.end)
+		beforeEnd := t.Before(val.end) || instant && t.Equal(val.end)
+		switch op {
+		case "=":
+			return !t.Before(val.start) && beforeEnd
+		case "<":
+			return t.Before(val.start)
+		case "<=":
+			return beforeEnd
+		case ">":
+			return !beforeEnd
+		case ">=":
+			return !t.Before(val.start)
+		}
+		return false
+	default:
+		s := leafText(v)
+		if op == "=" {
+			return strings.EqualFold(s, val.text)
+		}
+		return ordered(strings.Compare(strings.ToLower(s), strings.ToLower(val.text)), op)
+	}
+}
+
+// ordered applies an ordering operator to the result of a comparison.
+func ordered(cmp int, op string) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// leafText formats a scalar field value for string comparisons.
+func leafText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package filter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func decode(t *testing.T, src string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMatch(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	card := decode(t, `{
+		"number": 42,
+		"title": "Fix the Login page",
+		"golden": false,
+		"closed": false,
+		"tags": ["bug", "auth"],
+		"assignees": [],
+		"column": {"name": "In progress"},
+		"image_url": null,
+		"created_at": "2024-05-08T09:30:00Z",
+		"steps": [
+			{"content": "Reproduce", "completed": true},
+			{"content": "Fix", "completed": false}
+		]
+	}`)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"number = 42", true},
+		{"number == 42.0", true},
+		{"number > 40 and number <= 42", true},
+		{"number < 42", false},
+		{"title = 'fix the login page'", true},
+		{"title ~ login", true},
+		{"title !~ LOGIN", false},
+		{`column.name = "In progress"`, true},
+		{"column.name != done", true},
+		{"tags = bug", true},
+		{"tags != bug", false},
+		{"tags = ui", false},
+		{"tags ~ au", true},
+		{"tags = []", false},
+		{"assignees = []", true},
+		{"assignees != []", false},
+		{"assignees.name = Ann", false},
+		{"image_url = null", true},
+		{"missing = null", true},
+		{"column != null", true},
+		{"steps.completed = false", true},
+		{"steps.content = Deploy", false},
+		{"golden", false},
+		{"not golden", true},
+		{"tags", true},
+		{"assignees", false},
+		{"closed = false", true},
+		{"closed = true", false},
+		{"created_at > -7d", true},
+		{"created_at > -1d", false},
+		{"created_at < -1d", true},
+		{"created_at = 2024-05-08", true},
+		{"created_at > 2024-05-08", false},
+		{"created_at >= 2024-05-08", true},
+		{"created_at < 2024-05-08", false},
+		{"created_at <= 2024-05-08", true},
+		{"created_at > 2024-05-07", true},
+		{"created_at < 2024-05-08T10:00:00Z", true},
+		{"golden or title ~ login", true},
+		{"golden || (number = 1 and tags = bug)", false},
+		{"not (golden or closed) && tags = auth", true},
+		{"!golden", true},
+		{"GOLDEN OR NOT CLOSED", true},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := expr.Match(card); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "empty expression"},
+		{"title =", "expected a value after = but found end of expression at position 8"},
+		{"title = 'open", "unterminated string at position 9"},
+		{"(golden", "expected ) but found end of expression at position 8"},
+		{"golden closed", `unexpected "closed" at position 8`},
+		{"tags = [bug]", "only [] (an empty list) is supported at position 8"},
+		{"tags > []", "[] can only be compared with = or !="},
+		{"= 1", `expected a field but found "=" at position 1`},
+		{"a..b = 1", `invalid field "a..b"`},
+		{"a & b", `unexpected "&" at position 3`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestUses(t *testing.T) {
+	expr, err := Parse("title ~ x or not steps.completed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expr.Uses("steps") || !expr.Uses("title") || expr.Uses("completed") {
+		t.Error("unexpected fields in use")
+	}
+}
+
+func TestSort(t *testing.T) {
+	items := decode(t, `[
+		{"n": 1, "title": "beta", "at": "2024-05-01T10:00:00+02:00", "tags": ["a"]},
+		{"n": 2, "title": "Alpha", "at": "2024-05-01T09:00:00Z", "tags": []},
+		{"n": 3, "title": "alpha", "tags": ["a", "b"]},
+		{"n": 4, "title": "gamma", "at": "2024-04-01T00:00:00Z", "tags": ["c"]}
+	]`).([]interface{})
+
+	numbers := func() []float64 {
+		var out []float64
+		for _, item := range items {
+			out = append(out, item.(map[string]interface{})["n"].(float64))
+		}
+		return out
+	}
+
+	tests := []struct {
+		spec string
+		want []float64
+	}{
+		{"title", []float64{2, 3, 1, 4}},
+		{"title:desc,n:desc", []float64{4, 1, 3, 2}},
+		{"at", []float64{4, 1, 2, 3}},
+		{"at:desc", []float64{2, 1, 4, 3}},
+		{"tags:desc", []float64{3, 1, 4, 2}},
+		{"n:asc", []float64{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		keys, err := ParseSort(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSort(%q): %v", tt.spec, err)
+			continue
+		}
+		Sort(items, keys)
+		if got := numbers(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort by %q = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	if _, err := ParseSort("title:up"); err == nil || err.Error() != `invalid sort direction "up" (use asc or desc)` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := ParseSort("title,"); err == nil {
+		t.Error("expected an error for an empty field")
+	}
+}
@@ -0,0 +1,231 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenEmptyList
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits an expression into tokens.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(' || r == ')':
+			kind := tokenLParen
+			if r == ')' {
+				kind = tokenRParen
+			}
+			tokens = append(tokens, token{kind, string(r), start})
+			i++
+		case r == '[':
+			i++
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+			if i >= len(runes) || runes[i] != ']' {
+				return nil, fmt.Errorf("only [] (an empty list) is supported at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, token{tokenEmptyList, "[]", start})
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, token{tokenString, b.String(), start})
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(r), start+1)
+			}
+			kind := tokenAnd
+			if r == '|' {
+				kind = tokenOr
+			}
+			tokens = append(tokens, token{kind, string(runes[i : i+2]), start})
+			i += 2
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' && r != '~' || runes[i+1] == '~' && r == '!') {
+				op += string(runes[i+1])
+			}
+			i += len([]rune(op))
+			switch op {
+			case "!":
+				tokens = append(tokens, token{tokenNot, op, start})
+				continue
+			case "==":
+				op = "="
+			}
+			tokens = append(tokens, token{tokenOp, op, start})
+		default:
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()[]=!<>~"'&|`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokenWord
+			switch strings.ToLower(word) {
+			case "and":
+				kind = tokenAnd
+			case "or":
+				kind = tokenOr
+			case "not":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind, word, start})
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) but found %s at position %d", closing, closing.pos+1)
+		}
+		return inner, nil
+	case tokenWord:
+	default:
+		return nil, fmt.Errorf("expected a field but found %s at position %d", t, t.pos+1)
+	}
+
+	path, err := ParsePath(t.text)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenOp {
+		return setNode{path}, nil
+	}
+	op := p.next().text
+
+	v := p.next()
+	var val value
+	switch v.kind {
+	case tokenString:
+		val = value{kind: kindString, text: v.text}
+	case tokenWord:
+		val = parseValue(v.text, p.now)
+	case tokenEmptyList:
+		val = value{kind: kindEmptyList, text: v.text}
+	default:
+		return nil, fmt.Errorf("expected a value after %s but found %s at position %d", op, v, v.pos+1)
+	}
+	if (val.kind == kindEmptyList || val.kind == kindNull) && op != "=" && op != "!=" {
+		return nil, fmt.Errorf("%s can only be compared with = or !=", val.text)
+	}
+	return compareNode{path: path, op: op, val: val}, nil
+}
@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is a field to sort by.
+type SortKey struct {
+	Path       []string
+	Descending bool
+}
+
+// ParseSort parses a comma-separated list of fields, each optionally
+// followed by :asc or :desc, e.g. "last_active_at:desc,number".
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		field, dir, _ := strings.Cut(strings.TrimSpace(part), ":")
+		path, err := ParsePath(field)
+		if err != nil {
+			return nil, err
+		}
+		key := SortKey{Path: path}
+		switch strings.ToLower(dir) {
+		case "", "asc":
+		case "desc":
+			key.Descending = true
+		default:
+			return nil, fmt.Errorf("invalid sort direction %q (use asc or desc)", dir)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sort sorts items by keys, keeping the order of items that compare equal.
+// Items without a value for a key sort last in either direction.
+func Sort(items []interface{}, keys []SortKey) {
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := Lookup(items[i], key.Path), Lookup(items[j], key.Path)
+			if a == nil || b == nil {
+				if (a == nil) != (b == nil) {
+					return b == nil
+				}
+				continue
+			}
+			cmp := compareValues(a, b)
+			if key.Descending {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareValues orders two field values: numbers numerically, times
+// chronologically, other strings case-insensitively, false before true and
+// lists by length.
+func compareValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return compareFloats(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			tx, errX := time.Parse(time.RFC3339, x)
+			ty, errY := time.Parse(time.RFC3339, y)
+			if errX == nil && errY == nil {
+				return tx.Compare(ty)
+			}
+			return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case x:
+				return 1
+			}
+			return -1
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			return len(x) - len(y)
+		}
+	}
+	return strings.Compare(leafText(a), leafText(b))
+}
@@ -0,0 +1,463 @@
+package markdown
+
+import (
+	stdhtml "html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	autolinkRE   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailLinkRE  = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+	inlineHTMLRE = regexp.MustCompile(`^(?:<!--[\s\S]*?-->|</?[a-zA-Z][a-zA-Z0-9-]*(?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>)`)
+	entityRE     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	tagRE        = regexp.MustCompile(`<[^>]*>`)
+)
+
+// node is a piece of inline output: rendered HTML, or a run of emphasis
+// delimiters waiting to be matched.
+type node struct {
+	html string
+
+	delim  byte // '*', '_' or '~'
+	count  int
+	open   bool
+	close  bool
+	active bool
+	before string // closing tags rendered before the remaining delimiters
+	after  string // opening tags rendered after them
+}
+
+func (c *converter) renderInline(text string) string {
+	nodes := c.parseInline(text)
+	processEmphasis(nodes)
+
+	var b strings.Builder
+	for _, n := range nodes {
+		if n.delim == 0 {
+			b.WriteString(n.html)
+			continue
+		}
+		b.WriteString(n.before)
+		b.WriteString(strings.Repeat(string(n.delim), n.count))
+		b.WriteString(n.after)
+	}
+	return b.String()
+}
+
+func (c *converter) parseInline(s string) []*node {
+	var nodes []*node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, &node{html: text.String()})
+			text.Reset()
+		}
+	}
+	raw := func(html string) {
+		flush()
+		nodes = append(nodes, &node{html: html})
+	}
+
+	for i := 0; i < len(s); {
+		ch := s[i]
+		switch ch {
+		case '\\':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				raw("<br>\n")
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && isASCIIPunct(s[i+1]) {
+				text.WriteString(escapeHTML(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+
+		case '\n':
+			// Two trailing spaces make a hard break
+			current := text.String()
+			trimmed := strings.TrimRight(current, " ")
+			text.Reset()
+			text.WriteString(trimmed)
+			if len(current)-len(trimmed) >= 2 {
+				raw("<br>\n")
+			} else {
+				text.WriteString("\n")
+			}
+			i++
+			continue
+
+		case '`':
+			run := runLength(s, i, '`')
+			if end := findBacktickRun(s, i+run, run); end >= 0 {
+				code := strings.ReplaceAll(s[i+run:end], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+					code = code[1 : len(code)-1]
+				}
+				raw("<code>" + escapeHTML(code) + "</code>")
+				i = end + run
+				continue
+			}
+			text.WriteString(s[i : i+run])
+			i += run
+			continue
+
+		case '*', '_', '~':
+			run := runLength(s, i, ch)
+			if ch == '~' && run != 2 {
+				text.WriteString(s[i : i+run])
+				i += run
+				continue
+			}
+			flush()
+			nodes = append(nodes, delimiterRun(s, i, run))
+			i += run
+			continue
+
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				if html, end, ok := c.parseLink(s, i+1, true); ok {
+					raw(html)
+					i = end
+					continue
+				}
+			}
+
+		case '[':
+			if html, end, ok := c.parseLink(s, i, false); ok {
+				raw(html)
+				i = end
+				continue
+			}
+
+		case '<':
+			rest := s[i:]
+			if m := autolinkRE.FindStringSubmatch(rest); m != nil {
+				if safeURL(m[1]) {
+					raw(`<a href="` + escapeHTML(m[1]) + `">` + escapeHTML(m[1]) + `</a>`)
+				} else {
+					text.WriteString(escapeHTML(m[0]))
+				}
+				i += len(m[0])
+				continue
+			}
+			if m := emailLinkRE.FindStringSubmatch(rest); m != nil {
+				raw(`<a href="mailto:` + escapeHTML(m[1]) + `">` + escapeHTML(m[1]) + `</a>`)
+				i += len(m[0])
+				continue
+			}
+			if m := inlineHTMLRE.FindString(rest); m != "" {
+				if strings.HasPrefix(m, "<!--") {
+					raw(m)
+				} else if tag, ok := sanitizeTag(m); ok {
+					raw(tag)
+				} else {
+					text.WriteString(escapeHTML(m))
+				}
+				i += len(m)
+				continue
+			}
+
+		case '&':
+			if m := entityRE.FindString(s[i:]); m != "" {
+				text.WriteString(m)
+				i += len(m)
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		text.WriteString(escapeHTML(s[i : i+size]))
+		i += size
+	}
+	flush()
+	return nodes
+}
+
+// delimiterRun builds a node for the run of n delimiters at s[i], working
+// out from the surrounding characters whether it can open or close emphasis.
+func delimiterRun(s string, i, n int) *node {
+	ch := s[i]
+	before, after := ' ', ' '
+	if i > 0 {
+		before, _ = utf8.DecodeLastRuneInString(s[:i])
+	}
+	if i+n < len(s) {
+		after, _ = utf8.DecodeRuneInString(s[i+n:])
+	}
+
+	leftFlanking := !unicode.IsSpace(after) &&
+		(!isPunct(after) || unicode.IsSpace(before) || isPunct(before))
+	rightFlanking := !unicode.IsSpace(before) &&
+		(!isPunct(before) || unicode.IsSpace(after) || isPunct(after))
+
+	d := &node{delim: ch, count: n, active: true}
+	if ch == '_' {
+		d.open = leftFlanking && (!rightFlanking || isPunct(before))
+		d.close = rightFlanking && (!leftFlanking || isPunct(after))
+	} else {
+		d.open = leftFlanking
+		d.close = rightFlanking
+	}
+	return d
+}
+
+// processEmphasis matches delimiter runs into <em>, <strong> and <del>
+// following the CommonMark algorithm.
+func processEmphasis(nodes []*node) {
+	for ci := 0; ci < len(nodes); ci++ {
+		closer := nodes[ci]
+		for closer.delim != 0 && closer.active && closer.close && closer.count > 0 {
+			oi := findOpener(nodes, ci)
+			if oi < 0 {
+				if !closer.open {
+					closer.active = false
+				}
+				break
+			}
+			opener := nodes[oi]
+
+			use, tag := 1, "em"
+			switch {
+			case closer.delim == '~':
+				use, tag = 2, "del"
+			case opener.count >= 2 && closer.count >= 2:
+				use, tag = 2, "strong"
+			}
+			opener.count -= use
+			closer.count -= use
+			opener.after = "<" + tag + ">" + opener.after
+			closer.before += "</" + tag + ">"
+
+			// Delimiters between the pair can no longer match
+			for _, n := range nodes[oi+1 : ci] {
+				n.active = false
+			}
+			if opener.count == 0 {
+				opener.active = false
+			}
+		}
+	}
+}
+
+func findOpener(nodes []*node, ci int) int {
+	closer := nodes[ci]
+	for oi := ci - 1; oi >= 0; oi-- {
+		opener := nodes[oi]
+		if opener.delim != closer.delim || !opener.active || !opener.open || opener.count == 0 {
+			continue
+		}
+		if closer.delim == '~' && opener.count < 2 {
+			continue
+		}
+		// The "rule of three" for runs that can both open and close
+		if (opener.close || closer.open) && (opener.count+closer.count)%3 == 0 &&
+			!(opener.count%3 == 0 && closer.count%3 == 0) {
+			continue
+		}
+		return oi
+	}
+	return -1
+}
+
+// parseLink parses a link or image starting at the '[' at s[i], returning
+// its HTML and the index after it.
+func (c *converter) parseLink(s string, i int, image bool) (string, int, bool) {
+	labelEnd := matchingBracket(s, i)
+	if labelEnd < 0 {
+		return "", 0, false
+	}
+	label := s[i+1 : labelEnd]
+	end := labelEnd + 1
+
+	var l link
+	found := false
+	if end < len(s) && s[end] == '(' {
+		if dest, title, next, ok := parseInlineDestination(s, end+1); ok {
+			l, found, end = link{dest: dest, title: title}, true, next
+		}
+	}
+	if !found {
+		ref := label
+		if end < len(s) && s[end] == '[' {
+			if refEnd := matchingBracket(s, end); refEnd >= 0 {
+				if r := s[end+1 : refEnd]; strings.TrimSpace(r) != "" {
+					ref = r
+				}
+				end = refEnd + 1
+			}
+		}
+		l, found = c.refs[normalizeLabel(ref)]
+		if !found {
+			return "", 0, false
+		}
+	}
+
+	title := ""
+	if l.title != "" {
+		title = ` title="` + escapeHTML(l.title) + `"`
+	}
+	if image {
+		alt := plainText(c.renderInline(label))
+		if c.opts.Image != nil {
+			if html := c.opts.Image(l.dest, stdhtml.UnescapeString(alt)); html != "" {
+				return html, end, true
+			}
+		}
+		if !safeURL(l.dest) {
+			return alt, end, true
+		}
+		return `<img src="` + escapeHTML(l.dest) + `" alt="` + alt + `"` + title + `>`, end, true
+	}
+	if !safeURL(l.dest) {
+		return c.renderInline(label), end, true
+	}
+	return `<a href="` + escapeHTML(l.dest) + `"` + title + `>` + c.renderInline(label) + `</a>`, end, true
+}
+
+// matchingBracket returns the index of the ']' closing the '[' at s[i].
+func matchingBracket(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			run := runLength(s, j, '`')
+			if end := findBacktickRun(s, j+run, run); end >= 0 {
+				j = end + run - 1
+			} else {
+				j += run - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// parseInlineDestination parses `dest "title")` starting after the '('.
+func parseInlineDestination(s string, i int) (dest, title string, end int, ok bool) {
+	i = skipSpaces(s, i)
+	if i < len(s) && s[i] == '<' {
+		close := strings.IndexAny(s[i+1:], ">\n")
+		if close < 0 || s[i+1+close] != '>' {
+			return "", "", 0, false
+		}
+		dest = s[i+1 : i+1+close]
+		i += close + 2
+	} else {
+		start, depth := i, 0
+		for ; i < len(s); i++ {
+			ch := s[i]
+			if ch == '\\' && i+1 < len(s) {
+				i++
+				continue
+			}
+			if ch == '(' {
+				depth++
+			} else if ch == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			} else if ch == ' ' || ch == '\n' || ch < 0x20 {
+				break
+			}
+		}
+		dest = s[start:i]
+	}
+
+	j := skipSpaces(s, i)
+	if j < len(s) && j > i && (s[j] == '"' || s[j] == '\'' || s[j] == '(') {
+		closeCh := s[j]
+		if closeCh == '(' {
+			closeCh = ')'
+		}
+		close := strings.IndexByte(s[j+1:], closeCh)
+		if close < 0 {
+			return "", "", 0, false
+		}
+		title = unescape(s[j+1 : j+1+close])
+		j = skipSpaces(s, j+close+2)
+	}
+	if j >= len(s) || s[j] != ')' {
+		return "", "", 0, false
+	}
+	return unescape(dest), title, j + 1, true
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func runLength(s string, i int, ch byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == ch {
+		n++
+	}
+	return n
+}
+
+// findBacktickRun returns the start of the next run of exactly n backticks
+// at or after i.
+func findBacktickRun(s string, i, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		j += i
+		run := runLength(s, j, '`')
+		if run == n {
+			return j
+		}
+		i = j + run
+	}
+	return -1
+}
+
+func isASCIIPunct(ch byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", ch) >= 0
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// unescape removes backslash escapes from link destinations and titles.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// plainText strips tags from rendered inline HTML, for image alt text.
+func plainText(html string) string {
+	return tagRE.ReplaceAllString(html, "")
+}
@@ -0,0 +1,464 @@
+// Package markdown converts Markdown to the HTML subset that Fizzy's rich
+// text fields accept.
+//
+// It implements the commonly used parts of CommonMark: ATX and setext
+// headings, paragraphs, block quotes, bullet and ordered lists, fenced and
+// indented code blocks, thematic breaks, inline code, emphasis, links,
+// images and raw HTML. GitHub-style ~~strikethrough~~ is also supported.
+//
+// Raw HTML is limited to the formatting tags Fizzy uses; other tags are
+// escaped, and links and images keep only http, https, mailto and relative
+// URLs.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Options customises the conversion.
+type Options struct {
+	// Image renders an image reference. When it is nil or returns "", the
+	// image is rendered as an <img> tag.
+	Image func(dest, alt string) string
+}
+
+// ToHTML converts Markdown source to HTML.
+func ToHTML(src string, opts Options) string {
+	lines, refs := splitLines(src)
+	c := &converter{opts: opts, refs: refs}
+	return c.renderBlocks(parseBlocks(lines), false)
+}
+
+// Images returns the destinations of the images in src, in order.
+func Images(src string) []string {
+	var dests []string
+	ToHTML(src, Options{Image: func(dest, alt string) string {
+		dests = append(dests, dest)
+		return ""
+	}})
+	return dests
+}
+
+type link struct {
+	dest  string
+	title string
+}
+
+var (
+	atxHeadingRE  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicRE    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceRE       = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*?)[ \t]*$")
+	setextH1RE    = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	setextH2RE    = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	blockquoteRE  = regexp.MustCompile(`^ {0,3}>`)
+	htmlBlockRE   = regexp.MustCompile(`(?i)^ {0,3}(?:<!--|</?(?:action-text-attachment|address|article|aside|blockquote|details|div|dl|figure|figcaption|footer|h[1-6]|header|hr|li|ol|p|pre|section|summary|table|tbody|td|th|thead|tr|ul)(?:[\s/>]|$))`)
+	definitionRE  = regexp.MustCompile(`^ {0,3}\[((?:[^\]\\]|\\.)+)\]:[ \t]*<?([^\s>]+)>?(?:[ \t]+(?:"([^"]*)"|'([^']*)'|\(([^)]*)\)))?[ \t]*$`)
+	whitespaceRun = regexp.MustCompile(`\s+`)
+)
+
+// splitLines normalises line endings and tabs, and removes link reference
+// definitions outside code blocks, returning them separately.
+func splitLines(src string) ([]string, map[string]link) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	refs := map[string]link{}
+	var lines []string
+	fence := ""
+	for _, line := range strings.Split(strings.TrimRight(src, "\n"), "\n") {
+		line = expandTabs(line)
+		if m := fenceRE.FindStringSubmatch(line); m != nil {
+			if fence == "" {
+				fence = m[2]
+			} else if m[2][0] == fence[0] && len(m[2]) >= len(fence) && m[3] == "" {
+				fence = ""
+			}
+		} else if fence == "" {
+			if m := definitionRE.FindStringSubmatch(line); m != nil {
+				label := normalizeLabel(m[1])
+				if _, exists := refs[label]; !exists {
+					refs[label] = link{dest: unescape(m[2]), title: m[3] + m[4] + m[5]}
+				}
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, refs
+}
+
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col++
+	}
+	return b.String()
+}
+
+func normalizeLabel(label string) string {
+	return strings.ToLower(whitespaceRun.ReplaceAllString(strings.TrimSpace(label), " "))
+}
+
+// Blocks
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	codeBlock
+	quoteBlock
+	listBlock
+	ruleBlock
+	htmlBlock
+)
+
+type block struct {
+	kind     blockKind
+	level    int      // heading level
+	lines    []string // paragraph, heading, code and html content
+	children []block  // block quote content
+	items    [][]block
+	ordered  bool
+	start    int
+	loose    bool
+
+	// blankBefore records a blank line between this block and the one
+	// before it, which makes a list item containing both loose.
+	blankBefore bool
+}
+
+func parseBlocks(lines []string) []block {
+	var blocks []block
+	blank := false
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		n := len(blocks)
+		switch {
+		case isBlank(line):
+			blank = true
+			i++
+			continue
+
+		case fenceRE.MatchString(line):
+			m := fenceRE.FindStringSubmatch(line)
+			indent, fence := len(m[1]), m[2]
+			var code []string
+			i++
+			for ; i < len(lines); i++ {
+				if c := fenceRE.FindStringSubmatch(lines[i]); c != nil && c[2][0] == fence[0] && len(c[2]) >= len(fence) && c[3] == "" {
+					i++
+					break
+				}
+				code = append(code, trimIndent(lines[i], indent))
+			}
+			blocks = append(blocks, block{kind: codeBlock, lines: code})
+
+		case indentOf(line) >= 4:
+			var code []string
+			for ; i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4); i++ {
+				code = append(code, trimIndent(lines[i], 4))
+			}
+			for len(code) > 0 && isBlank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, block{kind: codeBlock, lines: code})
+
+		case atxHeadingRE.MatchString(line):
+			m := atxHeadingRE.FindStringSubmatch(line)
+			blocks = append(blocks, block{kind: headingBlock, level: len(m[1]), lines: []string{m[2]}})
+			i++
+
+		case thematicRE.MatchString(line):
+			blocks = append(blocks, block{kind: ruleBlock})
+			i++
+
+		case blockquoteRE.MatchString(line):
+			var inner []string
+			for ; i < len(lines); i++ {
+				l := lines[i]
+				if blockquoteRE.MatchString(l) {
+					l = strings.TrimLeft(l, " ")[1:]
+					if strings.HasPrefix(l, " ") {
+						l = l[1:]
+					}
+					inner = append(inner, l)
+					continue
+				}
+				// Lazy continuation of a paragraph
+				if !isBlank(l) && len(inner) > 0 && !isBlank(inner[len(inner)-1]) && !startsBlock(l) {
+					inner = append(inner, l)
+					continue
+				}
+				break
+			}
+			blocks = append(blocks, block{kind: quoteBlock, children: parseBlocks(inner)})
+
+		case htmlBlockRE.MatchString(line):
+			var raw []string
+			for ; i < len(lines) && !isBlank(lines[i]); i++ {
+				raw = append(raw, lines[i])
+			}
+			blocks = append(blocks, block{kind: htmlBlock, lines: raw})
+
+		case listMarkerOf(line) != nil:
+			var b block
+			b, i = parseList(lines, i)
+			blocks = append(blocks, b)
+
+		default:
+			para := []string{line}
+			i++
+			level := 0
+			for ; i < len(lines); i++ {
+				l := lines[i]
+				if setextH1RE.MatchString(l) {
+					level = 1
+				} else if setextH2RE.MatchString(l) {
+					level = 2
+				}
+				if level > 0 {
+					i++
+					break
+				}
+				if isBlank(l) || startsBlock(l) {
+					break
+				}
+				para = append(para, l)
+			}
+			if level > 0 {
+				blocks = append(blocks, block{kind: headingBlock, level: level, lines: para})
+			} else {
+				blocks = append(blocks, block{kind: paragraphBlock, lines: para})
+			}
+		}
+		if len(blocks) > n {
+			blocks[n].blankBefore = blank && n > 0
+			blank = false
+		}
+	}
+	return blocks
+}
+
+// startsBlock reports whether line interrupts a paragraph.
+func startsBlock(line string) bool {
+	if atxHeadingRE.MatchString(line) || thematicRE.MatchString(line) || fenceRE.MatchString(line) ||
+		blockquoteRE.MatchString(line) || htmlBlockRE.MatchString(line) {
+		return true
+	}
+	m := listMarkerOf(line)
+	return m != nil && !m.empty && (!m.ordered || m.start == 1)
+}
+
+type listMarker struct {
+	ordered bool
+	char    byte // bullet character, or the delimiter after an ordered number
+	start   int
+	width   int // columns up to the item content
+	empty   bool
+}
+
+func listMarkerOf(line string) *listMarker {
+	indent := indentOf(line)
+	if indent > 3 {
+		return nil
+	}
+	rest := line[indent:]
+	m := &listMarker{}
+	n := 0
+	switch {
+	case rest != "" && strings.ContainsRune("-+*", rune(rest[0])):
+		m.char = rest[0]
+		n = 1
+	default:
+		for n < len(rest) && n < 9 && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 0 || n >= len(rest) || (rest[n] != '.' && rest[n] != ')') {
+			return nil
+		}
+		m.ordered = true
+		m.start, _ = strconv.Atoi(rest[:n])
+		m.char = rest[n]
+		n++
+	}
+	after := rest[n:]
+	if after == "" || isBlank(after) {
+		m.empty = true
+		m.width = indent + n + 1
+		return m
+	}
+	spaces := indentOf(after)
+	if spaces == 0 {
+		return nil
+	}
+	if spaces > 4 {
+		spaces = 1
+	}
+	m.width = indent + n + spaces
+	return m
+}
+
+func parseList(lines []string, i int) (block, int) {
+	first := listMarkerOf(lines[i])
+	list := block{kind: listBlock, ordered: first.ordered, start: first.start}
+	continues := func(i int) bool {
+		m := listMarkerOf(lines[i])
+		return m != nil && m.ordered == first.ordered && m.char == first.char
+	}
+	for i < len(lines) && continues(i) {
+		m := listMarkerOf(lines[i])
+		item := []string{""}
+		if !m.empty {
+			item[0] = lines[i][m.width:]
+		}
+		i++
+		for ; i < len(lines); i++ {
+			l := lines[i]
+			switch {
+			case isBlank(l):
+				item = append(item, "")
+				continue
+			case indentOf(l) >= m.width:
+				item = append(item, trimIndent(l, m.width))
+				continue
+			case !isBlank(item[len(item)-1]) && !startsBlock(l) && listMarkerOf(l) == nil:
+				// Lazy continuation of a paragraph
+				item = append(item, strings.TrimLeft(l, " "))
+				continue
+			}
+			break
+		}
+
+		// Blank lines between items, or between the blocks of an item, make
+		// the list loose. Blank lines inside a nested block, such as a
+		// sublist or code block, don't.
+		trailing := 0
+		for len(item) > 0 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		blocks := parseBlocks(item)
+		for _, b := range blocks {
+			if b.blankBefore {
+				list.loose = true
+			}
+		}
+		list.items = append(list.items, blocks)
+		if trailing > 0 {
+			if i < len(lines) && continues(i) {
+				list.loose = true
+			} else {
+				// Leave the blank lines to separate the list from what
+				// follows it.
+				i -= trailing
+				break
+			}
+		}
+	}
+	return list, i
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent removes up to n leading spaces.
+func trimIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+// Rendering
+
+type converter struct {
+	opts Options
+	refs map[string]link
+}
+
+func (c *converter) renderBlocks(blocks []block, tight bool) string {
+	var out []string
+	for _, b := range blocks {
+		out = append(out, c.renderBlock(b, tight))
+	}
+	return strings.Join(out, "\n")
+}
+
+func (c *converter) renderBlock(b block, tight bool) string {
+	switch b.kind {
+	case headingBlock:
+		tag := "h" + strconv.Itoa(b.level)
+		return "<" + tag + ">" + c.inline(b.lines) + "</" + tag + ">"
+	case codeBlock:
+		code := strings.Join(b.lines, "\n")
+		if code != "" {
+			code += "\n"
+		}
+		return "<pre>" + escapeHTML(code) + "</pre>"
+	case quoteBlock:
+		return "<blockquote>\n" + c.renderBlocks(b.children, false) + "\n</blockquote>"
+	case listBlock:
+		tag := "ul"
+		open := "<ul>"
+		if b.ordered {
+			tag = "ol"
+			open = "<ol>"
+			if b.start != 1 {
+				open = `<ol start="` + strconv.Itoa(b.start) + `">`
+			}
+		}
+		var s strings.Builder
+		s.WriteString(open + "\n")
+		for _, item := range b.items {
+			s.WriteString("<li>" + c.renderItem(item, !b.loose) + "</li>\n")
+		}
+		return s.String() + "</" + tag + ">"
+	case ruleBlock:
+		return "<hr>"
+	case htmlBlock:
+		return sanitizeHTML(strings.Join(b.lines, "\n"))
+	}
+	if tight {
+		return c.inline(b.lines)
+	}
+	return "<p>" + c.inline(b.lines) + "</p>"
+}
+
+func (c *converter) renderItem(blocks []block, tight bool) string {
+	content := c.renderBlocks(blocks, tight)
+	if len(blocks) == 0 || (tight && blocks[0].kind == paragraphBlock && len(blocks) == 1) {
+		return content
+	}
+	if tight && blocks[0].kind == paragraphBlock {
+		return content + "\n"
+	}
+	return "\n" + content + "\n"
+}
+
+// inline renders the inline content of a paragraph or heading.
+func (c *converter) inline(lines []string) string {
+	for i := range lines {
+		lines[i] = strings.TrimLeft(lines[i], " ")
+	}
+	text := strings.TrimRight(strings.Join(lines, "\n"), " ")
+	return c.renderInline(text)
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{"paragraph", "Hello world", "<p>Hello world</p>"},
+		{"paragraphs", "One\ntwo\n\nThree", "<p>One\ntwo</p>\n<p>Three</p>"},
+		{"escapes html", "a < b & c", "<p>a &lt; b &amp; c</p>"},
+		{"keeps entities", "&copy; 2024", "<p>&copy; 2024</p>"},
+		{"hard break", "one  \ntwo", "<p>one<br>\ntwo</p>"},
+		{"backslash break", "one\\\ntwo", "<p>one<br>\ntwo</p>"},
+
+		{"atx headings", "# Title\n### Sub ###", "<h1>Title</h1>\n<h3>Sub</h3>"},
+		{"setext headings", "Title\n=====\n\nSub\n---", "<h1>Title</h1>\n<h2>Sub</h2>"},
+		{"heading needs space", "#hashtag", "<p>#hashtag</p>"},
+
+		{"bold and italic", "**bold** and *italic* and _under_", "<p><strong>bold</strong> and <em>italic</em> and <em>under</em></p>"},
+		{"nested emphasis", "***both*** and *a **b** c*", "<p><em><strong>both</strong></em> and <em>a <strong>b</strong> c</em></p>"},
+		{"intraword underscore", "snake_case_name", "<p>snake_case_name</p>"},
+		{"unmatched", "2 * 3 * 4 and *open", "<p>2 * 3 * 4 and *open</p>"},
+		{"strikethrough", "~~gone~~", "<p><del>gone</del></p>"},
+		{"escaped", `\*not emphasis\*`, "<p>*not emphasis*</p>"},
+
+		{"code span", "Run `fizzy card list`", "<p>Run <code>fizzy card list</code></p>"},
+		{"code span escapes", "`<b>` and `` a`b ``", "<p><code>&lt;b&gt;</code> and <code>a`b</code></p>"},
+		{"fenced code", "```go\nfmt.Println(\"<hi>\")\n\n```", "<pre>fmt.Println(&quot;&lt;hi&gt;&quot;)\n\n</pre>"},
+		{"tilde fence", "~~~\n**raw**\n~~~", "<pre>**raw**\n</pre>"},
+		{"indented code", "    x := 1\n    y := 2", "<pre>x := 1\ny := 2\n</pre>"},
+
+		{"link", "[Fizzy](https://fizzy.do)", `<p><a href="https://fizzy.do">Fizzy</a></p>`},
+		{"link with title", `[a](/b "The B")`, `<p><a href="/b" title="The B">a</a></p>`},
+		{"link with emphasis", "[**bold** link](/x)", `<p><a href="/x"><strong>bold</strong> link</a></p>`},
+		{"reference link", "See [the docs][docs].\n\n[docs]: https://example.com/docs", `<p>See <a href="https://example.com/docs">the docs</a>.</p>`},
+		{"shortcut reference", "[Docs]\n\n[docs]: /docs", `<p><a href="/docs">Docs</a></p>`},
+		{"autolink", "<https://fizzy.do>", `<p><a href="https://fizzy.do">https://fizzy.do</a></p>`},
+		{"not a link", "[just brackets]", "<p>[just brackets]</p>"},
+		{"image", "![A cat](https://example.com/cat.png)", `<p><img src="https://example.com/cat.png" alt="A cat"></p>`},
+
+		{"blockquote", "> Quoted\n> text\n>\n> More", "<blockquote>\n<p>Quoted\ntext</p>\n<p>More</p>\n</blockquote>"},
+		{"lazy blockquote", "> Quoted\ncontinued", "<blockquote>\n<p>Quoted\ncontinued</p>\n</blockquote>"},
+
+		{"bullet list", "- one\n- two\n- three", "<ul>\n<li>one</li>\n<li>two</li>\n<li>three</li>\n</ul>"},
+		{"ordered list", "1. one\n2. two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>"},
+		{"ordered start", "3) three\n4) four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>"},
+		{"loose list", "- one\n\n- two", "<ul>\n<li>\n<p>one</p>\n</li>\n<li>\n<p>two</p>\n</li>\n</ul>"},
+		{"nested list", "- one\n  - a\n  - b\n- two", "<ul>\n<li>one\n<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n</li>\n<li>two</li>\n</ul>"},
+		{"loose sublist in tight list", "- one\n  - a\n\n  - b\n- two", "<ul>\n<li>one\n<ul>\n<li>\n<p>a</p>\n</li>\n<li>\n<p>b</p>\n</li>\n</ul>\n</li>\n<li>two</li>\n</ul>"},
+		{"code in tight list", "- one\n  ```\n  x\n\n  y\n  ```\n- two", "<ul>\n<li>one\n<pre>x\n\ny\n</pre>\n</li>\n<li>two</li>\n</ul>"},
+		{"blank before sublist", "- one\n\n  - a", "<ul>\n<li>\n<p>one</p>\n<ul>\n<li>a</li>\n</ul>\n</li>\n</ul>"},
+		{"list then paragraph", "- one\n- two\n\nAfter", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<p>After</p>"},
+		{"list after paragraph", "Steps:\n- one\n- two", "<p>Steps:</p>\n<ul>\n<li>one</li>\n<li>two</li>\n</ul>"},
+		{"list changes type", "- a\n* b", "<ul>\n<li>a</li>\n</ul>\n<ul>\n<li>b</li>\n</ul>"},
+		{"number in paragraph", "The year\n2024. was good", "<p>The year\n2024. was good</p>"},
+		{"list item code", "1. Run:\n\n   ```\n   make\n   ```", "<ol>\n<li>\n<p>Run:</p>\n<pre>make\n</pre>\n</li>\n</ol>"},
+
+		{"thematic break", "above\n\n***\n\nbelow", "<p>above</p>\n<hr>\n<p>below</p>"},
+		{"html block", "<div>\n*raw*\n</div>", "<div>\n*raw*\n</div>"},
+		{"inline html", "a <strong>b</strong>", "<p>a <strong>b</strong></p>"},
+		{"attachment tag", `<action-text-attachment sgid="abc"></action-text-attachment>`, `<action-text-attachment sgid="abc"></action-text-attachment>`},
+		{"escapes unknown inline html", `a <script>alert(1)</script>`, "<p>a &lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"drops unknown attributes", `<strong onclick="x()">b</strong>`, "<p><strong>b</strong></p>"},
+		{"escapes unknown block html", "<div>\n<script>alert(1)</script>\n<img src=x onerror=alert(1)\n</div>", "<div>\n&lt;script>alert(1)&lt;/script>\n&lt;img src=x onerror=alert(1)\n</div>"},
+		{"drops unsafe raw href", `<a href="javascript:alert(1)">x</a>`, "<p><a>x</a></p>"},
+		{"drops unsafe link", "[x](javascript:alert(1)) and [y](data:text/html,hi)", "<p>x and y</p>"},
+		{"keeps mailto link", "[mail](mailto:a@example.com)", `<p><a href="mailto:a@example.com">mail</a></p>`},
+		{"drops unsafe image", "![pic](javascript:alert(1))", "<p>pic</p>"},
+		{"drops unsafe autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>"},
+		{"crlf and tabs", "-\tone\r\n-\ttwo", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToHTML(tt.markdown, Options{})
+			if result != tt.expected {
+				t.Errorf("ToHTML(%q)\n got: %q\nwant: %q", tt.markdown, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImageOption(t *testing.T) {
+	src := "Before\n\n![Screenshot](./shot.png) and ![remote](https://example.com/a.png)"
+	result := ToHTML(src, Options{Image: func(dest, alt string) string {
+		if dest == "./shot.png" {
+			return `<action-text-attachment sgid="sgid-` + alt + `"></action-text-attachment>`
+		}
+		return ""
+	}})
+
+	expected := "<p>Before</p>\n<p><action-text-attachment sgid=\"sgid-Screenshot\"></action-text-attachment> and <img src=\"https://example.com/a.png\" alt=\"remote\"></p>"
+	if result != expected {
+		t.Errorf("got: %q\nwant: %q", result, expected)
+	}
+}
+
+func TestImages(t *testing.T) {
+	src := "![a](one.png)\n\n- ![b](<two words.png>)\n\n```\n![not](code.png)\n```\n\n![c][ref]\n\n[ref]: three.png"
+	expected := []string{"one.png", "two words.png", "three.png"}
+	if result := Images(src); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
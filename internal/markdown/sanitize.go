@@ -0,0 +1,137 @@
+package markdown
+
+import (
+	stdhtml "html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// allowedTags are the raw HTML tags kept in the output, with the attributes
+// each may have. Other tags are escaped so they show as text, and other
+// attributes are dropped.
+var allowedTags = map[string][]string{
+	"a":                      {"href", "title"},
+	"b":                      nil,
+	"blockquote":             nil,
+	"br":                     nil,
+	"code":                   nil,
+	"del":                    nil,
+	"div":                    nil,
+	"em":                     nil,
+	"figcaption":             nil,
+	"figure":                 nil,
+	"h1":                     nil,
+	"h2":                     nil,
+	"h3":                     nil,
+	"h4":                     nil,
+	"h5":                     nil,
+	"h6":                     nil,
+	"hr":                     nil,
+	"i":                      nil,
+	"img":                    {"src", "alt", "title", "width", "height"},
+	"li":                     nil,
+	"ol":                     {"start"},
+	"p":                      nil,
+	"pre":                    nil,
+	"s":                      nil,
+	"strike":                 nil,
+	"strong":                 nil,
+	"sub":                    nil,
+	"sup":                    nil,
+	"u":                      nil,
+	"ul":                     nil,
+	"action-text-attachment": {"sgid", "content-type", "url", "caption", "filename", "filesize", "width", "height", "presentation"},
+}
+
+var (
+	htmlTagRE  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*)\s*(/?)>`)
+	htmlAttrRE = regexp.MustCompile(`([a-zA-Z_:][a-zA-Z0-9_.:-]*)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?`)
+)
+
+// sanitizeHTML keeps the allowed tags in a run of raw HTML and escapes
+// everything else that could start markup.
+func sanitizeHTML(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		rest := s[i:]
+		if strings.HasPrefix(rest, "<!--") {
+			if end := strings.Index(rest[4:], "-->"); end >= 0 {
+				n := 4 + end + 3
+				b.WriteString(rest[:n])
+				i += n
+				continue
+			}
+		}
+		if m := htmlTagRE.FindString(rest); m != "" {
+			if tag, ok := sanitizeTag(m); ok {
+				b.WriteString(tag)
+				i += len(m)
+				continue
+			}
+		}
+		b.WriteString("&lt;")
+		i++
+	}
+	return b.String()
+}
+
+// sanitizeTag rebuilds an allowed tag with only its allowed attributes.
+func sanitizeTag(tag string) (string, bool) {
+	m := htmlTagRE.FindStringSubmatch(tag)
+	if m == nil {
+		return "", false
+	}
+	name := strings.ToLower(m[2])
+	attrs, ok := allowedTags[name]
+	if !ok {
+		return "", false
+	}
+	if m[1] == "/" {
+		return "</" + name + ">", true
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + name)
+	for _, a := range htmlAttrRE.FindAllStringSubmatch(m[3], -1) {
+		key := strings.ToLower(a[1])
+		if !slices.Contains(attrs, key) {
+			continue
+		}
+		value := a[2]
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		value = stdhtml.UnescapeString(value)
+		if (key == "href" || key == "src" || key == "url") && !safeURL(value) {
+			continue
+		}
+		b.WriteString(" " + key + `="` + escapeHTML(value) + `"`)
+	}
+	if m[4] == "/" {
+		b.WriteString(" /")
+	}
+	b.WriteString(">")
+	return b.String(), true
+}
+
+// safeURL reports whether a link or image destination may be kept: an
+// http, https or mailto URL, or a relative one. Schemes such as javascript:
+// and data: are dropped.
+func safeURL(dest string) bool {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,653 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/robzolkos/fizzy-cli/internal/richtext"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format for command results.
+type Format string
+
+// Supported output formats.
+const (
+	FormatJSON   Format = "json"
+	FormatTable  Format = "table"
+	FormatYAML   Format = "yaml"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// Formats lists the supported output formats in help order.
+var Formats = []Format{FormatJSON, FormatTable, FormatYAML, FormatNDJSON, FormatCSV}
+
+// outputFormat is the format used by Print.
+var outputFormat = FormatJSON
+
+// ParseFormat validates an output format name.
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(name)))
+	if f == "" {
+		return FormatJSON, nil
+	}
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, known := range Formats {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown output format %q (expected %s)", name, strings.Join(names, ", "))
+}
+
+// SetFormat sets the output format used by Print.
+func SetFormat(f Format) {
+	if f == "" {
+		f = FormatJSON
+	}
+	outputFormat = f
+}
+
+// CurrentFormat returns the output format used by Print.
+func CurrentFormat() Format {
+	return outputFormat
+}
+
+// RichText selects how rich text (HTML) fields are shown.
+type RichText string
+
+// Supported rich text modes.
+const (
+	// RichTextStyled renders HTML as styled terminal text in table output.
+	RichTextStyled RichText = "styled"
+	// RichTextPlain renders HTML as unstyled text in table output.
+	RichTextPlain RichText = "plain"
+	// RichTextMarkdown converts HTML to Markdown in table output, and adds
+	// the Markdown next to the HTML in the other formats.
+	RichTextMarkdown RichText = "markdown"
+)
+
+// richTextMode is the rich text mode used by Print.
+var richTextMode = RichTextStyled
+
+// SetRichText sets how rich text fields are shown by Print.
+func SetRichText(m RichText) {
+	if m == "" {
+		m = RichTextStyled
+	}
+	richTextMode = m
+}
+
+// Column describes one column in table and CSV output.
+type Column struct {
+	// Header is the column title.
+	Header string
+	// Path is a dotted path into the item, e.g. "column.name". A "[]" suffix
+	// on a segment maps over an array, e.g. "assignees[].name".
+	Path string
+}
+
+// defaultColumns are the table/CSV columns for each resource type.
+var defaultColumns = map[string][]Column{
+	"board": {
+		{Header: "ID", Path: "id"},
+		{Header: "NAME", Path: "name"},
+	},
+	"card": {
+		{Header: "NUMBER", Path: "number"},
+		{Header: "TITLE", Path: "title"},
+		{Header: "COLUMN", Path: "column.name"},
+		{Header: "ASSIGNEES", Path: "assignees[].name"},
+		{Header: "TAGS", Path: "tags"},
+	},
+	"card_bulk": {
+		{Header: "NUMBER", Path: "number"},
+		{Header: "OK", Path: "success"},
+		{Header: "ERROR", Path: "error.message"},
+	},
+	"card_timeline": {
+		{Header: "AT", Path: "at"},
+		{Header: "TYPE", Path: "type"},
+		{Header: "ACTOR", Path: "actor"},
+		{Header: "SUMMARY", Path: "summary"},
+	},
+	"card_search": {
+		{Header: "NUMBER", Path: "number"},
+		{Header: "TITLE", Path: "title"},
+		{Header: "COLUMN", Path: "column.name"},
+		{Header: "IN", Path: "search.field"},
+		{Header: "MATCH", Path: "search.snippet"},
+	},
+	"column": {
+		{Header: "ID", Path: "id"},
+		{Header: "NAME", Path: "name"},
+		{Header: "COLOR", Path: "color"},
+	},
+	"comment": {
+		{Header: "ID", Path: "id"},
+		{Header: "AUTHOR", Path: "creator.name"},
+		{Header: "CREATED", Path: "created_at"},
+		{Header: "BODY", Path: "body.plain_text"},
+	},
+	"step": {
+		{Header: "ID", Path: "id"},
+		{Header: "DONE", Path: "completed"},
+		{Header: "CONTENT", Path: "content"},
+	},
+	"reaction": {
+		{Header: "ID", Path: "id"},
+		{Header: "CONTENT", Path: "content"},
+		{Header: "BY", Path: "reacter.name"},
+	},
+	"user": {
+		{Header: "ID", Path: "id"},
+		{Header: "NAME", Path: "name"},
+		{Header: "EMAIL", Path: "email_address"},
+		{Header: "ROLE", Path: "role"},
+	},
+	"tag": {
+		{Header: "ID", Path: "id"},
+		{Header: "TITLE", Path: "title"},
+	},
+	"notification": {
+		{Header: "ID", Path: "id"},
+		{Header: "READ", Path: "read"},
+		{Header: "CARD", Path: "card.title"},
+		{Header: "TITLE", Path: "title"},
+		{Header: "CREATED", Path: "created_at"},
+	},
+}
+
+// richTextField describes the rich text field of a resource.
+type richTextField struct {
+	// Path is the HTML field (see Column.Path).
+	Path string
+	// Markdown is where the Markdown version is added (see Column.Path).
+	Markdown string
+	// Heading lists the columns shown above the text of each item. Without
+	// a heading, single items are shown as a table with the text below and
+	// lists as a plain table.
+	Heading []Column
+}
+
+// richTextFields are the resources whose rich text is rendered in table
+// output.
+var richTextFields = map[string]richTextField{
+	"card": {Path: "description_html", Markdown: "description_markdown"},
+	"card_timeline": {
+		Path:     "body.html",
+		Markdown: "body.markdown",
+		Heading: []Column{
+			{Header: "AT", Path: "at"},
+			{Header: "ACTOR", Path: "actor"},
+			{Header: "SUMMARY", Path: "summary"},
+		},
+	},
+	"comment": {
+		Path:     "body.html",
+		Markdown: "body.markdown",
+		Heading: []Column{
+			{Header: "AUTHOR", Path: "creator.name"},
+			{Header: "CREATED", Path: "created_at"},
+			{Header: "ID", Path: "id"},
+		},
+	},
+}
+
+// ColumnsFor returns the default columns for a resource type.
+func ColumnsFor(resource string) []Column {
+	return defaultColumns[resource]
+}
+
+// Write renders the response to w in the given format.
+func (r *Response) Write(w io.Writer, format Format) error {
+	if richTextMode == RichTextMarkdown {
+		r = r.withMarkdown()
+	}
+	switch format {
+	case FormatTable:
+		return r.writeTable(w)
+	case FormatCSV:
+		return r.writeCSV(w)
+	case FormatYAML:
+		return r.writeYAML(w)
+	case FormatNDJSON:
+		return r.writeNDJSON(w)
+	default:
+		output, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(output))
+		return err
+	}
+}
+
+func (r *Response) writeYAML(w io.Writer) error {
+	// Round-trip through JSON so the YAML keys match the JSON envelope.
+	var generic interface{}
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func (r *Response) writeNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	if !r.Success {
+		return enc.Encode(r)
+	}
+	if items, ok := normalize(r.Data).([]interface{}); ok {
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if r.Data == nil {
+		return nil
+	}
+	return enc.Encode(r.Data)
+}
+
+func (r *Response) writeTable(w io.Writer) error {
+	if !r.Success {
+		return writeErrorText(w, r.Error)
+	}
+
+	field, hasText := richTextFields[r.Resource]
+	data := normalize(r.Data)
+	_, isItem := data.(map[string]interface{})
+	_, isList := data.([]interface{})
+	switch {
+	case hasText && field.Heading != nil && (isItem || isList):
+		return r.writeEntries(w, field)
+	case hasText && isItem:
+		if err := r.writeRows(w); err != nil {
+			return err
+		}
+		if text := renderRichText(data, field); text != "" {
+			_, err := fmt.Fprintf(w, "\n%s\n", text)
+			return err
+		}
+		return nil
+	}
+	return r.writeRows(w)
+}
+
+func (r *Response) writeRows(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers, rows := r.tabular()
+	if headers == nil {
+		// Single object without known columns: print field/value pairs.
+		for _, kv := range fieldPairs(normalize(r.Data)) {
+			fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(kv[0]), sanitizeCell(kv[1]))
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		for i := range row {
+			row[i] = sanitizeCell(row[i])
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	r.writeMoreNote(w)
+	return nil
+}
+
+// writeEntries prints each item as a heading line followed by its rich
+// text, indented.
+func (r *Response) writeEntries(w io.Writer, field richTextField) error {
+	data := normalize(r.Data)
+	items, isList := data.([]interface{})
+	if !isList {
+		items = []interface{}{data}
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		var heading []string
+		for _, c := range field.Heading {
+			if v := sanitizeCell(formatValue(lookupPath(item, c.Path))); v != "" {
+				heading = append(heading, v)
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(heading, "  ")); err != nil {
+			return err
+		}
+		if text := renderRichText(item, field); text != "" {
+			fmt.Fprintln(w, indent(text, "  "))
+		}
+	}
+	if r.Pagination != nil && r.Pagination.HasNext {
+		fmt.Fprintln(w)
+		r.writeMoreNote(w)
+	}
+	return nil
+}
+
+func (r *Response) writeMoreNote(w io.Writer) {
+	if r.Pagination != nil && r.Pagination.HasNext {
+		hint := r.Pagination.Hint
+		if hint == "" {
+			hint = "use --all or --page"
+		}
+		fmt.Fprintf(w, "(more results available, %s)\n", hint)
+	}
+}
+
+func (r *Response) writeCSV(w io.Writer) error {
+	if !r.Success {
+		return writeErrorText(w, r.Error)
+	}
+
+	cw := csv.NewWriter(w)
+	headers, rows := r.tabular()
+	if headers == nil {
+		cw.Write([]string{"field", "value"})
+		for _, kv := range fieldPairs(normalize(r.Data)) {
+			cw.Write(kv[:])
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	lower := make([]string, len(headers))
+	for i, h := range headers {
+		lower[i] = strings.ToLower(h)
+	}
+	cw.Write(lower)
+	for _, row := range rows {
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tabular converts the response data into headers and rows. It returns nil
+// headers when the data is a single object with no known columns.
+func (r *Response) tabular() ([]string, [][]string) {
+	data := normalize(r.Data)
+	columns := ColumnsFor(r.Resource)
+
+	items, isList := data.([]interface{})
+	if !isList {
+		if data == nil || columns == nil {
+			return nil, nil
+		}
+		items = []interface{}{data}
+	}
+
+	if columns == nil {
+		columns = inferColumns(items)
+	}
+
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = formatValue(lookupPath(item, c.Path))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// inferColumns picks the scalar fields of the first item, with id first.
+func inferColumns(items []interface{}) []Column {
+	if len(items) == 0 {
+		return []Column{{Header: "ID", Path: "id"}}
+	}
+	first, ok := items[0].(map[string]interface{})
+	if !ok {
+		return []Column{{Header: "VALUE", Path: ""}}
+	}
+
+	var keys []string
+	for k, v := range first {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		if k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := first["id"]; ok {
+		keys = append([]string{"id"}, keys...)
+	}
+
+	columns := make([]Column, len(keys))
+	for i, k := range keys {
+		columns[i] = Column{Header: strings.ToUpper(k), Path: k}
+	}
+	return columns
+}
+
+// lookupPath resolves a dotted path (see Column.Path) against a value.
+func lookupPath(v interface{}, path string) interface{} {
+	if path == "" {
+		return v
+	}
+	segment, rest, _ := strings.Cut(path, ".")
+	if name, ok := strings.CutSuffix(segment, "[]"); ok {
+		m, _ := v.(map[string]interface{})
+		arr, _ := m[name].([]interface{})
+		out := make([]interface{}, 0, len(arr))
+		for _, el := range arr {
+			if val := lookupPath(el, rest); val != nil {
+				out = append(out, val)
+			}
+		}
+		return out
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return lookupPath(m[segment], rest)
+}
+
+// formatValue converts a JSON value into a single table cell.
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		if val {
+			return "yes"
+		}
+		return "no"
+	case float64:
+		if val == float64(int64(val)) {
+			return fmt.Sprintf("%d", int64(val))
+		}
+		return fmt.Sprintf("%g", val)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, el := range val {
+			if m, ok := el.(map[string]interface{}); ok {
+				parts = append(parts, formatValue(firstOf(m, "name", "title", "id")))
+				continue
+			}
+			parts = append(parts, formatValue(el))
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		return formatValue(firstOf(val, "name", "title", "id"))
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func firstOf(m map[string]interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// fieldPairs flattens an object into sorted field/value pairs.
+func fieldPairs(data interface{}) [][2]string {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		if data == nil {
+			return nil
+		}
+		return [][2]string{{"value", formatValue(data)}}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([][2]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, [2]string{k, formatValue(m[k])})
+	}
+	return pairs
+}
+
+// normalize converts typed data into generic JSON values so the renderers
+// only need to handle maps, slices and scalars.
+func normalize(data interface{}) interface{} {
+	switch data.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return data
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return data
+	}
+	return generic
+}
+
+// renderRichText renders the rich text field of an item in the current
+// rich text mode.
+func renderRichText(item interface{}, field richTextField) string {
+	src, _ := lookupPath(item, field.Path).(string)
+	switch richTextMode {
+	case RichTextPlain:
+		return richtext.Render(src, richtext.Options{Plain: true})
+	case RichTextMarkdown:
+		return richtext.ToMarkdown(src)
+	default:
+		return richtext.Render(src, richtext.Options{})
+	}
+}
+
+// withMarkdown returns a copy of the response with the Markdown version of
+// each rich text field added to the data.
+func (r *Response) withMarkdown() *Response {
+	field, ok := richTextFields[r.Resource]
+	if !ok || !r.Success {
+		return r
+	}
+
+	addMarkdown := func(item interface{}) interface{} {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return item
+		}
+		src, ok := lookupPath(m, field.Path).(string)
+		if !ok {
+			return item
+		}
+		return setPath(m, field.Markdown, richtext.ToMarkdown(src))
+	}
+
+	copied := *r
+	switch data := normalize(r.Data).(type) {
+	case []interface{}:
+		items := make([]interface{}, len(data))
+		for i, item := range data {
+			items[i] = addMarkdown(item)
+		}
+		copied.Data = items
+	default:
+		copied.Data = addMarkdown(data)
+	}
+	return &copied
+}
+
+// setPath returns a copy of m with value set at a dotted path, copying the
+// nested objects along the way.
+func setPath(m map[string]interface{}, path string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		out[key] = value
+		return out
+	}
+	child, _ := m[key].(map[string]interface{})
+	out[key] = setPath(child, rest, value)
+	return out
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sanitizeCell(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "\t", " ")
+}
+
+func writeErrorText(w io.Writer, e *ErrorDetail) error {
+	if e == nil {
+		_, err := fmt.Fprintln(w, "Error")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Error (%s): %s\n", e.Code, e.Message)
+	return err
+}
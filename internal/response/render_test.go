@@ -0,0 +1,318 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/errors"
+)
+
+func sampleCards() []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"id":     "c1",
+			"number": float64(42),
+			"title":  "Fix login",
+			"column": map[string]interface{}{"id": "col-1", "name": "Doing"},
+			"assignees": []interface{}{
+				map[string]interface{}{"id": "u1", "name": "Ada"},
+				map[string]interface{}{"id": "u2", "name": "Grace"},
+			},
+			"tags": []interface{}{"bug", "auth"},
+		},
+		map[string]interface{}{
+			"id":     "c2",
+			"number": float64(43),
+			"title":  "Write docs",
+			"column": nil,
+			"tags":   []interface{}{},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		wantErr  bool
+	}{
+		{"", FormatJSON, false},
+		{"json", FormatJSON, false},
+		{"TABLE", FormatTable, false},
+		{"yaml", FormatYAML, false},
+		{"ndjson", FormatNDJSON, false},
+		{"csv", FormatCSV, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f, err := ParseFormat(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, f)
+			}
+		})
+	}
+}
+
+func TestWriteTable_Cards(t *testing.T) {
+	resp := Success(sampleCards())
+	resp.Resource = "card"
+
+	var buf bytes.Buffer
+	if err := resp.Write(&buf, FormatTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	for _, h := range []string{"NUMBER", "TITLE", "COLUMN", "ASSIGNEES", "TAGS"} {
+		if !strings.Contains(lines[0], h) {
+			t.Errorf("expected header to contain %s, got %q", h, lines[0])
+		}
+	}
+	for _, want := range []string{"42", "Fix login", "Doing", "Ada, Grace", "bug, auth"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("expected first row to contain %q, got %q", want, lines[1])
+		}
+	}
+}
+
+func TestWriteTable_Boards(t *testing.T) {
+	resp := Success([]interface{}{
+		map[string]interface{}{"id": "b1", "name": "Engineering", "all_access": true},
+	})
+	resp.Resource = "board"
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatTable)
+
+	out := buf.String()
+	if !strings.Contains(out, "ID") || !strings.Contains(out, "NAME") {
+		t.Errorf("expected ID and NAME headers, got:\n%s", out)
+	}
+	if strings.Contains(out, "ALL_ACCESS") {
+		t.Errorf("expected only default board columns, got:\n%s", out)
+	}
+}
+
+func TestWriteTable_SingleObjectWithoutColumns(t *testing.T) {
+	resp := Success(map[string]interface{}{"version": "1.2.3", "deleted": true})
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatTable)
+
+	out := buf.String()
+	if !strings.Contains(out, "VERSION") || !strings.Contains(out, "1.2.3") {
+		t.Errorf("expected field/value output, got:\n%s", out)
+	}
+	if !strings.Contains(out, "yes") {
+		t.Errorf("expected boolean rendered as yes, got:\n%s", out)
+	}
+}
+
+func TestWriteTable_Error(t *testing.T) {
+	resp := Error(errors.NewNotFoundError("Card not found"))
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatTable)
+
+	if got := strings.TrimSpace(buf.String()); got != "Error (NOT_FOUND): Card not found" {
+		t.Errorf("unexpected error output: %q", got)
+	}
+}
+
+func TestWriteTable_CardDescription(t *testing.T) {
+	resp := Success(map[string]interface{}{
+		"number":           float64(42),
+		"title":            "Fix login",
+		"description_html": "<div>Steps:</div><ol><li>Open <strong>login</strong></li></ol>",
+	})
+	resp.Resource = "card"
+
+	tests := []struct {
+		mode     RichText
+		expected string
+	}{
+		{RichTextPlain, "Steps:\n\n1. Open login\n"},
+		{RichTextMarkdown, "Steps:\n\n1. Open **login**\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			SetRichText(tt.mode)
+			defer SetRichText(RichTextStyled)
+
+			var buf bytes.Buffer
+			if err := resp.Write(&buf, FormatTable); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			table, text, found := strings.Cut(buf.String(), "\n\n")
+			if !found || !strings.Contains(table, "Fix login") {
+				t.Fatalf("expected the card row, then the description, got:\n%s", buf.String())
+			}
+			if text != tt.expected {
+				t.Errorf("expected description %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestWriteTable_Comments(t *testing.T) {
+	SetRichText(RichTextPlain)
+	defer SetRichText(RichTextStyled)
+
+	resp := SuccessWithPagination([]interface{}{
+		map[string]interface{}{
+			"id":         "cm1",
+			"created_at": "2025-01-01T10:00:00Z",
+			"creator":    map[string]interface{}{"name": "Ada"},
+			"body":       map[string]interface{}{"html": "<div>Looks good<br>Ship it</div>"},
+		},
+		map[string]interface{}{
+			"id":         "cm2",
+			"created_at": "2025-01-02T10:00:00Z",
+			"creator":    map[string]interface{}{"name": "Grace"},
+			"body":       map[string]interface{}{"html": `<action-text-attachment filename="shot.png"></action-text-attachment>`},
+		},
+	}, true, "")
+	resp.Resource = "comment"
+
+	var buf bytes.Buffer
+	if err := resp.Write(&buf, FormatTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Ada  2025-01-01T10:00:00Z  cm1\n" +
+		"  Looks good\n" +
+		"  Ship it\n" +
+		"\n" +
+		"Grace  2025-01-02T10:00:00Z  cm2\n" +
+		"  [attachment: shot.png]\n" +
+		"\n" +
+		"(more results available, use --all or --page)\n"
+	if buf.String() != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteJSON_Markdown(t *testing.T) {
+	SetRichText(RichTextMarkdown)
+	defer SetRichText(RichTextStyled)
+
+	body := map[string]interface{}{"html": "<div><em>Hi</em></div>"}
+	resp := Success([]interface{}{map[string]interface{}{"id": "cm1", "body": body}})
+	resp.Resource = "comment"
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatJSON)
+
+	var parsed struct {
+		Data []struct {
+			Body map[string]string `json:"body"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("expected JSON envelope: %v", err)
+	}
+	if got := parsed.Data[0].Body["markdown"]; got != "*Hi*" {
+		t.Errorf("expected body.markdown '*Hi*', got %q", got)
+	}
+	if _, ok := body["markdown"]; ok {
+		t.Error("expected the original data to be left unchanged")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	resp := Success(sampleCards())
+	resp.Resource = "card"
+
+	var buf bytes.Buffer
+	if err := resp.Write(&buf, FormatCSV); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "number,title,column,assignees,tags" {
+		t.Errorf("unexpected CSV header: %q", lines[0])
+	}
+	if lines[1] != `42,Fix login,Doing,"Ada, Grace","bug, auth"` {
+		t.Errorf("unexpected CSV row: %q", lines[1])
+	}
+	if lines[2] != "43,Write docs,,," {
+		t.Errorf("unexpected CSV row: %q", lines[2])
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	resp := Success(sampleCards())
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatNDJSON)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var item map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &item); err != nil {
+		t.Fatalf("expected valid JSON line: %v", err)
+	}
+	if item["id"] != "c1" {
+		t.Errorf("expected first item id c1, got %v", item["id"])
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	resp := Success(map[string]interface{}{"id": "b1"})
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatYAML)
+
+	out := buf.String()
+	if !strings.Contains(out, "success: true") || !strings.Contains(out, "id: b1") {
+		t.Errorf("unexpected YAML output:\n%s", out)
+	}
+}
+
+func TestWriteJSON_IsDefaultEnvelope(t *testing.T) {
+	resp := Success(map[string]interface{}{"id": "b1"})
+	resp.Resource = "board"
+
+	var buf bytes.Buffer
+	resp.Write(&buf, FormatJSON)
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("expected JSON envelope: %v", err)
+	}
+	if _, ok := parsed["Resource"]; ok {
+		t.Error("expected Resource to be omitted from JSON envelope")
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	item := sampleCards()[0]
+
+	if got := formatValue(lookupPath(item, "column.name")); got != "Doing" {
+		t.Errorf("expected 'Doing', got %q", got)
+	}
+	if got := formatValue(lookupPath(item, "assignees[].name")); got != "Ada, Grace" {
+		t.Errorf("expected 'Ada, Grace', got %q", got)
+	}
+	if got := lookupPath(item, "missing.field"); got != nil {
+		t.Errorf("expected nil for missing path, got %v", got)
+	}
+}
@@ -2,7 +2,6 @@
 package response
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -18,6 +17,10 @@ type Response struct {
 	Pagination *Pagination            `json:"pagination,omitempty"`
 	Location   string                 `json:"location,omitempty"`
 	Meta       map[string]interface{} `json:"meta,omitempty"`
+
+	// Resource is the resource type (e.g. "card") used to pick default
+	// columns for table and CSV output. It is not part of the envelope.
+	Resource string `json:"-"`
 }
 
 // ErrorDetail represents an error in the response.
@@ -32,6 +35,8 @@ type ErrorDetail struct {
 type Pagination struct {
 	HasNext bool   `json:"has_next"`
 	NextURL string `json:"next_url,omitempty"`
+	// Hint tells table readers how to see the rest, e.g. "use --all or --page".
+	Hint string `json:"-"`
 }
 
 // Success creates a successful response with data.
@@ -76,12 +81,16 @@ func Error(err *errors.CLIError) *Response {
 		Error: &ErrorDetail{
 			Code:    err.Code,
 			Message: err.Message,
+			Details: err.Details,
 		},
 		Meta: createMeta(),
 	}
 	if err.Status != 0 {
 		resp.Error.Status = err.Status
 	}
+	if err.Partial != nil {
+		resp.Data = err.Partial
+	}
 	return resp
 }
 
@@ -99,14 +108,16 @@ func createMeta() map[string]interface{} {
 	}
 }
 
-// Print outputs the response as JSON to stdout.
+// Print outputs the response to stdout in the current output format.
+// Errors in the human-oriented formats (table, csv) go to stderr instead.
 func (r *Response) Print() {
-	output, err := json.MarshalIndent(r, "", "  ")
-	if err != nil {
+	w := os.Stdout
+	if !r.Success && (outputFormat == FormatTable || outputFormat == FormatCSV) {
+		w = os.Stderr
+	}
+	if err := r.Write(w, outputFormat); err != nil {
 		fmt.Fprintf(os.Stderr, "Error marshaling response: %v\n", err)
-		return
 	}
-	fmt.Println(string(output))
 }
 
 // PrintAndExit prints the response and exits with appropriate code.
@@ -130,6 +141,10 @@ func (r *Response) PrintAndExit() {
 			os.Exit(errors.ExitNetwork)
 		case "INVALID_ARGS":
 			os.Exit(errors.ExitInvalidArgs)
+		case "RATE_LIMITED":
+			os.Exit(errors.ExitRateLimited)
+		case "CANCELLED":
+			os.Exit(errors.ExitCancelled)
 		default:
 			os.Exit(errors.ExitError)
 		}
@@ -156,6 +171,10 @@ func (r *Response) ExitCode() int {
 			return errors.ExitNetwork
 		case "INVALID_ARGS":
 			return errors.ExitInvalidArgs
+		case "RATE_LIMITED":
+			return errors.ExitRateLimited
+		case "CANCELLED":
+			return errors.ExitCancelled
 		}
 	}
 	return errors.ExitError
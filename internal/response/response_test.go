@@ -110,6 +110,17 @@ func TestErrorWithoutStatus(t *testing.T) {
 	}
 }
 
+func TestErrorWithPartialData(t *testing.T) {
+	cliErr := errors.NewCancelledError("cancelled")
+	cliErr.Partial = []interface{}{"first page"}
+	resp := Error(cliErr)
+
+	data, ok := resp.Data.([]interface{})
+	if !ok || len(data) != 1 {
+		t.Errorf("expected partial data in response, got %v", resp.Data)
+	}
+}
+
 func TestErrorFromError(t *testing.T) {
 	t.Run("with CLIError", func(t *testing.T) {
 		cliErr := errors.NewNotFoundError("not found")
@@ -224,6 +235,16 @@ func TestExitCode(t *testing.T) {
 			resp:     Error(errors.NewInvalidArgsError("missing flag")),
 			expected: errors.ExitInvalidArgs,
 		},
+		{
+			name:     "rate limited",
+			resp:     Error(errors.NewRateLimitError("slow down")),
+			expected: errors.ExitRateLimited,
+		},
+		{
+			name:     "cancelled",
+			resp:     Error(errors.NewCancelledError("cancelled")),
+			expected: errors.ExitCancelled,
+		},
 		{
 			name:     "generic error",
 			resp:     Error(errors.NewError("something went wrong")),
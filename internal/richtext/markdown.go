@@ -0,0 +1,265 @@
+package richtext
+
+import (
+	stdhtml "html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// hardBreak marks a <br> until the paragraph is complete; it becomes a
+// backslash at the end of the line.
+const hardBreak = "\x1f"
+
+var (
+	backtickRunRE = regexp.MustCompile("`+")
+	// Line starts that Markdown would read as a block marker
+	blockMarkerRE = regexp.MustCompile(`^(#{1,6}(?:\s|$)|>|[-+*](?:\s|$)|=+\s*$|-+\s*$)`)
+	orderedItemRE = regexp.MustCompile(`^(\d{1,9})([.)])(\s|$)`)
+)
+
+// ToMarkdown converts HTML to Markdown that markdown.ToHTML turns back into
+// equivalent HTML. Attachments are kept as <action-text-attachment> tags so
+// they survive a round trip.
+func ToMarkdown(src string) string {
+	nodes, err := parse(src)
+	if err != nil {
+		return strings.TrimSpace(src)
+	}
+	return strings.Join(markdownBlocks(nodes), "\n\n")
+}
+
+func markdownBlocks(nodes []*html.Node) []string {
+	var out []string
+	var inline strings.Builder
+	flush := func() {
+		// Line breaks at the end of a paragraph have no effect in Markdown
+		text := strings.TrimRight(tidy(inline.String()), hardBreak+"\n ")
+		text = strings.ReplaceAll(escapeLineStarts(text), hardBreak, "\\")
+		if text != "" {
+			out = append(out, text)
+		}
+		inline.Reset()
+	}
+
+	for _, n := range nodes {
+		if isBlock(n) {
+			flush()
+			if text := markdownBlock(n); text != "" {
+				out = append(out, text)
+			}
+			continue
+		}
+		inline.WriteString(markdownInline(n))
+	}
+	flush()
+	return out
+}
+
+func markdownBlock(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		text := strings.ReplaceAll(tidy(markdownChildren(n)), "\n", " ")
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", level) + " " + text
+	case atom.Pre:
+		code := strings.TrimRight(textContent(n), "\n")
+		fence := "```"
+		for _, run := range backtickRunRE.FindAllString(code, -1) {
+			if len(run) >= len(fence) {
+				fence = strings.Repeat("`", len(run)+1)
+			}
+		}
+		return fence + "\n" + code + "\n" + fence
+	case atom.Blockquote:
+		return prefixLines(strings.Join(markdownBlocks(children(n)), "\n\n"), "> ", "> ")
+	case atom.Ul, atom.Ol:
+		return markdownList(n)
+	case atom.Hr:
+		return "---"
+	default:
+		return strings.Join(markdownBlocks(children(n)), "\n\n")
+	}
+}
+
+func markdownList(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		content := strings.Join(markdownBlocks(children(c)), "\n")
+		items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+func markdownInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeMarkdown(spaceRE.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return hardBreak + "\n"
+	case atom.Strong, atom.B:
+		return wrapInline(markdownChildren(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(markdownChildren(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline(markdownChildren(n), "~~")
+	case atom.Code:
+		return codeSpan(textContent(n))
+	case atom.A:
+		text := markdownChildren(n)
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+		return "[" + text + "](" + linkDestination(href) + ")"
+	case atom.Img:
+		return "![" + escapeMarkdown(attr(n, "alt")) + "](" + linkDestination(attr(n, "src")) + ")"
+	case atom.Script, atom.Style:
+		return ""
+	}
+	if n.Data == "action-text-attachment" {
+		return attachmentHTML(n)
+	}
+	return markdownChildren(n)
+}
+
+func markdownChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlock(c) {
+			b.WriteString("\n" + markdownBlock(c) + "\n")
+			continue
+		}
+		b.WriteString(markdownInline(c))
+	}
+	return b.String()
+}
+
+// attachmentHTML writes an attachment back as a tag that refers to the
+// same upload.
+func attachmentHTML(n *html.Node) string {
+	sgid := attr(n, "sgid")
+	if sgid == "" {
+		var b strings.Builder
+		if err := html.Render(&b, n); err == nil {
+			return b.String()
+		}
+		return ""
+	}
+	attrs := `sgid="` + stdhtml.EscapeString(sgid) + `"`
+	if caption := attr(n, "caption"); caption != "" {
+		attrs += ` caption="` + stdhtml.EscapeString(caption) + `"`
+	}
+	return "<action-text-attachment " + attrs + "></action-text-attachment>"
+}
+
+// wrapInline puts a delimiter around text, keeping surrounding spaces
+// outside it so the emphasis still parses.
+func wrapInline(text, delim string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	return leading + delim + trimmed + delim + trailing
+}
+
+func codeSpan(code string) string {
+	code = spaceRE.ReplaceAllString(code, " ")
+	longest := 0
+	for _, run := range backtickRunRE.FindAllString(code, -1) {
+		longest = max(longest, len(run))
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+func linkDestination(dest string) string {
+	if strings.ContainsAny(dest, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(dest) + ">"
+	}
+	return dest
+}
+
+// EscapeMarkdown escapes characters in plain text that Markdown would read
+// as inline formatting, for text placed in a line of Markdown.
+func EscapeMarkdown(text string) string {
+	return escapeMarkdown(text)
+}
+
+// escapeMarkdown escapes characters in text that Markdown would read as
+// formatting.
+func escapeMarkdown(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch ch {
+		case '\\', '*', '`', '[', ']', '<':
+			b.WriteByte('\\')
+		case '_':
+			if !(i > 0 && isWordByte(text[i-1]) && i+1 < len(text) && isWordByte(text[i+1])) {
+				b.WriteByte('\\')
+			}
+		case '~':
+			if i+1 < len(text) && text[i+1] == '~' {
+				b.WriteByte('\\')
+			}
+		case '&':
+			if strings.Contains(text[i:min(i+34, len(text))], ";") {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// escapeLineStarts escapes text at the start of lines that would otherwise
+// begin a heading, quote, list or rule.
+func escapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if m := orderedItemRE.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + "\\" + line[len(m[1]):]
+			continue
+		}
+		if blockMarkerRE.MatchString(line) {
+			lines[i] = "\\" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
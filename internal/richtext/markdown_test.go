@@ -0,0 +1,63 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/robzolkos/fizzy-cli/internal/markdown"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"paragraphs", "<div>One</div><div>Two</div>", "One\n\nTwo"},
+		{"line breaks", "<div>One<br>Two<br></div>", "One\\\nTwo"},
+		{"wrapper div", `<div class="action-text-content"><p>Text</p></div>`, "Text"},
+		{"headings", "<h1>Title</h1><h3>Sub</h3>", "# Title\n\n### Sub"},
+		{"emphasis", "<div><strong>bold</strong>, <em>italic </em>and <del>gone</del></div>", "**bold**, *italic* and ~~gone~~"},
+		{"nested emphasis", "<div><strong>a <em>b</em></strong></div>", "**a *b***"},
+		{"link", `<div><a href="https://fizzy.do">Fizzy</a></div>`, "[Fizzy](https://fizzy.do)"},
+		{"link with spaces", `<div><a href="/a b">x</a></div>`, "[x](</a b>)"},
+		{"image", `<div><img src="https://example.com/a.png" alt="A"></div>`, "![A](https://example.com/a.png)"},
+		{"code", "<div><code>a`b</code></div>", "``a`b``"},
+		{"code block", "<pre>x := 1\n```\n</pre>", "````\nx := 1\n```\n````"},
+		{"quote", "<blockquote><p>One</p><p>Two</p></blockquote>", "> One\n>\n> Two"},
+		{"lists", `<ul><li>a<ul><li>b</li></ul></li></ul><ol start="2"><li>c</li></ol>`, "- a\n  - b\n\n2. c"},
+		{"rule", "<p>a</p><hr><p>b</p>", "a\n\n---\n\nb"},
+		{"escapes", "<div>*not* [a] snake_case _x_ &lt;b&gt;</div>", `\*not\* \[a\] snake_case \_x\_ \<b>`},
+		{"escapes line starts", "<div># one<br>2. two<br>- three</div>", "\\# one\\\n2\\. two\\\n\\- three"},
+		{"attachment", `<action-text-attachment sgid="abc" filename="a.png" caption="Shot"><figure></figure></action-text-attachment>`, `<action-text-attachment sgid="abc" caption="Shot"></action-text-attachment>`},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToMarkdown(tt.html)
+			if result != tt.expected {
+				t.Errorf("ToMarkdown(%q)\n got: %q\nwant: %q", tt.html, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToMarkdownRoundTrip(t *testing.T) {
+	sources := []string{
+		"# Plan\n\nSome **bold**, *italic* and `code` with a [link](https://fizzy.do).",
+		"- one\n- two\n  1. nested\n  2. items\n- three",
+		"> Quoted\n>\n> - with a list",
+		"```\nfunc main() {\n\t// *not emphasis*\n}\n```",
+		"Line one\\\nline two\n\n---\n\n2024\\. A year, not a list",
+		`Before <action-text-attachment sgid="abc"></action-text-attachment> after`,
+		"Escapes: \\*stars\\*, \\[brackets\\], \\<tags> and snake_case",
+	}
+
+	for _, src := range sources {
+		html := markdown.ToHTML(src, markdown.Options{})
+		back := markdown.ToHTML(ToMarkdown(html), markdown.Options{})
+		if back != html {
+			t.Errorf("round trip of %q changed the HTML\n got: %q\nwant: %q\nmarkdown: %q", src, back, html, ToMarkdown(html))
+		}
+	}
+}
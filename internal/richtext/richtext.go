@@ -0,0 +1,323 @@
+// Package richtext renders Fizzy's rich text HTML (ActionText) as text for
+// the terminal.
+package richtext
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Options control how HTML is rendered.
+type Options struct {
+	// Plain disables styling and uses ASCII markers for lists, quotes and
+	// rules, leaving only the text.
+	Plain bool
+}
+
+var (
+	plainStyle      = lipgloss.NewStyle()
+	headingStyle    = lipgloss.NewStyle().Bold(true)
+	titleStyle      = lipgloss.NewStyle().Bold(true).Underline(true)
+	codeStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	linkStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Underline(true)
+	dimStyle        = lipgloss.NewStyle().Faint(true)
+	attachmentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+)
+
+var (
+	spaceRE      = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLinesRE = regexp.MustCompile(`\n{3,}`)
+)
+
+// Render converts HTML to text with lists, quotes, code and links laid out
+// for the terminal. Attachments are shown as "[attachment: filename]".
+func Render(src string, opts Options) string {
+	nodes, err := parse(src)
+	if err != nil {
+		return strings.TrimSpace(src)
+	}
+	r := &renderer{opts: opts}
+	return strings.Join(r.blocks(nodes), "\n\n")
+}
+
+// parse parses an HTML fragment as it appears inside a document body.
+func parse(src string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+// isBlock reports whether n starts a new block of text.
+func isBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Pre, atom.Blockquote, atom.Ul, atom.Ol, atom.Hr, atom.Figure, atom.Table:
+		return true
+	}
+	return false
+}
+
+// attachmentName returns a short name for an <action-text-attachment>: its
+// filename, caption or the last part of its URL.
+func attachmentName(n *html.Node) string {
+	for _, key := range []string{"filename", "caption"} {
+		if v := attr(n, key); v != "" {
+			return v
+		}
+	}
+	if caption := findElement(n, "figcaption"); caption != nil {
+		if text := strings.TrimSpace(spaceRE.ReplaceAllString(textContent(caption), " ")); text != "" {
+			return text
+		}
+	}
+	for _, key := range []string{"url", "href"} {
+		if v := attr(n, key); v != "" {
+			return path.Base(v)
+		}
+	}
+	if img := findElement(n, "img"); img != nil {
+		if src := attr(img, "src"); src != "" {
+			return path.Base(src)
+		}
+	}
+	return "file"
+}
+
+// attr returns the value of an attribute, or "" if it is not set.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+type renderer struct {
+	opts Options
+}
+
+// blocks renders sibling nodes as a list of blocks. Runs of inline nodes
+// between block elements form paragraphs.
+func (r *renderer) blocks(nodes []*html.Node) []string {
+	var out []string
+	var inline strings.Builder
+	flush := func() {
+		if text := tidy(inline.String()); text != "" {
+			out = append(out, text)
+		}
+		inline.Reset()
+	}
+
+	for _, n := range nodes {
+		if isBlock(n) {
+			flush()
+			if text := r.block(n); text != "" {
+				out = append(out, text)
+			}
+			continue
+		}
+		inline.WriteString(r.inline(n, plainStyle))
+	}
+	flush()
+	return out
+}
+
+func (r *renderer) block(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1:
+		return tidy(r.inlineChildren(n, titleStyle))
+	case atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return tidy(r.inlineChildren(n, headingStyle))
+	case atom.Pre:
+		code := strings.TrimRight(textContent(n), "\n")
+		lines := strings.Split(code, "\n")
+		for i, line := range lines {
+			lines[i] = "    " + r.style(codeStyle, line)
+		}
+		return strings.Join(lines, "\n")
+	case atom.Blockquote:
+		marker := r.style(dimStyle, "│ ")
+		if r.opts.Plain {
+			marker = "> "
+		}
+		return prefixLines(strings.Join(r.blocks(children(n)), "\n\n"), marker, marker)
+	case atom.Ul, atom.Ol:
+		return r.list(n)
+	case atom.Hr:
+		if r.opts.Plain {
+			return "---"
+		}
+		return r.style(dimStyle, strings.Repeat("─", 40))
+	default:
+		return strings.Join(r.blocks(children(n)), "\n\n")
+	}
+}
+
+func (r *renderer) list(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		number = start
+	}
+
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "• "
+		if r.opts.Plain {
+			marker = "- "
+		}
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		content := strings.Join(r.blocks(children(c)), "\n")
+		items = append(items, prefixLines(content, marker, strings.Repeat(" ", len([]rune(marker)))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// inline renders an inline node with s, the style of its enclosing
+// elements, applied to its text.
+func (r *renderer) inline(n *html.Node, s lipgloss.Style) string {
+	switch n.Type {
+	case html.TextNode:
+		return r.style(s, spaceRE.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Strong, atom.B:
+		return r.inlineChildren(n, s.Bold(true))
+	case atom.Em, atom.I:
+		return r.inlineChildren(n, s.Italic(true))
+	case atom.Del, atom.S, atom.Strike:
+		return r.inlineChildren(n, s.Strikethrough(true))
+	case atom.U:
+		return r.inlineChildren(n, s.Underline(true))
+	case atom.Code:
+		return r.style(s.Inherit(codeStyle), textContent(n))
+	case atom.A:
+		text := r.inlineChildren(n, s.Inherit(linkStyle))
+		href := attr(n, "href")
+		label := strings.TrimSpace(textContent(n))
+		if href == "" || href == label || strings.TrimPrefix(href, "mailto:") == label {
+			return text
+		}
+		return text + r.style(s, " ("+href+")")
+	case atom.Img:
+		name := attr(n, "alt")
+		if name == "" {
+			name = path.Base(attr(n, "src"))
+		}
+		return r.style(attachmentStyle, "[image: "+name+"]")
+	case atom.Script, atom.Style:
+		return ""
+	}
+	if n.Data == "action-text-attachment" {
+		return r.style(attachmentStyle, "[attachment: "+attachmentName(n)+"]")
+	}
+	return r.inlineChildren(n, s)
+}
+
+func (r *renderer) inlineChildren(n *html.Node, s lipgloss.Style) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlock(c) {
+			b.WriteString("\n" + r.block(c) + "\n")
+			continue
+		}
+		b.WriteString(r.inline(c, s))
+	}
+	return b.String()
+}
+
+// style applies s line by line, so multi-line text isn't padded into a box.
+func (r *renderer) style(s lipgloss.Style, text string) string {
+	if r.opts.Plain || strings.TrimSpace(text) == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = s.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tidy trims the spaces left around line breaks and collapses blank lines.
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Trim(line, " ")
+	}
+	s = strings.Join(lines, "\n")
+	return strings.Trim(blankLinesRE.ReplaceAllString(s, "\n\n"), "\n")
+}
+
+// prefixLines puts first before the first line of s and rest before the
+// others, leaving blank lines without trailing spaces.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" && i > 0 {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func children(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	return nodes
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func findElement(n *html.Node, name string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == name {
+			return c
+		}
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package richtext
+
+import "testing"
+
+func TestRenderPlain(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"text", "<div>Hello world</div>", "Hello world"},
+		{"collapses whitespace", "<div>\n  Hello\n  world  </div>", "Hello world"},
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"line breaks", "<div>One<br>Two<br><br>Three<br></div>", "One\nTwo\n\nThree"},
+		{"wrapper div", `<div class="action-text-content"><div>One</div><div>Two</div></div>`, "One\n\nTwo"},
+		{"entities", "<div>a &lt; b &amp; c</div>", "a < b & c"},
+		{"emphasis", "<div><strong>bold</strong> <em>italic</em> <del>gone</del></div>", "bold italic gone"},
+		{"heading", "<h1>Title</h1><div>Body</div>", "Title\n\nBody"},
+		{"link", `<div>See <a href="https://fizzy.do/docs">the docs</a></div>`, "See the docs (https://fizzy.do/docs)"},
+		{"bare link", `<div><a href="https://fizzy.do">https://fizzy.do</a></div>`, "https://fizzy.do"},
+		{"code", "<div>Run <code>fizzy &lt;cmd&gt;</code></div>", "Run fizzy <cmd>"},
+		{"code block", "<pre>if x {\n  y()\n}\n</pre>", "    if x {\n      y()\n    }"},
+		{"quote", "<blockquote>Quoted<br>text</blockquote>", "> Quoted\n> text"},
+		{"bullet list", "<ul><li>one</li><li>two</li></ul>", "- one\n- two"},
+		{"ordered list", `<ol start="3"><li>three</li><li>four</li></ol>`, "3. three\n4. four"},
+		{"nested list", "<ul><li>one<ul><li>a</li></ul></li><li>two</li></ul>", "- one\n  - a\n- two"},
+		{"multi-line item", "<ol><li>First<br>line</li></ol>", "1. First\n   line"},
+		{"rule", "<div>Above</div><hr><div>Below</div>", "Above\n\n---\n\nBelow"},
+		{"image", `<div><img src="https://example.com/cat.png" alt="A cat"></div>`, "[image: A cat]"},
+		{"attachment", `<action-text-attachment sgid="x" filename="shot.png"></action-text-attachment>`, "[attachment: shot.png]"},
+		{"rendered attachment", `<action-text-attachment url="https://example.com/blobs/report.pdf"><figure><figcaption class="attachment__caption"><span>report.pdf</span> 12 KB</figcaption></figure></action-text-attachment>`, "[attachment: report.pdf 12 KB]"},
+		{"attachment url", `<action-text-attachment url="https://example.com/blobs/log.txt"></action-text-attachment>`, "[attachment: log.txt]"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Render(tt.html, Options{Plain: true})
+			if result != tt.expected {
+				t.Errorf("Render(%q)\n got: %q\nwant: %q", tt.html, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderMarkers(t *testing.T) {
+	// Styles are only emitted on a terminal, but the layout is the same
+	html := "<ul><li>one</li></ul><blockquote>Quoted</blockquote>"
+	expected := "• one\n\n│ Quoted"
+	if result := Render(html, Options{}); result != expected {
+		t.Errorf("got: %q\nwant: %q", result, expected)
+	}
+}